@@ -0,0 +1,81 @@
+// alphatable.go
+//
+// Temperature-dependent compensation coefficient.
+//
+// Conductivity temperature coefficients drift with temperature, so one AlphaPerC
+// is only an approximation over a wide range. AlphaTable lists alpha at a few
+// temperatures ("10:0.0215, 20:0.0200, 30:0.0190"); the alpha used for a reading
+// is interpolated linearly at the current temperature and held at the end values
+// outside the table. Without a table the scalar AlphaPerC applies.
+//
+package ads1115tds
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type alphaPoint struct {
+	tempC float64
+	alpha float64
+}
+
+// parseAlphaTable parses "T:alpha" pairs separated by commas. Temperatures must
+// be strictly increasing and every alpha within the AlphaPerC bounds. An empty
+// string is no table.
+func parseAlphaTable(s string) ([]alphaPoint, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	var out []alphaPoint
+	for _, pair := range strings.Split(s, ",") {
+		tStr, aStr, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			return nil, fmt.Errorf("entry %q must be tempC:alpha", strings.TrimSpace(pair))
+		}
+		t, err := strconv.ParseFloat(strings.TrimSpace(tStr), 64)
+		if err != nil || math.IsNaN(t) || math.IsInf(t, 0) {
+			return nil, fmt.Errorf("entry %q: bad temperature", strings.TrimSpace(pair))
+		}
+		a, err := strconv.ParseFloat(strings.TrimSpace(aStr), 64)
+		if err != nil || math.IsNaN(a) || a < minAlphaPerC || a > maxAlphaPerC {
+			return nil, fmt.Errorf("entry %q: alpha must be %g..%g", strings.TrimSpace(pair), minAlphaPerC, maxAlphaPerC)
+		}
+		if n := len(out); n > 0 && t <= out[n-1].tempC {
+			return nil, fmt.Errorf("temperatures must be strictly increasing (%.2f after %.2f)", t, out[n-1].tempC)
+		}
+		out = append(out, alphaPoint{tempC: t, alpha: a})
+	}
+	return out, nil
+}
+
+// alphaAt returns the alpha for tempC: interpolated from table when one is set,
+// otherwise scalar.
+func alphaAt(table []alphaPoint, tempC, scalar float64) float64 {
+	switch {
+	case len(table) == 0:
+		return scalar
+	case tempC <= table[0].tempC:
+		return table[0].alpha
+	case tempC >= table[len(table)-1].tempC:
+		return table[len(table)-1].alpha
+	}
+	for i := 1; i < len(table); i++ {
+		if tempC <= table[i].tempC {
+			lo, hi := table[i-1], table[i]
+			return lo.alpha + (hi.alpha-lo.alpha)*(tempC-lo.tempC)/(hi.tempC-lo.tempC)
+		}
+	}
+	return table[len(table)-1].alpha
+}
+
+func formatAlphaTable(table []alphaPoint) string {
+	parts := make([]string, 0, len(table))
+	for _, p := range table {
+		parts = append(parts, fmt.Sprintf("%g:%g", p.tempC, p.alpha))
+	}
+	return strings.Join(parts, ",")
+}
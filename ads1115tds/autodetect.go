@@ -0,0 +1,47 @@
+// autodetect.go
+//
+// AutoDetectAddress: find the ADS1115 among the four addresses its ADDR pin
+// can select (GND 0x48, VDD 0x49, SDA 0x4A, SCL 0x4B).
+//
+// Each address is probed with a config register read; the chip answers
+// whether or not it is converting. Exactly one address must answer: with
+// none there is nothing to drive, and with several the driver can't tell
+// which board is meant, so Address has to be set by hand.
+//
+package ads1115tds
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/reef-pi/rpi/i2c"
+)
+
+// Addresses selectable with the ADDR pin.
+var ads1115Addresses = []byte{0x48, 0x49, 0x4A, 0x4B}
+
+// detectAddress returns the only ADS1115 address on bus that answers a
+// config register read.
+func detectAddress(bus i2c.Bus) (byte, error) {
+	var found []byte
+	for _, a := range ads1115Addresses {
+		b := make([]byte, 2)
+		if err := bus.ReadFromReg(a, regConfig, b); err == nil {
+			found = append(found, a)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return 0, fmt.Errorf("ads1115tds: AutoDetectAddress: no ADS1115 answered at 0x48..0x4B")
+	case 1:
+		return found[0], nil
+	default:
+		s := make([]string, len(found))
+		for i, a := range found {
+			s[i] = fmt.Sprintf("0x%02X", a)
+		}
+		return 0, fmt.Errorf("ads1115tds: AutoDetectAddress: several devices answered (%s); set Address instead",
+			strings.Join(s, ", "))
+	}
+}
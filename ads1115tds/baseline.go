@@ -0,0 +1,122 @@
+// baseline.go
+//
+// OutputMode "delta": report the change from a baseline instead of absolute TDS.
+//
+// For change-detection sensors (turbidity and the like) the absolute value
+// means little. The baseline is captured by SetBaseline, or automatically from
+// the first stable stretch of readings: baselineStableN consecutive outputs
+// within baselineStableTol of their mean. Until then the primary value is 0.
+// Calibration changes the output scale, so it drops the baseline.
+//
+package ads1115tds
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	outputAbsolute = "absolute"
+	outputDelta    = "delta"
+
+	// Auto-baseline: this many consecutive outputs, all within
+	// baselineStableTol (relative to |mean|, at least baselineStableAbs).
+	baselineStableN   = 5
+	baselineStableTol = 0.01
+	baselineStableAbs = 0.001
+)
+
+type baselineTracker struct {
+	mu     sync.Mutex
+	value  float64
+	set    bool
+	at     time.Time
+	recent []float64
+	next   int
+}
+
+// observe feeds an output; while no baseline is set it captures one from the
+// first stable run of outputs.
+func (b *baselineTracker) observe(v float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.set {
+		return
+	}
+	if len(b.recent) < baselineStableN {
+		b.recent = append(b.recent, v)
+	} else {
+		b.recent[b.next] = v
+		b.next = (b.next + 1) % baselineStableN
+	}
+	if len(b.recent) < baselineStableN {
+		return
+	}
+
+	lo, hi, sum := math.Inf(1), math.Inf(-1), 0.0
+	for _, x := range b.recent {
+		lo, hi, sum = math.Min(lo, x), math.Max(hi, x), sum+x
+	}
+	mean := sum / float64(len(b.recent))
+	if hi-lo <= math.Max(baselineStableTol*math.Abs(mean), baselineStableAbs) {
+		b.value, b.set, b.at = mean, true, time.Now()
+	}
+}
+
+// capture sets the baseline to v.
+func (b *baselineTracker) capture(v float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.value, b.set, b.at = v, true, time.Now()
+}
+
+// reset drops the baseline; the next stable run captures a new one.
+func (b *baselineTracker) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.value, b.set, b.at = 0, false, time.Time{}
+	b.recent, b.next = b.recent[:0], 0
+}
+
+// get returns the baseline and when it was captured (zero if none).
+func (b *baselineTracker) get() (value float64, set bool, at time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.value, b.set, b.at
+}
+
+// output turns the smoothed absolute output into the primary value for the
+// configured OutputMode.
+func (c *tdsChannel) output(abs float64) float64 {
+	if c.outputMode != outputDelta {
+		return abs
+	}
+	c.baseline.observe(abs)
+	base, set, _ := c.baseline.get()
+	if !set {
+		return 0
+	}
+	return abs - base
+}
+
+// SetBaseline takes a fresh reading and uses it as the delta baseline.
+// It works in either OutputMode; only "delta" reports against it.
+func (c *tdsChannel) SetBaseline() error {
+	r, err := c.measure(nil)
+	if err != nil {
+		return err
+	}
+	c.baseline.capture(r.Smoothed)
+	return nil
+}
+
+// SetBaseline captures a new baseline on every channel.
+func (d *Driver) SetBaseline() error {
+	for _, p := range d.pins {
+		if err := p.SetBaseline(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// calfit.go
+//
+// Least-squares calibration fit over N points (see internal/linfit).
+//
+// Two points define TdsK/TdsOffset exactly; with three or more the line is a
+// least-squares fit and the residuals show whether the calibration standards
+// agree with each other. R² and the largest residual are stored with the
+// points and reported in Snapshot meta; a poor fit is logged and flagged.
+//
+package ads1115tds
+
+import (
+	"fmt"
+
+	"github.com/reef-pi/drivers/internal/linfit"
+)
+
+const (
+	// maxCalPoints bounds one Calibrate call.
+	maxCalPoints = 8
+
+	// Fits over 3+ points with R² below this are flagged: at least one
+	// standard (or its reading) disagrees with the others.
+	calMinR2 = 0.995
+)
+
+// calFit is the goodness of fit of the current calibration.
+type calFit struct {
+	R2          float64 `json:"r2"`
+	MaxResidual float64 `json:"max_residual"` // largest |expected - fitted|, TDS units
+}
+
+// fitLinear fits y = k*x + off by least squares. xs must not all be equal.
+func fitLinear(xs, ys []float64) (k, off float64, err error) {
+	k, off, err = linfit.Fit(xs, ys)
+	if err != nil {
+		return 0, 0, fmt.Errorf("calibration points have the same volts (%.6f); use different solutions", xs[0])
+	}
+	return k, off, nil
+}
+
+// fitStats returns the residuals (y - fitted), R² and the largest |residual|.
+// R² is 1 when ys have no spread (nothing to explain).
+func fitStats(xs, ys []float64, k, off float64) (residuals []float64, fit calFit) {
+	residuals, q := linfit.Residuals(xs, ys, k, off)
+	return residuals, calFit{R2: q.R2, MaxResidual: q.MaxResidual}
+}
+
+// calFitWarning flags a 3+ point fit whose standards disagree.
+func calFitWarning(points []calPoint, fit calFit) string {
+	if len(points) < 3 || fit.R2 >= calMinR2 {
+		return ""
+	}
+	return fmt.Sprintf("calibration fit R²=%.4f (max residual %.2f) over %d points; one of the standards or its reading is off, check the per-point residuals.",
+		fit.R2, fit.MaxResidual, len(points))
+}
@@ -0,0 +1,114 @@
+// channels.go
+//
+// ChannelsSpec: several ADS1115 channels from one driver instance.
+//
+// The parameter is a JSON array, one object per channel:
+//
+//	[{"ch":0,"gain":"1","tdsK":2.3},{"ch":2,"gain":"4","unit":"NTU"}]
+//
+// Only "ch" is required. Omitted fields take the driver-wide value (Gain,
+// TdsK, TdsOffset, ClampV, UnitLabel); everything else (temperature
+// compensation, continuous mode, conversion timing) is shared by all entries.
+// Channels on the same chip are serialized through the shared *chip.
+//
+package ads1115tds
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/reef-pi/rpi/i2c"
+)
+
+type channelSpec struct {
+	Ch        *int     `json:"ch"`
+	Gain      *string  `json:"gain"`
+	TdsK      *float64 `json:"tdsK"`
+	TdsOffset *float64 `json:"tdsOffset"`
+	ClampV    *float64 `json:"clampV"`
+	Unit      *string  `json:"unit"`
+
+	gain uint16 // parsed Gain, valid when Gain != nil
+}
+
+// parseChannelsSpec reads and validates ChannelsSpec. An absent or empty
+// parameter yields no specs and no error.
+func parseChannelsSpec(parameters map[string]interface{}) ([]channelSpec, error) {
+	v, ok := getAny(parameters, paramChannelsSpec, "channelsspec", "channels_spec")
+	if !ok {
+		return nil, nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("must be a JSON array string, got %T", v)
+	}
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.DisallowUnknownFields()
+	var specs []channelSpec
+	if err := dec.Decode(&specs); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("must list at least one channel")
+	}
+
+	seen := map[int]bool{}
+	for i := range specs {
+		sp := &specs[i]
+		if sp.Ch == nil {
+			return nil, fmt.Errorf("entry %d: missing \"ch\"", i)
+		}
+		if _, ok := muxForChannel(*sp.Ch); !ok {
+			return nil, fmt.Errorf("entry %d: ch %d must be 0..3", i, *sp.Ch)
+		}
+		if seen[*sp.Ch] {
+			return nil, fmt.Errorf("entry %d: ch %d listed twice", i, *sp.Ch)
+		}
+		seen[*sp.Ch] = true
+		if sp.Gain != nil {
+			g, err := parseGain(*sp.Gain)
+			if err != nil {
+				return nil, fmt.Errorf("entry %d: %v", i, err)
+			}
+			sp.gain = g
+		}
+		if sp.ClampV != nil && (*sp.ClampV <= 0 || *sp.ClampV > 6.0) {
+			return nil, fmt.Errorf("entry %d: clampV must be >0 and <=6.0", i)
+		}
+	}
+	return specs, nil
+}
+
+// newChannel builds the channel for s, taking omitted fields from base (the
+// channel built from the driver-wide parameters).
+func (s channelSpec) newChannel(bus i2c.Bus, addr byte, base *tdsChannel, parameters map[string]interface{}) *tdsChannel {
+	mux, _ := muxForChannel(*s.Ch)
+	gain, tdsK, tdsOff, clampV := base.gainConfig, base.tdsK, base.tdsOffset, base.clampV
+	if s.Gain != nil {
+		gain = s.gain
+	}
+	if s.TdsK != nil {
+		tdsK = *s.TdsK
+	}
+	if s.TdsOffset != nil {
+		tdsOff = *s.TdsOffset
+	}
+	if s.ClampV != nil {
+		clampV = *s.ClampV
+	}
+	alpha, refTempC := base.tempComp()
+
+	c := newTdsChannel(bus, addr, *s.Ch, mux, gain, tdsK, tdsOff, clampV,
+		alpha, base.doTempComp, refTempC, base.continuous, base.debug, base.meta)
+	applyChannelOptions(c, parameters)
+	if s.Unit != nil {
+		c.unitLabel = strings.TrimSpace(*s.Unit)
+	}
+	return c
+}
@@ -0,0 +1,65 @@
+package ads1115tds
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseChannelsSpec(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		spec  interface{}
+		chans []int
+		err   string // substring of the expected error, "" = valid
+	}{
+		{name: "absent", spec: nil},
+		{name: "empty string", spec: "  "},
+		{name: "one channel", spec: `[{"ch":0}]`, chans: []int{0}},
+		{name: "all fields", spec: `[{"ch":1,"gain":"4","tdsK":2.3,"tdsOffset":-1,"clampV":3.3,"unit":"NTU"}]`, chans: []int{1}},
+		{name: "four channels", spec: `[{"ch":3},{"ch":0},{"ch":2},{"ch":1}]`, chans: []int{3, 0, 2, 1}},
+		{name: "duplicate channel", spec: `[{"ch":1},{"ch":2},{"ch":1}]`, err: "entry 2: ch 1 listed twice"},
+		{name: "channel above range", spec: `[{"ch":4}]`, err: "ch 4 must be 0..3"},
+		{name: "negative channel", spec: `[{"ch":0},{"ch":-1}]`, err: "entry 1: ch -1 must be 0..3"},
+		{name: "missing ch", spec: `[{"gain":"1"}]`, err: `missing "ch"`},
+		{name: "empty array", spec: `[]`, err: "at least one channel"},
+		{name: "unknown field", spec: `[{"ch":0,"chan":1}]`, err: "invalid JSON"},
+		{name: "bad gain", spec: `[{"ch":0,"gain":"x"}]`, err: "entry 0: Gain must be one of"},
+		{name: "bad clampV", spec: `[{"ch":0,"clampV":7}]`, err: "clampV must be"},
+		{name: "not a string", spec: 42, err: "JSON array string"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			params := map[string]interface{}{}
+			if tc.spec != nil {
+				params[paramChannelsSpec] = tc.spec
+			}
+			specs, err := parseChannelsSpec(params)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("got %v, want an error containing %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(specs) != len(tc.chans) {
+				t.Fatalf("got %d specs, want %d", len(specs), len(tc.chans))
+			}
+			for i, ch := range tc.chans {
+				if *specs[i].Ch != ch {
+					t.Errorf("spec %d: ch %d, want %d", i, *specs[i].Ch, ch)
+				}
+			}
+		})
+	}
+}
+
+func TestChannelsSpecGainParsed(t *testing.T) {
+	specs, err := parseChannelsSpec(map[string]interface{}{paramChannelsSpec: `[{"ch":2,"gain":"16"}]`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if specs[0].gain != configGainSixteen {
+		t.Errorf("gain 0x%04X, want 0x%04X", specs[0].gain, configGainSixteen)
+	}
+}
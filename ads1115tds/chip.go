@@ -0,0 +1,133 @@
+// chip.go
+//
+// Shared ADS1115 chip state.
+//
+// Each reef-pi driver instance owns one channel, but several instances can point
+// at the same physical ADS1115 (same bus + address). They share one *chip so that:
+//   - conversions are serialized (a mux change from one channel can't land in the
+//     middle of another channel's conversion)
+//   - the config word is built once; per read only the mux bits change
+//   - in continuous mode the config write is skipped when nothing changed
+//   - a cooperating driver reading an NTC on another channel can publish its
+//     temperature here, and a TDS channel can use it for compensation
+//
+package ads1115tds
+
+import (
+	"sync"
+	"time"
+
+	"github.com/reef-pi/rpi/i2c"
+)
+
+// Continuous mode: after a config change the first result is only valid after
+// a full conversion (~1.2ms @860SPS). Wait two periods to be safe.
+const contSettle = 3 * time.Millisecond
+
+type chipKey struct {
+	bus  i2c.Bus
+	addr byte
+}
+
+type chip struct {
+	// mu serializes the full write -> wait -> read sequence on this chip.
+	mu sync.Mutex
+
+	// Cached base config (everything except mux / OS bit) and the inputs it was built from.
+	base           uint16
+	baseGain       uint16
+	baseContinuous bool
+	baseValid      bool
+
+	// lastConfig is the config word a continuous channel left running; any
+	// other config write (single-shot) invalidates it.
+	lastConfig      uint16
+	lastConfigValid bool
+
+	// lastMux is the input of the last conversion, to spot mux changes (see warmup.go).
+	lastMux      uint16
+	lastMuxValid bool
+
+	// temps holds temperatures published per channel (see PublishTemperatureC).
+	// Separate lock so a publish never waits on a conversion.
+	tempMu sync.Mutex
+	temps  map[int]chipTemp
+}
+
+type chipTemp struct {
+	tempC float64
+	at    time.Time
+}
+
+var (
+	chipsMu sync.Mutex
+	chips   = map[chipKey]*chip{}
+)
+
+// chipFor returns the shared state for the ADS1115 at addr on bus.
+func chipFor(bus i2c.Bus, addr byte) *chip {
+	chipsMu.Lock()
+	defer chipsMu.Unlock()
+
+	k := chipKey{bus: bus, addr: addr}
+	if c, ok := chips[k]; ok {
+		return c
+	}
+	c := &chip{}
+	chips[k] = c
+	return c
+}
+
+// PublishTemperatureC records a temperature (°C) read from channel ch of the
+// ADS1115 at addr on bus, e.g. by a driver that owns an NTC on that channel.
+// TDS channels configured with TempChannel=ch use it for compensation.
+func PublishTemperatureC(bus i2c.Bus, addr byte, ch int, tempC float64) {
+	chipFor(bus, addr).publishTemperature(ch, tempC)
+}
+
+// ChipLock returns the lock that serializes conversions on the ADS1115 at addr
+// on bus. Cooperating drivers should hold it around their own write/read sequence.
+func ChipLock(bus i2c.Bus, addr byte) sync.Locker {
+	return &chipFor(bus, addr).mu
+}
+
+func (c *chip) publishTemperature(ch int, tempC float64) {
+	c.tempMu.Lock()
+	defer c.tempMu.Unlock()
+	if c.temps == nil {
+		c.temps = map[int]chipTemp{}
+	}
+	c.temps[ch] = chipTemp{tempC: tempC, at: time.Now()}
+}
+
+// temperature returns the last temperature published for ch.
+func (c *chip) temperature(ch int) (tempC float64, at time.Time, ok bool) {
+	c.tempMu.Lock()
+	defer c.tempMu.Unlock()
+	t, ok := c.temps[ch]
+	return t.tempC, t.at, ok
+}
+
+// configFor returns the config word for mux. Caller holds c.mu.
+// The base (gain, data rate, mode, comparator) is rebuilt only when gain or mode changes.
+func (c *chip) configFor(mux, gain uint16, continuous bool) uint16 {
+	if !c.baseValid || c.baseGain != gain || c.baseContinuous != continuous {
+		base := configComparatorModeTraditional |
+			configComparitorNonLatching |
+			configComparitorPolarityActiveLow |
+			configComparitorQueueNone |
+			gain |
+			configDataRate860
+		if !continuous {
+			base |= configModeSingle
+		}
+		c.base, c.baseGain, c.baseContinuous, c.baseValid = base, gain, continuous, true
+	}
+
+	cfg := c.base | mux
+	if !continuous {
+		// Single-shot: setting OS starts the conversion.
+		cfg |= configOsSingle
+	}
+	return cfg
+}
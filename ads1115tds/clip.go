@@ -0,0 +1,103 @@
+// clip.go
+//
+// High-side clipping detection.
+//
+// A reading clips when the ADC saturates (raw at +full scale) or volts exceed
+// ClampV. One clipped sample can be a spike; a run of them means the selected
+// gain or ClampV truncates the real signal. That is logged as a warning on the
+// normal read path (not only in debug or Snapshot), throttled to once per
+// clipWarnEvery so a stuck probe does not flood the journal.
+//
+// FailOnClamp is the strict alternative for setup and QA: a reading that
+// would clamp high or low (or saturate the ADC) fails Measure with
+// ErrOutOfRange and the unclamped volts instead of being silently limited.
+//
+package ads1115tds
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrOutOfRange is returned (wrapped) with FailOnClamp when a reading would be
+// clamped.
+var ErrOutOfRange = errors.New("ads1115: input out of range")
+
+const (
+	// Consecutive clipped readings before warning.
+	clipWarnStreak = 3
+
+	// Minimum spacing between clip warnings per channel.
+	clipWarnEvery = 10 * time.Minute
+)
+
+type clipTracker struct {
+	mu       sync.Mutex
+	streak   int
+	lastWarn time.Time
+}
+
+// observe records one reading and reports whether a warning is due now.
+func (ct *clipTracker) observe(clipped bool, now time.Time) (warn bool, streak int) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	if !clipped {
+		ct.streak = 0
+		return false, 0
+	}
+	ct.streak++
+	if ct.streak < clipWarnStreak || (!ct.lastWarn.IsZero() && now.Sub(ct.lastWarn) < clipWarnEvery) {
+		return false, ct.streak
+	}
+	ct.lastWarn = now
+	return true, ct.streak
+}
+
+func (ct *clipTracker) current() int {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	return ct.streak
+}
+
+// checkClip tracks high-side clipping for raw/voltsRaw and logs a throttled
+// warning with the likely fix.
+func (c *tdsChannel) checkClip(raw int16, voltsRaw float64, gain uint16) {
+	saturated := raw == math.MaxInt16
+	clipped := saturated || voltsRaw >= c.clampV
+	warn, streak := c.clip.observe(clipped, time.Now())
+	if !warn {
+		return
+	}
+
+	if saturated {
+		log.Printf("ads1115tds addr=0x%02X ch=%d WARNING: ADC saturated at full scale (gain %s) for %d readings; the signal exceeds the selected range. Use a lower gain (larger full scale).",
+			c.address, c.channel, gainLabel(gain), streak)
+		return
+	}
+	log.Printf("ads1115tds addr=0x%02X ch=%d WARNING: volts clamped at ClampV=%.3fV for %d readings; readings are truncated. Raise ClampV to match the sensor output.",
+		c.address, c.channel, c.clampV, streak)
+}
+
+// clampError returns the FailOnClamp error for a conversion, nil when
+// FailOnClamp is off or nothing was clamped.
+func (c *tdsChannel) clampError(raw int16, gain uint16, clampedHigh, clampedLow bool) error {
+	if !c.failOnClamp {
+		return nil
+	}
+	fs, _ := fsVoltsForGain(gain)
+	volts := float64(raw) / 32768.0 * fs
+	switch {
+	case raw == math.MaxInt16:
+		return fmt.Errorf("%w: over-range, ADC saturated at full scale %.3fV (gain %s, raw=%d); use a lower gain",
+			ErrOutOfRange, fs, gainLabel(gain), raw)
+	case clampedHigh:
+		return fmt.Errorf("%w: over-range, %.4fV above ClampV=%.3fV (raw=%d)", ErrOutOfRange, volts, c.clampV, raw)
+	case clampedLow:
+		return fmt.Errorf("%w: under-range, %.4fV below 0V (raw=%d, NegativeRawPolicy=%s)", ErrOutOfRange, volts, raw, c.negRawPolicy)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// conversion.go
+//
+// Pure conversion math: raw counts -> volts_raw -> volts_ref -> TDS (or, with
+// CompOrder=linear-then-normalize, raw counts -> volts_raw -> TDS_T -> TDS).
+//
+// No I/O, locks, logging or trace formatting, so it can be unit-tested and
+// benchmarked without hardware. measure converts every reading through
+// computeTDS and adds the debug trace (traceVolts, traceTDS) around it.
+//
+package ads1115tds
+
+// tdsParams is the configuration computeTDS needs, copied out of a channel.
+type tdsParams struct {
+	fs           float64 // full-scale volts for the gain (fsVoltsForGain)
+	clampV       float64
+	negRawPolicy string
+
+	doTempComp bool
+	compOrder  string
+	tempC      float64
+	alpha      float64
+	refTempC   float64
+
+	k, off float64
+}
+
+// computeTDS converts one raw conversion result to a Reading (TakenAt unset).
+func computeTDS(raw int16, p tdsParams) Reading {
+	voltsRaw, _, _ := voltsFromRaw(raw, p.fs, p.clampV, p.negRawPolicy)
+	if p.doTempComp && p.compOrder == compLinearFirst {
+		return Reading{
+			Raw:      raw,
+			VoltsRaw: voltsRaw,
+			VoltsRef: voltsRaw,
+			Value:    tempNormalize(p.k*voltsRaw+p.off, p.tempC, p.alpha, p.refTempC),
+		}
+	}
+	voltsRef := voltsRaw
+	if p.doTempComp {
+		voltsRef = tempNormalize(voltsRaw, p.tempC, p.alpha, p.refTempC)
+	}
+	return Reading{
+		Raw:      raw,
+		VoltsRaw: voltsRaw,
+		VoltsRef: voltsRef,
+		Value:    p.k*voltsRef + p.off,
+	}
+}
+
+// voltsFromRaw scales raw counts to volts, clamps to clampV and handles
+// negatives per NegativeRawPolicy (default: clamp to 0 for single-ended use).
+// The ADS1115 code range is -32768..32767 for full scale; dividing by 32768
+// maps -32768 to -FS and 32767 to FS - 1 LSB.
+func voltsFromRaw(raw int16, fs, clampV float64, negRawPolicy string) (volts float64, clampedHigh, clampedLow bool) {
+	volts = (float64(raw) / 32768.0) * fs
+
+	// Clamp for single-ended expectation.
+	// If wiring is truly AINx vs GND and inputs are within range, raw should typically be >= 0.
+	if volts > clampV {
+		volts, clampedHigh = clampV, true
+	}
+	if volts < 0 {
+		switch negRawPolicy {
+		case negRawPassthrough:
+		case negRawReflect:
+			volts = -volts
+			if volts > clampV {
+				volts = clampV
+			}
+		default:
+			volts, clampedLow = 0, true
+		}
+	}
+	return volts, clampedHigh, clampedLow
+}
@@ -0,0 +1,508 @@
+package ads1115tds
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComputeTDS(t *testing.T) {
+	p := tdsParams{fs: 4.096, clampV: 3.3, negRawPolicy: negRawClamp, k: 500, off: 10}
+
+	// 8192 counts at 4.096V full scale = 1.024V
+	if r := computeTDS(8192, p); math.Abs(r.VoltsRaw-1.024) > 1e-9 || math.Abs(r.Value-522) > 1e-9 {
+		t.Errorf("unexpected reading %+v", r)
+	}
+	if r := computeTDS(32767, p); r.VoltsRaw != 3.3 {
+		t.Errorf("expected clamp to 3.3V, got %v", r.VoltsRaw)
+	}
+	if r := computeTDS(-8192, p); r.VoltsRaw != 0 {
+		t.Errorf("expected negative clamped to 0, got %v", r.VoltsRaw)
+	}
+
+	p.doTempComp, p.tempC, p.alpha, p.refTempC = true, 35, 0.02, 25
+	if r := computeTDS(8192, p); math.Abs(r.VoltsRef-1.024/1.2) > 1e-9 {
+		t.Errorf("expected volts@25C %v, got %v", 1.024/1.2, r.VoltsRef)
+	}
+
+	// linear-then-normalize: (500*1.024 + 10) / 1.2, offset is scaled too.
+	p.compOrder = compLinearFirst
+	if r := computeTDS(8192, p); r.VoltsRef != r.VoltsRaw || math.Abs(r.Value-522/1.2) > 1e-9 {
+		t.Errorf("linear-then-normalize: unexpected reading %+v", r)
+	}
+}
+
+func TestFitLinear(t *testing.T) {
+	xs := []float64{0.5, 1.0, 1.5}
+	ys := []float64{260, 510, 760} // exactly 500*x + 10
+	k, off, err := fitLinear(xs, ys)
+	if err != nil || math.Abs(k-500) > 1e-9 || math.Abs(off-10) > 1e-9 {
+		t.Fatalf("fitLinear = %v, %v, %v; want 500, 10", k, off, err)
+	}
+	if _, fit := fitStats(xs, ys, k, off); math.Abs(fit.R2-1) > 1e-12 || fit.MaxResidual > 1e-9 {
+		t.Errorf("exact fit stats %+v", fit)
+	}
+
+	// A bad middle standard shows up as a residual and a low R².
+	ys[1] = 600
+	k, off, _ = fitLinear(xs, ys)
+	res, fit := fitStats(xs, ys, k, off)
+	if fit.R2 >= calMinR2 || math.Abs(res[1]) != fit.MaxResidual {
+		t.Errorf("bad standard not flagged: residuals=%v fit=%+v", res, fit)
+	}
+	if calFitWarning(make([]calPoint, 3), fit) == "" {
+		t.Error("expected a fit warning")
+	}
+
+	if _, _, err := fitLinear([]float64{1, 1}, []float64{100, 200}); err == nil {
+		t.Error("equal volts accepted")
+	}
+}
+
+func BenchmarkComputeTDS(b *testing.B) {
+	p := tdsParams{fs: 4.096, clampV: 3.3, negRawPolicy: negRawClamp,
+		doTempComp: true, tempC: 26.5, alpha: 0.02, refTempC: 25, k: 500, off: 10}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		computeTDS(int16(i), p)
+	}
+}
+
+// fixedBus answers every conversion with 0x2000 counts and a ready OS bit.
+type fixedBus struct{}
+
+func (fixedBus) SetAddress(byte) error               { return nil }
+func (fixedBus) ReadBytes(byte, int) ([]byte, error) { return nil, nil }
+func (fixedBus) WriteBytes(byte, []byte) error       { return nil }
+func (fixedBus) WriteToReg(byte, byte, []byte) error { return nil }
+func (fixedBus) Close() error                        { return nil }
+func (fixedBus) ReadFromReg(_, reg byte, b []byte) error {
+	b[0], b[1] = 0x80, 0x00
+	if reg == regConversion {
+		b[0] = 0x20
+	}
+	return nil
+}
+
+// BenchmarkMeasure covers the per-read hot path with debug off, where no
+// debug lines may be formatted.
+func BenchmarkMeasure(b *testing.B) {
+	c := newTdsChannel(fixedBus{}, 0x48, 0, configMuxSingle0, configGainOne, 500, 0, 3.3,
+		defaultAlphaPerC, true, 25, false, false, Factory().Metadata())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Measure(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// busyBus never reports a finished conversion.
+type busyBus struct{ fixedBus }
+
+func (busyBus) ReadFromReg(_, _ byte, b []byte) error {
+	b[0], b[1] = 0x00, 0x00
+	return nil
+}
+
+func TestStatsCountReadsAndFailures(t *testing.T) {
+	c := newTdsChannel(fixedBus{}, 0x49, 0, configMuxSingle0, configGainOne, 500, 0, 0.5,
+		defaultAlphaPerC, false, 25, false, false, Factory().Metadata())
+	for i := 0; i < 2; i++ {
+		if _, err := c.Measure(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 0x2000 counts = 1.024V, above ClampV=0.5
+	if s := c.Stats(); s.Reads != 2 || s.ClampHigh != 2 || s.Errors != 0 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+
+	c = newTdsChannel(busyBus{}, 0x4A, 0, configMuxSingle0, configGainOne, 500, 0, 3.3,
+		defaultAlphaPerC, false, 25, false, false, Factory().Metadata())
+	c.convTimeout, c.pollWait = time.Millisecond, 100*time.Microsecond
+	if _, err := c.Measure(); !errors.Is(err, ErrConversionTimeout) {
+		t.Fatalf("expected ErrConversionTimeout, got %v", err)
+	}
+	if s := c.Stats(); s.Errors != 1 || s.Timeouts != 1 || s.I2CErrors != 0 || s.LastError == "" {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+// probeBus answers config reads only at the listed addresses.
+type probeBus struct {
+	fixedBus
+	present map[byte]bool
+}
+
+func (b probeBus) ReadFromReg(addr, reg byte, buf []byte) error {
+	if !b.present[addr] {
+		return errors.New("nack")
+	}
+	return b.fixedBus.ReadFromReg(addr, reg, buf)
+}
+
+func TestDetectAddress(t *testing.T) {
+	if a, err := detectAddress(probeBus{present: map[byte]bool{0x4A: true}}); err != nil || a != 0x4A {
+		t.Errorf("one chip: got 0x%02X, %v; want 0x4A", a, err)
+	}
+	if _, err := detectAddress(probeBus{}); err == nil {
+		t.Error("no chip: want error")
+	}
+	if _, err := detectAddress(probeBus{present: map[byte]bool{0x48: true, 0x4B: true}}); err == nil {
+		t.Error("two chips: want error")
+	}
+	// Addresses outside 0x48..0x4B are not probed.
+	if _, err := detectAddress(probeBus{present: map[byte]bool{0x40: true}}); err == nil {
+		t.Error("chip at 0x40: want error")
+	}
+}
+
+func TestSmoothingValueOnly(t *testing.T) {
+	for _, spec := range []string{"", "none", " None "} {
+		if f, err := parseSmoothing(spec); f != nil || err != nil {
+			t.Errorf("parseSmoothing(%q) = %v, %v; want off", spec, f, err)
+		}
+	}
+	if _, err := parseSmoothing("median"); err == nil {
+		t.Error("parseSmoothing(median): want error")
+	}
+
+	c := newTdsChannel(fixedBus{}, 0x4A, 0, configMuxSingle0, configGainOne, 500, 0, 3.3,
+		defaultAlphaPerC, false, 25, false, false, Factory().Metadata())
+	c.smooth, _ = parseSmoothing("mean:2")
+	c.smoothSpec = "mean:2"
+
+	r, err := c.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The filter only sees outputs; volts (the calibration signal) is untouched.
+	c.lastMu.Lock()
+	got := c.smoothLocked(r.Value + 10)
+	c.lastMu.Unlock()
+	if want := r.Value + 5; math.Abs(got-want) > 1e-9 {
+		t.Errorf("smoothed %v, want %v", got, want)
+	}
+	if r.Smoothed != r.Value || r.VoltsRef != r.VoltsRaw {
+		t.Errorf("unexpected reading %+v", r)
+	}
+}
+
+func TestDeltaOutputMode(t *testing.T) {
+	c := newTdsChannel(fixedBus{}, 0x4B, 0, configMuxSingle0, configGainOne, 500, 0, 3.3,
+		defaultAlphaPerC, false, 25, false, false, Factory().Metadata())
+	c.outputMode = outputDelta
+
+	// Unstable readings never set a baseline.
+	for _, v := range []float64{100, 120, 100, 120, 100, 120} {
+		if got := c.output(v); got != 0 {
+			t.Fatalf("output(%v) before baseline = %v, want 0", v, got)
+		}
+	}
+	// A stable run does.
+	for i := 0; i < baselineStableN; i++ {
+		c.output(200)
+	}
+	if got := c.output(230); got != 30 {
+		t.Errorf("delta after auto baseline = %v, want 30", got)
+	}
+
+	// SetBaseline re-captures from a fresh reading (0x2000 counts -> 1.024V -> 512).
+	if err := c.SetBaseline(); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := c.Measure(); err != nil || math.Abs(v) > 1e-9 {
+		t.Errorf("Measure after SetBaseline = %v, %v; want 0", v, err)
+	}
+}
+
+func TestFailOnClamp(t *testing.T) {
+	// 0x2000 counts = 1.024V, above ClampV=0.5
+	c := newTdsChannel(fixedBus{}, 0x4B, 0, configMuxSingle0, configGainOne, 500, 0, 0.5,
+		defaultAlphaPerC, false, 25, false, false, Factory().Metadata())
+	c.failOnClamp = true
+	_, err := c.Measure()
+	if !errors.Is(err, ErrOutOfRange) || !strings.Contains(err.Error(), "1.0240V") {
+		t.Fatalf("expected ErrOutOfRange with the unclamped volts, got %v", err)
+	}
+	if s := c.Stats(); s.Errors != 1 || s.Reads != 0 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+
+	c.clampV = 3.3
+	if v, err := c.Measure(); err != nil || math.Abs(v-512) > 1e-9 {
+		t.Errorf("in-range reading = %v, %v; want 512", v, err)
+	}
+}
+
+// gainBus answers every conversion with 0x2000 counts and records the last
+// config word written.
+type gainBus struct {
+	fixedBus
+	config uint16
+}
+
+func (b *gainBus) WriteToReg(_, reg byte, v []byte) error {
+	if reg == regConfig {
+		b.config = uint16(v[0])<<8 | uint16(v[1])
+	}
+	return nil
+}
+
+func TestMeasureWithGain(t *testing.T) {
+	bus := &gainBus{}
+	c := newTdsChannel(bus, 0x4C, 0, configMuxSingle0, configGainOne, 500, 0, 3.3,
+		defaultAlphaPerC, false, 25, false, false, Factory().Metadata())
+
+	// 0x2000 counts = 1/4 full scale.
+	r, err := c.MeasureWithGain(GainFour)
+	if err != nil || bus.config&configGainMask != GainFour || math.Abs(r.VoltsRaw-0.256) > 1e-9 {
+		t.Fatalf("GainFour: volts=%v cfg=0x%04X err=%v", r.VoltsRaw, bus.config, err)
+	}
+	if _, err := c.Measure(); err != nil || bus.config&configGainMask != configGainOne {
+		t.Errorf("Measure must return to the configured gain: cfg=0x%04X err=%v", bus.config, err)
+	}
+	if _, err := c.MeasureWithGain(0x0C00); err == nil {
+		t.Error("expected an error for an unknown gain")
+	}
+}
+
+func TestMeasureWithGainLeavesChannelState(t *testing.T) {
+	bus := &gainBus{}
+	c := newTdsChannel(bus, 0x4C, 0, configMuxSingle0, configGainOne, 500, 0, 3.3,
+		defaultAlphaPerC, false, 25, false, false, Factory().Metadata())
+
+	if _, err := c.Measure(); err != nil {
+		t.Fatal(err)
+	}
+	last := c.lastReading()
+	_, n, _ := c.noise.stddev()
+	streak := c.clip.streak
+
+	if _, err := c.MeasureWithGain(GainSixteen); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.lastReading(); got != last {
+		t.Errorf("override-gain read replaced the last reading: %+v -> %+v", last, got)
+	}
+	if _, m, _ := c.noise.stddev(); m != n {
+		t.Errorf("override-gain read fed the noise ring: %d -> %d samples", n, m)
+	}
+	if c.clip.streak != streak {
+		t.Errorf("override-gain read changed the clip streak: %d -> %d", streak, c.clip.streak)
+	}
+
+	// A read at the configured gain is a normal read.
+	if _, err := c.MeasureWithGain(configGainOne); err != nil {
+		t.Fatal(err)
+	}
+	if _, m, _ := c.noise.stddev(); m != n+1 {
+		t.Errorf("configured-gain read must feed the noise ring: %d -> %d samples", n, m)
+	}
+}
+
+// seqBus answers conversions with counts from seq, repeating the last one.
+type seqBus struct {
+	fixedBus
+	seq []int16
+}
+
+func (b *seqBus) ReadFromReg(_, reg byte, v []byte) error {
+	v[0], v[1] = 0x80, 0x00
+	if reg == regConversion {
+		n := b.seq[0]
+		if len(b.seq) > 1 {
+			b.seq = b.seq[1:]
+		}
+		v[0], v[1] = byte(uint16(n)>>8), byte(n)
+	}
+	return nil
+}
+
+func TestSpreadFlagsFloatingInput(t *testing.T) {
+	bus := &seqBus{seq: []int16{100, 9000, 300, 12000, 50, 50, 50, 50, 50, 50, 50, 50}}
+	c := newTdsChannel(bus, 0x4D, 0, configMuxSingle0, configGainOne, 500, 0, 3.3,
+		defaultAlphaPerC, false, 25, false, false, Factory().Metadata())
+	c.maxSpreadCounts = 1000
+	c.failOnSpread = true
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Measure(); err != nil {
+			t.Fatalf("read %d before spreadMinSamples: %v", i, err)
+		}
+	}
+	if _, err := c.Measure(); !errors.Is(err, ErrUnstableInput) {
+		t.Fatalf("expected ErrUnstableInput, got %v", err)
+	}
+
+	// Steady counts push the swings out of the window.
+	var err error
+	for i := 0; i < spreadWindow; i++ {
+		_, err = c.Measure()
+	}
+	if err != nil {
+		t.Errorf("stable input still fails: %v", err)
+	}
+	if flag, counts, _ := c.unstable(); flag || counts != 0 {
+		t.Errorf("unstable()=%v spread=%d after settling", flag, counts)
+	}
+}
+
+func TestRegisterTraceMeta(t *testing.T) {
+	c := newTdsChannel(fixedBus{}, 0x4E, 2, configMuxSingle2, configGainOne, 500, 0, 3.3,
+		defaultAlphaPerC, false, 25, false, false, Factory().Metadata())
+	meta := map[string]any{}
+	c.regs.addMeta(meta)
+	if len(meta) != 0 {
+		t.Fatalf("meta before any conversion: %v", meta)
+	}
+
+	if _, err := c.Measure(); err != nil {
+		t.Fatal(err)
+	}
+	c.regs.addMeta(meta)
+	if meta["last_config_hex"] != "0xE3E3" || meta["last_conv_bytes"] != "20 00" {
+		t.Errorf("last_config_hex=%v last_conv_bytes=%v", meta["last_config_hex"], meta["last_conv_bytes"])
+	}
+	cfg := meta["last_config"].(map[string]any)
+	if cfg["mux"] != "AIN2-GND" || cfg["mode"] != "single-shot" || cfg["data_rate"] != 860 || cfg["comparator"] != "disabled" {
+		t.Errorf("decoded config %v", cfg)
+	}
+}
+
+func TestTempUnit(t *testing.T) {
+	c := newTdsChannel(fixedBus{}, 0x4F, 0, configMuxSingle0, configGainOne, 500, 0, 3.3,
+		defaultAlphaPerC, true, 25, false, false, Factory().Metadata())
+
+	c.SetTemperatureF(77)
+	if temp, injected, _ := c.getTemperatureC(); !injected || math.Abs(temp-25) > 1e-9 {
+		t.Fatalf("SetTemperatureF(77): temp=%v injected=%v, want 25 °C", temp, injected)
+	}
+
+	// 77 through the °C hook is a unit mismatch: dropped, 25 °C stays in use.
+	c.SetTemperatureC(77)
+	if temp, _, _ := c.getTemperatureC(); math.Abs(temp-25) > 1e-9 {
+		t.Errorf("implausible °C value replaced temp: %v", temp)
+	}
+	if reason, _ := c.tempRejection(); !strings.Contains(reason, "looks like °F") {
+		t.Errorf("rejection %q, want a °F hint", reason)
+	}
+
+	c.tempUnit = tempUnitF
+	c.SetTemperatureC(82.4)
+	if temp, _, _ := c.getTemperatureC(); math.Abs(temp-28) > 1e-9 {
+		t.Errorf("TempUnit=F SetTemperatureC(82.4): temp=%v, want 28", temp)
+	}
+	if reason, _ := c.tempRejection(); reason != "" {
+		t.Errorf("rejection not cleared: %q", reason)
+	}
+	c.SetTemperatureC(25)
+	if reason, _ := c.tempRejection(); !strings.Contains(reason, "looks like °C") {
+		t.Errorf("rejection %q, want a °C hint", reason)
+	}
+}
+
+func TestTempRejectionLoggedOnChange(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	c := newTdsChannel(fixedBus{}, 0x4F, 0, configMuxSingle0, configGainOne, 500, 0, 3.3,
+		defaultAlphaPerC, true, 25, false, false, Factory().Metadata())
+	for i := 0; i < 3; i++ {
+		c.SetTemperatureC(77)
+	}
+	c.SetTemperatureC(200)
+	c.SetTemperatureC(25)
+	c.SetTemperatureC(77)
+	if n := strings.Count(buf.String(), "injected temperature ignored"); n != 3 {
+		t.Errorf("logged %d rejections, want 3 (77, 200, 77 after a good value):\n%s", n, buf.String())
+	}
+}
+
+func TestSmoothSnapOnStep(t *testing.T) {
+	bus := &seqBus{seq: []int16{1000, 1000, 1200, 8000}}
+	c := newTdsChannel(bus, 0x49, 0, configMuxSingle0, configGainOne, 500, 0, 3.3,
+		defaultAlphaPerC, false, 25, false, false, Factory().Metadata())
+	applyChannelOptions(c, map[string]interface{}{paramSmoothing: "ema:0.1", paramSmoothSnapThreshold: 100.0})
+
+	var rs []Reading
+	for i := 0; i < 4; i++ {
+		r, err := c.ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rs = append(rs, r)
+	}
+	// 1200 counts is a 12.5 ppm step: smoothed, no snap.
+	if want := rs[1].Smoothed + 0.1*(rs[2].Value-rs[1].Smoothed); math.Abs(rs[2].Smoothed-want) > 1e-9 {
+		t.Errorf("small step smoothed to %v, want %v", rs[2].Smoothed, want)
+	}
+	// 8000 counts is a ~425 ppm step: the value jumps straight to it.
+	if rs[3].Smoothed != rs[3].Value {
+		t.Errorf("large step: smoothed %v, want snap to %v", rs[3].Smoothed, rs[3].Value)
+	}
+	meta := map[string]any{}
+	if notes := c.addSnapMeta(meta, nil); meta["smooth_snapped"] != true || meta["smooth_snaps"] != uint64(1) || len(notes) != 1 {
+		t.Errorf("snap meta %v notes %v", meta, notes)
+	}
+}
+
+// configBus counts config writes and answers with the last written config
+// (OS bit set, so single-shot conversions complete at once).
+type configBus struct {
+	fixedBus
+	cfgWrites int
+	cfg       uint16
+}
+
+func (b *configBus) WriteToReg(_, reg byte, v []byte) error {
+	if reg == regConfig {
+		b.cfgWrites++
+		b.cfg = uint16(v[0])<<8 | uint16(v[1])
+	}
+	return nil
+}
+
+func (b *configBus) ReadFromReg(_, reg byte, v []byte) error {
+	v[0], v[1] = byte(b.cfg>>8)|0x80, byte(b.cfg)
+	if reg == regConversion {
+		v[0], v[1] = 0x20, 0x00
+	}
+	return nil
+}
+
+func TestMixedContinuousAndSingleShot(t *testing.T) {
+	bus := &configBus{}
+	cont := newTdsChannel(bus, 0x48, 0, configMuxSingle0, configGainOne, 500, 0, 3.3,
+		defaultAlphaPerC, false, 25, true, false, Factory().Metadata())
+	single := newTdsChannel(bus, 0x48, 1, configMuxSingle1, configGainOne, 500, 0, 3.3,
+		defaultAlphaPerC, false, 25, false, false, Factory().Metadata())
+
+	if _, err := cont.Measure(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cont.Measure(); err != nil {
+		t.Fatal(err)
+	}
+	if bus.cfgWrites != 1 {
+		t.Fatalf("unchanged continuous config should be written once, got %d writes", bus.cfgWrites)
+	}
+	if _, err := single.Measure(); err != nil {
+		t.Fatal(err)
+	}
+	// The single-shot write left AIN1 powered down; AIN0 must rewrite its config.
+	if _, err := cont.Measure(); err != nil {
+		t.Fatal(err)
+	}
+	if bus.cfgWrites != 3 || bus.cfg&configMuxMask != configMuxSingle0 {
+		t.Errorf("after a single-shot read: %d config writes, mux 0x%04X; want 3 writes on AIN0",
+			bus.cfgWrites, bus.cfg&configMuxMask)
+	}
+}
@@ -0,0 +1,116 @@
+// describe.go
+//
+// Config-only description of the measurement pipeline.
+//
+// DescribePipeline reports what the channel will do with a reading, using the same
+// terms as the debug lines, but derived purely from configuration: no I2C traffic,
+// no injected temperature. Useful for setup docs and UI help.
+//
+package ads1115tds
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DescribePipeline returns a multi-line, human-readable description of the
+// configured pipeline: raw ADC -> volts_raw -> volts_ref -> TDS output.
+func (c *tdsChannel) DescribePipeline() string {
+	k, off := c.coeffs()
+	var b strings.Builder
+	line := func(format string, args ...any) {
+		fmt.Fprintf(&b, format, args...)
+		b.WriteByte('\n')
+	}
+
+	mode := "single-shot (OS poll)"
+	if c.continuous {
+		mode = "continuous"
+	}
+	line("ADS: addr=0x%02X AIN%d vs GND, mux=0x%04X, gain=%s, 860 SPS, %s", c.address, c.channel, c.mux, gainLabel(c.gainConfig), mode)
+	if !c.continuous {
+		if c.pollStrategy == pollStrategySleep {
+			line("ADS:   conversion timeout=%v sleep %v then read", c.convTimeout, convPeriod+convSleepMargin)
+		} else {
+			line("ADS:   conversion timeout=%v poll every %v", c.convTimeout, c.pollWait)
+		}
+	}
+	if c.discardFirstN > 0 {
+		line("ADS:   discard first %d conversions after start-up and after a mux change", c.discardFirstN)
+	}
+
+	fs, ok := fsVoltsForGain(c.gainConfig)
+	if !ok {
+		line("VOLTS: unknown gain config 0x%04X; readings will fail", c.gainConfig)
+		return b.String()
+	}
+	lsb := fs / 32768.0
+	line("VOLTS: volts_raw = (raw / 32768.0) * fs, fs=%.3fV, LSB=%.9f V/count", fs, lsb)
+
+	vMax := math.Min(c.clampV, fs)
+	switch c.negRawPolicy {
+	case negRawPassthrough:
+		line("VOLTS:   clamp to [.. %.3fV], negatives kept (NegativeRawPolicy=passthrough)", c.clampV)
+	case negRawReflect:
+		line("VOLTS:   negatives reflected to |volts|, clamp to [0 .. %.3fV] (NegativeRawPolicy=reflect)", c.clampV)
+	default:
+		line("VOLTS:   clamp to [0 .. %.3fV] (ClampV=%.3fV)", c.clampV, c.clampV)
+	}
+	if c.clampV > fs {
+		line("VOLTS:   note: ClampV is above full scale; usable range ends at %.3fV", fs)
+	}
+	if c.failOnClamp {
+		line("VOLTS:   FailOnClamp: a reading that would clamp fails with ErrOutOfRange")
+	}
+	if c.maxSpreadCounts > 0 {
+		line("ADC: floating input when the last %d raw counts spread > %d (MaxSpreadCounts, FailOnSpread=%v)",
+			spreadWindow, c.maxSpreadCounts, c.failOnSpread)
+	}
+
+	if c.doTempComp && c.compOrder == compLinearFirst {
+		alpha, refTempC := c.tempComp()
+		line("TEMP: CompOrder=%s; volts_ref = volts_raw", compLinearFirst)
+		line("TDS: out_T = %.6f * volts_ref + %.6f", k, off)
+		if len(c.alphaTable) > 0 {
+			line("TEMP: out = out_T / (1 + alpha(T)*(T - %.2f)), alpha(T) from AlphaTable %s", refTempC, formatAlphaTable(c.alphaTable))
+		} else {
+			line("TEMP: out = out_T / (1 + %.4f*(T - %.2f))", alpha, refTempC)
+		}
+		line("TEMP:   T is the injected temperature; RefTempC is used until one arrives")
+		line("TDS:   range (at RefTempC) %.3f .. %.3f, resolution %.6f per count", off, k*vMax+off, math.Abs(k)*lsb)
+		c.describeOutput(line)
+		return b.String()
+	}
+
+	if c.doTempComp {
+		alpha, refTempC := c.tempComp()
+		if len(c.alphaTable) > 0 {
+			line("TEMP: volts_ref = volts_raw / (1 + alpha(T)*(T - %.2f)), alpha(T) from AlphaTable %s", refTempC, formatAlphaTable(c.alphaTable))
+		} else {
+			line("TEMP: volts_ref = volts_raw / (1 + %.4f*(T - %.2f))", alpha, refTempC)
+		}
+		line("TEMP:   T is the injected temperature; RefTempC is used until one arrives")
+	} else {
+		line("TEMP: disabled; volts_ref = volts_raw")
+	}
+
+	line("TDS: out = %.6f * volts_ref + %.6f", k, off)
+	line("TDS:   range (at RefTempC) %.3f .. %.3f, resolution %.6f per count", off, k*vMax+off, math.Abs(k)*lsb)
+	c.describeOutput(line)
+	return b.String()
+}
+
+// describeOutput adds the stages after the TDS output: Smoothing and OutputMode.
+func (c *tdsChannel) describeOutput(line func(format string, args ...any)) {
+	if c.smooth == nil {
+		line("SMOOTH: off; abs = out")
+	} else {
+		line("SMOOTH: abs = %s(out); calibration still uses the instantaneous volts", c.smoothSpec)
+	}
+	if c.outputMode == outputDelta {
+		line("OUTPUT: delta; value = abs - baseline (SetBaseline, or the first %d stable readings; 0 until captured)", baselineStableN)
+	} else {
+		line("OUTPUT: absolute; value = abs")
+	}
+}
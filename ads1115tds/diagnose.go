@@ -0,0 +1,77 @@
+// diagnose.go
+//
+// Health report (see package diag).
+//
+// The liveness check reads the config register of each chip once, under the
+// chip lock, so it never starts a conversion or disturbs another channel's.
+// Per-channel read counters (Stats) are reported as ain<N>_<counter>, and
+// clipping, floating inputs and suspected disconnects become warnings.
+//
+package ads1115tds
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/reef-pi/drivers/diag"
+)
+
+var _ diag.Diagnoser = (*Driver)(nil)
+
+// Diagnose reads each chip's config register and reports per-channel health.
+func (d *Driver) Diagnose() diag.Diagnostics {
+	r := diag.New()
+
+	checked := map[*chip]bool{}
+	for _, c := range d.pins {
+		if !checked[c.chip] {
+			checked[c.chip] = true
+			cfg, err := c.readConfigRegister()
+			if r.Check(fmt.Sprintf("read config 0x%02X", c.address), err) {
+				r.Infof("chip 0x%02X config=0x%04X", c.address, cfg)
+			}
+		}
+		c.diagnose(&r)
+	}
+	return r
+}
+
+// readConfigRegister reads the config register under the chip lock.
+func (c *tdsChannel) readConfigRegister() (uint16, error) {
+	c.chip.mu.Lock()
+	defer c.chip.mu.Unlock()
+	b := make([]byte, 2)
+	if err := c.bus.ReadFromReg(c.address, regConfig, b); err != nil {
+		return 0, fmt.Errorf("ads1115: read config: %w", err)
+	}
+	return binary.BigEndian.Uint16(b), nil
+}
+
+// diagnose adds this channel's counters and warnings to r.
+func (c *tdsChannel) diagnose(r *diag.Diagnostics) {
+	s := c.Stats()
+	key := func(name string) string { return fmt.Sprintf("ain%d_%s", c.channel, name) }
+	r.Counters[key("reads")] = s.Reads
+	r.Counters[key("errors")] = s.Errors
+	r.Counters[key("timeouts")] = s.Timeouts
+	r.Counters[key("i2c_errors")] = s.I2CErrors
+	r.Counters[key("clamp_high")] = s.ClampHigh
+	r.Counters[key("clamp_low")] = s.ClampLow
+	r.Counters[key("disconnect_suspect")] = s.DisconnectSuspect
+	if r.LastError == "" && s.LastError != "" {
+		r.LastError = s.LastError
+	}
+
+	if n := c.clip.current(); n >= clipWarnStreak {
+		r.Warnf("AIN%d: last %d readings clipped; the gain or ClampV truncates the signal", c.channel, n)
+	}
+	if flag, counts, n := c.unstable(); flag {
+		r.Warnf("AIN%d: raw counts spread %d over the last %d reads; floating input, probe disconnected?", c.channel, counts, n)
+	}
+	if s.DisconnectSuspect > 0 {
+		r.Warnf("AIN%d: %d readings pinned at an ADC rail; check the probe connection", c.channel, s.DisconnectSuspect)
+	}
+	if s.Reads == 0 && s.Errors > 0 {
+		r.Warnf("AIN%d: no successful reading yet (%d errors)", c.channel, s.Errors)
+	}
+}
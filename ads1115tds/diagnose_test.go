@@ -0,0 +1,82 @@
+package ads1115tds
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/reef-pi/drivers/diag"
+)
+
+// railBus answers conversions pinned at the positive rail.
+type railBus struct{ fixedBus }
+
+func (railBus) ReadFromReg(_, reg byte, b []byte) error {
+	b[0], b[1] = 0x80, 0x00
+	if reg == regConversion {
+		b[0], b[1] = 0x7F, 0xFF
+	}
+	return nil
+}
+
+// deadBus fails every register read.
+type deadBus struct{ fixedBus }
+
+func (deadBus) ReadFromReg(byte, byte, []byte) error { return errors.New("remote i/o error") }
+
+func hasMessage(r diag.Diagnostics, prefix, substr string) bool {
+	for _, m := range r.Messages {
+		if strings.HasPrefix(m, prefix) && strings.Contains(m, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDiagnoseHealthy(t *testing.T) {
+	c := newTdsChannel(fixedBus{}, 0x48, 0, configMuxSingle0, configGainOne, 500, 0, 3.3,
+		defaultAlphaPerC, false, 25, false, false, Factory().Metadata())
+	if _, err := c.Measure(); err != nil {
+		t.Fatal(err)
+	}
+	r := (&Driver{pins: []*tdsChannel{c}}).Diagnose()
+	if !r.OK || r.LastError != "" {
+		t.Fatalf("healthy chip: %+v", r)
+	}
+	if !hasMessage(r, "", "read config 0x48: ok") {
+		t.Errorf("missing liveness message: %v", r.Messages)
+	}
+	if r.Counters["ain0_reads"] != 1 || r.Counters["ain0_errors"] != 0 {
+		t.Errorf("unexpected counters %v", r.Counters)
+	}
+}
+
+func TestDiagnoseWarnsOnRail(t *testing.T) {
+	c := newTdsChannel(railBus{}, 0x48, 1, configMuxSingle1, configGainOne, 500, 0, 6.0,
+		defaultAlphaPerC, false, 25, false, false, Factory().Metadata())
+	if _, err := c.Measure(); err != nil {
+		t.Fatal(err)
+	}
+	r := (&Driver{pins: []*tdsChannel{c}}).Diagnose()
+	if !r.OK {
+		t.Fatalf("a pinned reading is a warning, not an error: %+v", r)
+	}
+	if !hasMessage(r, diag.WarningPrefix, "AIN1: 1 readings pinned at an ADC rail") {
+		t.Errorf("missing rail warning: %v", r.Messages)
+	}
+	if r.Counters["ain1_disconnect_suspect"] != 1 {
+		t.Errorf("unexpected counters %v", r.Counters)
+	}
+}
+
+func TestDiagnoseDeadChip(t *testing.T) {
+	c := newTdsChannel(deadBus{}, 0x49, 0, configMuxSingle0, configGainOne, 500, 0, 3.3,
+		defaultAlphaPerC, false, 25, false, false, Factory().Metadata())
+	r := (&Driver{pins: []*tdsChannel{c}}).Diagnose()
+	if r.OK || !strings.Contains(r.LastError, "remote i/o error") {
+		t.Fatalf("dead chip must fail: %+v", r)
+	}
+	if !hasMessage(r, diag.ErrorPrefix, "read config 0x49") {
+		t.Errorf("missing error message: %v", r.Messages)
+	}
+}
@@ -34,6 +34,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/reef-pi/drivers/filter"
+	"github.com/reef-pi/drivers/i2creg"
 	"github.com/reef-pi/hal"
 	"github.com/reef-pi/rpi/i2c"
 )
@@ -59,12 +61,64 @@ const (
 	configComparitorPolarityActiveLow uint16 = 0x0000
 	configComparitorQueueNone         uint16 = 0x0003
 
-	// conversion poll limits (ADS1115 @ 860SPS is ~1.2ms)
+	// conversion poll limits (ADS1115 @ 860SPS is ~1.2ms).
+	// Defaults; ConvTimeoutMs / ConvPollUs can override within the bounds below.
 	convTimeout  = 50 * time.Millisecond
 	convPollWait = 200 * time.Microsecond
 
+	minConvTimeout  = 5 * time.Millisecond
+	maxConvTimeout  = 1000 * time.Millisecond
+	minConvPollWait = 50 * time.Microsecond
+	maxConvPollWait = 10 * time.Millisecond
+
+	// Bounds for AlphaPerC / RefTempC (factory validation and live setters)
+	minAlphaPerC = 0.0
+	maxAlphaPerC = 0.1
+	minRefTempC  = 0.0
+	maxRefTempC  = 60.0
+
 	// Reasonable "stale temperature" threshold for warning logs
 	tempStaleWarn = 2 * time.Minute
+
+	// With DoTempComp on, no temperature at all after this long since startup
+	// means there is no temperature source: compensation is flagged inactive.
+	tempSourceGrace = 5 * time.Minute
+
+	// Calibration points captured further apart than this (°C) with temp comp
+	// disabled are flagged: the fit then mixes temperature and concentration.
+	calTempSpreadWarnC = 2.0
+)
+
+// NegativeRawPolicy values: what rawToVolts does with volts below 0 (noise around
+// 0V on a single-ended input).
+const (
+	negRawClamp       = "clamp"       // rectify to 0 (default, safe)
+	negRawPassthrough = "passthrough" // keep small negatives so averages stay unbiased
+	negRawReflect     = "reflect"     // use |volts|
+)
+
+// CompOrder values: where temperature compensation sits relative to the linear
+// TDS step. normalize-then-linear (default) rescales volts to RefTempC and
+// applies TdsK/TdsOffset to them, so k and offset describe the probe at
+// RefTempC. linear-then-normalize applies TdsK/TdsOffset to the measured volts
+// and rescales the result, for probes whose linear fit was characterised at the
+// measured temperature. The two differ only when TdsOffset is non-zero.
+const (
+	compNormalizeFirst = "normalize-then-linear"
+	compLinearFirst    = "linear-then-normalize"
+)
+
+// PollStrategy values: how single-shot mode waits for a conversion.
+const (
+	pollStrategyPoll  = "poll"  // re-read the OS bit every pollWait (lowest latency)
+	pollStrategySleep = "sleep" // sleep one conversion period, then read (fewest wakeups)
+)
+
+// convPeriod is one conversion at 860 SPS (~1.16ms); the sleep strategy waits
+// this plus convSleepMargin for oscillator tolerance (ADS1115 rate is ±10%).
+const (
+	convPeriod      = time.Second / 860
+	convSleepMargin = convPeriod / 5
 )
 
 var logBusTypeOnce sync.Once
@@ -77,6 +131,8 @@ const (
 	configGainFour      uint16 = 0x0600 // +/- 1.024V
 	configGainEight     uint16 = 0x0800 // +/- 0.512V
 	configGainSixteen   uint16 = 0x0A00 // +/- 0.256V
+
+	configGainMask uint16 = 0x0E00 // PGA bits of the config word
 )
 
 // --- Mux (single-ended AINx vs GND) ---
@@ -142,32 +198,73 @@ func gainLabel(gain uint16) string {
 	}
 }
 
-// Driver provides one AnalogInput pin (single channel per driver instance).
+// Driver provides one AnalogInput pin per configured channel: the single
+// Channel, or every entry of ChannelsSpec.
 type Driver struct {
 	meta hal.Metadata
-	pin  *tdsChannel
+	pins []*tdsChannel
+
+	// claim is this instance's i2creg address claim (nil when config-only).
+	claim *i2creg.Handle
 }
 
 func (d *Driver) Name() string           { return driverName }
 func (d *Driver) Metadata() hal.Metadata { return d.meta }
-func (d *Driver) Close() error           { return nil }
+func (d *Driver) Close() error {
+	d.claim.Release()
+	return nil
+}
+
+// DriverType returns the stable driver kind for host-side routing.
+func (d *Driver) DriverType() string { return driverType }
+
+// SetAlphaPerC and SetRefTempC forward live compensation tuning to every channel.
+func (d *Driver) SetAlphaPerC(alpha float64) error {
+	for _, p := range d.pins {
+		if err := p.SetAlphaPerC(alpha); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Driver) SetRefTempC(refTempC float64) error {
+	for _, p := range d.pins {
+		if err := p.SetRefTempC(refTempC); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
 // Pins returns pins for the requested capability.
 func (d *Driver) Pins(cap hal.Capability) ([]hal.Pin, error) {
 	switch cap {
 	case hal.AnalogInput:
-		return []hal.Pin{d.pin}, nil
+		pins := make([]hal.Pin, 0, len(d.pins))
+		for _, p := range d.pins {
+			pins = append(pins, p)
+		}
+		return pins, nil
 	default:
 		return nil, fmt.Errorf("unsupported capability: %s", cap.String())
 	}
 }
 
-func (d *Driver) AnalogInputPins() []hal.AnalogInputPin { return []hal.AnalogInputPin{d.pin} }
+func (d *Driver) AnalogInputPins() []hal.AnalogInputPin {
+	pins := make([]hal.AnalogInputPin, 0, len(d.pins))
+	for _, p := range d.pins {
+		pins = append(pins, p)
+	}
+	return pins
+}
 
-// AnalogInputPin returns the configured channel pin if it matches n.
+// AnalogInputPin returns the configured channel pin numbered n (AIN n).
 func (d *Driver) AnalogInputPin(n int) (hal.AnalogInputPin, error) {
-	if d.pin.Number() == n {
-		return d.pin, nil
+	for _, p := range d.pins {
+		if p.Number() == n {
+			return p, nil
+		}
 	}
 	return nil, fmt.Errorf("%s: no analog input channel %d", driverName, n)
 }
@@ -187,24 +284,111 @@ type tdsChannel struct {
 	mux        uint16
 	gainConfig uint16
 
+	// chip is shared by every channel on the same bus+address.
+	chip *chip
+
+	// continuous runs the ADS1115 in continuous-conversion mode (config write skipped when unchanged).
+	continuous bool
+
+	// Conversion wait tuning (single-shot mode): give up after convTimeout, re-poll every pollWait.
+	// pollStrategy is pollStrategyPoll or pollStrategySleep.
+	convTimeout  time.Duration
+	pollWait     time.Duration
+	pollStrategy string
+
 	// Calibration coefficients for the final linear conversion.
+	// Guarded by calMu because Calibrate can update them at runtime.
 	tdsK      float64
 	tdsOffset float64
+	calPoints []calPoint
+	calFit    calFit
+	calMu     sync.Mutex
 
 	// Clamp voltage to match your hardware range (usually 3.3 or 5.0).
-	clampV float64
+	// failOnClamp turns a clamped reading into ErrOutOfRange (see clip.go).
+	clampV      float64
+	failOnClamp bool
+
+	// unitLabel overrides the snapshot unit and primary display name ("" = TDS).
+	unitLabel string
+
+	// outputStep snaps the primary value to multiples of itself (0 = off).
+	// Raw and volts signals are never quantized.
+	outputStep float64
 
-	// Temperature compensation coefficient (per °C), e.g. 0.02
-	alphaPerC float64
+	// negRawPolicy handles volts < 0 (negRawClamp / negRawPassthrough / negRawReflect).
+	negRawPolicy string
+
+	// Temperature compensation coefficient (per °C), e.g. 0.02.
+	// alphaTable, when set, replaces it with alpha interpolated by temperature (see alphatable.go).
+	alphaPerC  float64
+	alphaTable []alphaPoint
 
 	// Temperature compensation settings
 	doTempComp bool    // checkbox
 	refTempC   float64 // reference temperature (typically 25C)
-
-	// Latest injected temperature (°C) and last update time (for staleness warnings)
-	tempC         float64
-	tempUpdatedAt time.Time
-	tempMu        sync.Mutex
+	compOrder  string  // compNormalizeFirst / compLinearFirst
+
+	// tempSourceCh, when >= 0, takes temperature from another channel of the same
+	// chip (published via PublishTemperatureC) before the injected one.
+	tempSourceCh int
+
+	// Latest injected temperature (°C) and last update time (for staleness warnings).
+	// tempUnit is the unit SetTemperatureC takes (tempUnitC / tempUnitF);
+	// tempRejected is why the last injected value was dropped (see tempunit.go).
+	tempC          float64
+	tempUpdatedAt  time.Time
+	tempUnit       string
+	tempRejected   string
+	tempRejectedAt time.Time
+	tempMu         sync.Mutex
+
+	// createdAt starts the tempSourceGrace window.
+	createdAt time.Time
+
+	// noise keeps recent raw counts for the noise_counts / noise_mv signals.
+	noise noiseRing
+
+	// regs keeps the last config word and conversion bytes (see registers.go).
+	regs regTrace
+
+	// maxSpreadCounts flags a floating input when recent raw counts spread
+	// more than this (0 = off); failOnSpread fails Measure instead (see spread.go).
+	maxSpreadCounts int
+	failOnSpread    bool
+
+	// history keeps recent samples for meta "history" when includeHistory is set.
+	includeHistory bool
+	history        historyRing
+
+	// discardFirstN drops unsettled conversions (see warmup.go). warmupLeft
+	// counts the start-up discards still pending (guarded by chip.mu).
+	discardFirstN int
+	warmupLeft    int
+
+	// clip counts consecutive high-side clipped readings (see clip.go).
+	clip clipTracker
+
+	// outputMode is outputAbsolute or outputDelta; in delta mode the primary
+	// value is reported against baseline (see baseline.go).
+	outputMode string
+	baseline   baselineTracker
+
+	// last is the most recent successful reading (see MeasureStamped).
+	// stats counts readings and failures (see stats.go). smooth filters the
+	// reported value (Smoothing, nil = off, see smooth.go). All guarded by lastMu.
+	last       Reading
+	stats      Stats
+	smooth     filter.Filter
+	smoothSpec string
+	lastMu     sync.Mutex
+
+	// snapThreshold wraps smooth with jump detection (SmoothSnapThreshold,
+	// 0 = off); snapped/snaps/lastSnapAt record the snaps (guarded by lastMu).
+	snapThreshold float64
+	snapped       bool
+	snaps         uint64
+	lastSnapAt    time.Time
 
 	debug bool
 	meta  hal.Metadata
@@ -222,6 +406,7 @@ func newTdsChannel(
 	alphaPerC float64,
 	doTempComp bool,
 	refTempC float64,
+	continuous bool,
 	debug bool,
 	meta hal.Metadata,
 ) *tdsChannel {
@@ -231,6 +416,11 @@ func newTdsChannel(
 		channel:    channelNum,
 		mux:        mux,
 		gainConfig: gain,
+		chip:       chipFor(b, address),
+		continuous: continuous,
+
+		convTimeout: convTimeout,
+		pollWait:    convPollWait,
 		tdsK:       tdsK,
 		tdsOffset:  tdsOffset,
 		clampV:     clampV,
@@ -239,6 +429,14 @@ func newTdsChannel(
 		refTempC:   refTempC,
 		debug:      debug,
 		meta:       meta,
+
+		tempSourceCh: -1,
+		tempUnit:     tempUnitC,
+		negRawPolicy: negRawClamp,
+		pollStrategy: pollStrategyPoll,
+		compOrder:    compNormalizeFirst,
+		outputMode:   outputAbsolute,
+		createdAt:    time.Now(),
 	}
 
 	// Initialize tempC to refTempC so "temp enabled but not yet injected" behaves nicely.
@@ -248,21 +446,57 @@ func newTdsChannel(
 
 // SetTemperatureC allows Chemistry to inject a temperature used for normalization.
 // This is the "external temperature" hook that matches your RoboTank driver pattern.
+// With TempUnit=F the value is taken as °F (see tempunit.go).
 func (c *tdsChannel) SetTemperatureC(tempC float64) {
+	c.injectTemperature(tempC, c.tempUnit)
+}
+
+// SetAlphaPerC changes the temperature coefficient at runtime (same 0..0.1
+// bounds as AlphaPerC). The next reading uses it, unless an AlphaTable is set.
+func (c *tdsChannel) SetAlphaPerC(alpha float64) error {
+	if math.IsNaN(alpha) || alpha < minAlphaPerC || alpha > maxAlphaPerC {
+		return fmt.Errorf("ads1115tds: AlphaPerC must be %g..%g, got %g", minAlphaPerC, maxAlphaPerC, alpha)
+	}
 	c.tempMu.Lock()
-	old := c.tempC
-	c.tempC = tempC
-	c.tempUpdatedAt = time.Now()
+	old := c.alphaPerC
+	c.alphaPerC = alpha
 	c.tempMu.Unlock()
 
-	if c.debug {
-		log.Printf("ads1115tds addr=0x%02X ch=%d SetTemperatureC: %.2fC -> %.2fC (DoTempComp=%v RefTempC=%.2f alpha=%.4f)",
-			c.address, c.channel, old, tempC, c.doTempComp, c.refTempC, c.alphaPerC)
+	log.Printf("ads1115tds addr=0x%02X ch=%d AlphaPerC %.4f -> %.4f", c.address, c.channel, old, alpha)
+	return nil
+}
+
+// SetRefTempC changes the compensation reference temperature at runtime (same
+// 0..60 °C bounds as RefTempC). The next reading uses it.
+func (c *tdsChannel) SetRefTempC(refTempC float64) error {
+	if math.IsNaN(refTempC) || refTempC < minRefTempC || refTempC > maxRefTempC {
+		return fmt.Errorf("ads1115tds: RefTempC must be %g..%g °C, got %g", minRefTempC, maxRefTempC, refTempC)
 	}
+	c.tempMu.Lock()
+	old := c.refTempC
+	c.refTempC = refTempC
+	c.tempMu.Unlock()
+
+	log.Printf("ads1115tds addr=0x%02X ch=%d RefTempC %.2f -> %.2f", c.address, c.channel, old, refTempC)
+	return nil
+}
+
+// tempComp returns the current compensation settings (see SetAlphaPerC / SetRefTempC).
+func (c *tdsChannel) tempComp() (alpha, refTempC float64) {
+	c.tempMu.Lock()
+	defer c.tempMu.Unlock()
+	return c.alphaPerC, c.refTempC
 }
 
 // getTemperatureC returns the latest injected temp and whether it has ever been injected.
+// A fresh temperature published on the chip for tempSourceCh takes precedence.
 func (c *tdsChannel) getTemperatureC() (temp float64, injected bool, updatedAt time.Time) {
+	if c.tempSourceCh >= 0 {
+		if t, at, ok := c.chip.temperature(c.tempSourceCh); ok && time.Since(at) <= tempStaleWarn {
+			return t, true, at
+		}
+	}
+
 	c.tempMu.Lock()
 	defer c.tempMu.Unlock()
 
@@ -287,29 +521,167 @@ func (c *tdsChannel) Number() int            { return c.channel }
 func (c *tdsChannel) Close() error           { return nil }
 func (c *tdsChannel) Metadata() hal.Metadata { return c.meta }
 
-// Calibrate is a no-op because this driver uses config-linear (TdsK/TdsOffset).
-// Use the UI/config to adjust calibration coefficients.
-func (c *tdsChannel) Calibrate(_ []hal.Measurement) error { return nil }
+// calPoint is one captured calibration point.
+// Volts are always volts@RefTempC when DoTempComp is on, so the fitted
+// TdsK/TdsOffset stay consistent with the normalized measurement path.
+type calPoint struct {
+	Expected     float64   `json:"expected"`
+	Volts        float64   `json:"volts"`
+	TempC        float64   `json:"temp_c"`
+	TempInjected bool      `json:"temp_injected"`
+	At           time.Time `json:"at"`
+	Residual     float64   `json:"residual"` // expected - fitted (see calfit.go)
+}
+
+// coeffs returns the current TdsK/TdsOffset.
+func (c *tdsChannel) coeffs() (k, off float64) {
+	c.calMu.Lock()
+	defer c.calMu.Unlock()
+	return c.tdsK, c.tdsOffset
+}
+
+// Calibrate fits TdsK/TdsOffset from TDS-domain points:
+//   - Expected = known TDS of the calibration solution
+//   - Observed = the snapshot "volts" signal (volts@RefTempC when DoTempComp is on)
+//
+// If Observed is 0, the channel is read live and raw volts are normalized with the
+// temperature at capture time. The capture temperature is stored for every point.
+//
+// One point adjusts TdsOffset only; two or more set both TdsK and TdsOffset
+// by least squares (up to maxCalPoints, see calfit.go).
+// Fitted values are runtime-only; copy them into the driver config to persist.
+func (c *tdsChannel) Calibrate(ms []hal.Measurement) error {
+	if len(ms) == 0 {
+		return nil
+	}
+	alpha, refTempC := c.tempComp()
+	if len(ms) > maxCalPoints {
+		return fmt.Errorf("%s: calibration supports 1..%d points, got %d", driverName, maxCalPoints, len(ms))
+	}
+
+	// With linear-then-normalize the fit maps measured volts to TDS at the
+	// capture temperature, so each Expected (TDS at RefTempC) is scaled back to it.
+	linearFirst := c.doTempComp && c.compOrder == compLinearFirst
+	points := make([]calPoint, 0, len(ms))
+	targets := make([]float64, 0, len(ms))
+	for _, m := range ms {
+		temp, injected, _ := c.getTemperatureC()
+		volts := m.Observed
+
+		if volts == 0 {
+			_, voltsRaw, _, _, _, _, err := c.measureAllDebug()
+			if err != nil {
+				return err
+			}
+			volts = voltsRaw
+			if c.doTempComp && !linearFirst {
+				volts = tempNormalize(voltsRaw, temp, alphaAt(c.alphaTable, temp, alpha), refTempC)
+			}
+		}
+
+		target := m.Expected
+		if linearFirst {
+			target *= 1.0 + alphaAt(c.alphaTable, temp, alpha)*(temp-refTempC)
+		}
+		targets = append(targets, target)
+
+		points = append(points, calPoint{
+			Expected:     m.Expected,
+			Volts:        volts,
+			TempC:        temp,
+			TempInjected: injected,
+			At:           time.Now(),
+		})
+	}
+
+	c.calMu.Lock()
+	defer c.calMu.Unlock()
+
+	xs := make([]float64, len(points))
+	for i, p := range points {
+		xs[i] = p.Volts
+	}
+	k, off := c.tdsK, c.tdsOffset
+	if len(points) == 1 {
+		off = targets[0] - k*xs[0]
+	} else {
+		var err error
+		if k, off, err = fitLinear(xs, targets); err != nil {
+			return fmt.Errorf("%s: %v", driverName, err)
+		}
+	}
+	residuals, fit := fitStats(xs, targets, k, off)
+	for i := range points {
+		points[i].Residual = residuals[i]
+	}
+
+	c.tdsK, c.tdsOffset, c.calPoints, c.calFit = k, off, points, fit
+	c.resetSmoothing()
+	c.baseline.reset()
+
+	log.Printf("ads1115tds addr=0x%02X ch=%d calibrated k=%.6f off=%.6f points=%d r2=%.5f max_residual=%.3f DoTC=%v",
+		c.address, c.channel, k, off, len(points), fit.R2, fit.MaxResidual, c.doTempComp)
+	if w := calTempWarning(points, c.doTempComp); w != "" {
+		log.Printf("ads1115tds addr=0x%02X ch=%d WARNING: %s", c.address, c.channel, w)
+	}
+	if w := calFitWarning(points, fit); w != "" {
+		log.Printf("ads1115tds addr=0x%02X ch=%d WARNING: %s", c.address, c.channel, w)
+	}
+	return nil
+}
+
+// calTempWarning flags points captured at clearly different temperatures
+// while compensation was off.
+func calTempWarning(points []calPoint, doTempComp bool) string {
+	if doTempComp || len(points) < 2 {
+		return ""
+	}
+	lo, hi := points[0].TempC, points[0].TempC
+	for _, p := range points[1:] {
+		lo, hi = math.Min(lo, p.TempC), math.Max(hi, p.TempC)
+	}
+	spread := hi - lo
+	if spread <= calTempSpreadWarnC {
+		return ""
+	}
+	return fmt.Sprintf("calibration points were captured %.1f°C apart with temp compensation disabled; TdsK includes a temperature error.", spread)
+}
 
 func (c *tdsChannel) Value() (float64, error) { return c.Measure() }
 
 // Measure returns the calibrated TDS reading.
+// With debug off it runs the pipeline without formatting any debug lines.
 func (c *tdsChannel) Measure() (float64, error) {
-	raw, voltsRaw, voltsRef, out, dbg, err := c.measureAllDebug()
+	if !c.debug {
+		r, err := c.measure(nil)
+		if err != nil {
+			return 0, err
+		}
+		return quantize(c.output(r.Smoothed), c.outputStep), nil
+	}
+
+	alpha, refTempC := c.tempComp()
+	raw, voltsRaw, voltsRef, out, smoothed, dbg, err := c.measureAllDebug()
 	if err != nil {
 		return 0, err
 	}
 
-	c.dbg("SUMMARY raw=%d volts_raw=%.6f volts_ref=%.6f out=%.6f (k=%.6f off=%.6f clamp=%.2fV alpha=%.4f DoTC=%v RefTemp=%.2f)",
-		raw, voltsRaw, voltsRef, out, c.tdsK, c.tdsOffset, c.clampV, c.alphaPerC, c.doTempComp, c.refTempC)
-
-	if c.debug {
-		for _, line := range dbg {
-			c.dbg("%s", line)
-		}
+	k, off := c.coeffs()
+	c.dbg("SUMMARY raw=%d volts_raw=%.6f volts_ref=%.6f out=%.6f smoothed=%.6f (k=%.6f off=%.6f clamp=%.2fV alpha=%.4f DoTC=%v RefTemp=%.2f smoothing=%s)",
+		raw, voltsRaw, voltsRef, out, smoothed, k, off, c.clampV, alpha, c.doTempComp, refTempC, c.smoothingName())
+	for _, line := range dbg {
+		c.dbg("%s", line)
 	}
 
-	return out, nil
+	return quantize(c.output(smoothed), c.outputStep), nil
+}
+
+// quantize rounds v to the nearest multiple of step; step <= 0 returns v.
+func quantize(v, step float64) float64 {
+	if step <= 0 {
+		return v
+	}
+	return math.Round(v/step) * step
 }
 
 // tempNormalize converts observed volts at temperature T into equivalent volts at RefTempC.
@@ -322,143 +694,260 @@ func tempNormalize(volts, tempC, alpha, refTempC float64) float64 {
 	return volts / (1.0 + alpha*(tempC-refTempC))
 }
 
+// trace collects pipeline debug lines. Methods on a nil *trace are no-ops, so the
+// pipeline can run without formatting any strings (see ReadAll). The arguments of
+// a call are still boxed into interfaces, so hot-path callers also check t != nil
+// before building them.
+type trace struct {
+	lines []string
+}
+
+func (t *trace) addf(format string, args ...any) {
+	if t == nil {
+		return
+	}
+	t.lines = append(t.lines, fmt.Sprintf(format, args...))
+}
+
+// Reading is one pass through the conversion pipeline.
+type Reading struct {
+	Raw      int16   // ADS1115 conversion result (signed counts)
+	VoltsRaw float64 // gain-scaled and clamped volts
+	VoltsRef float64 // volts@RefTempC when DoTempComp is on, else VoltsRaw
+	Value    float64 // calibrated output: TdsK*VoltsRef + TdsOffset
+	Smoothed float64 // Value after Smoothing (equals Value when off)
+
+	TakenAt time.Time // when the conversion result was read from the chip
+}
+
+// ReadAll runs the full pipeline and returns every stage without building
+// debug lines, notes, or meta. Use it for programmatic access to the result.
+func (c *tdsChannel) ReadAll() (Reading, error) {
+	return c.measure(nil)
+}
+
+// MeasureStamped returns the calibrated reading with the time its conversion
+// was taken. cached reports a value served from the last reading rather than
+// a fresh conversion; every call converts today, so it is always false, but
+// callers should not assume that if sampling moves to the background.
+func (c *tdsChannel) MeasureStamped() (value float64, takenAt time.Time, cached bool, err error) {
+	r, err := c.measure(nil)
+	if err != nil {
+		return 0, time.Time{}, false, err
+	}
+	return quantize(c.output(r.Smoothed), c.outputStep), r.TakenAt, false, nil
+}
+
+// lastReading returns the most recent successful reading (zero TakenAt if none).
+func (c *tdsChannel) lastReading() Reading {
+	c.lastMu.Lock()
+	defer c.lastMu.Unlock()
+	return c.last
+}
+
 // measureAllDebug runs the full pipeline and returns detailed debug lines:
-//   raw ADC -> volts_raw -> volts_ref -> TDS output
+//   raw ADC -> volts_raw -> volts_ref -> TDS output -> smoothed output
+// Lines are only built when debug is enabled; otherwise lines is nil.
 func (c *tdsChannel) measureAllDebug() (
 	raw int16,
 	voltsRaw float64,
 	voltsRef float64,
 	out float64,
+	smoothed float64,
 	lines []string,
 	err error,
 ) {
-	lines = []string{}
+	var t *trace
+	if c.debug {
+		t = &trace{lines: []string{}}
+	}
+	r, err := c.measure(t)
+	if t == nil {
+		return r.Raw, r.VoltsRaw, r.VoltsRef, r.Value, r.Smoothed, nil, err
+	}
+	if err != nil {
+		return 0, 0, 0, 0, 0, t.lines, err
+	}
+	return r.Raw, r.VoltsRaw, r.VoltsRef, r.Value, r.Smoothed, t.lines, nil
+}
+
+// measure runs raw ADC -> volts_raw -> volts_ref -> TDS output at the
+// configured gain. Debug lines are added to t when it is non-nil.
+func (c *tdsChannel) measure(t *trace) (Reading, error) {
+	return c.measureGain(t, c.gainConfig)
+}
+
+// measureGain is measure with the PGA gain for this one conversion (see
+// MeasureWithGain). A read at any gain other than the configured one is a
+// one-off: its counts are on a different scale, so it stays out of the noise
+// ring, clip streak, history, smoothing and the last reading.
+func (c *tdsChannel) measureGain(t *trace, gain uint16) (Reading, error) {
+	alpha, refTempC := c.tempComp()
+	override := gain != c.gainConfig
 
 	// ---------------------------------------------------------------------
 	// 1) Perform ADS1115 conversion (raw ADC counts)
 	// ---------------------------------------------------------------------
-	raw, convLines, err := c.performConversionDebug()
+	raw, err := c.performConversion(t, gain)
 	if err != nil {
-		return 0, 0, 0, 0, lines, err
+		c.recordError(err, true)
+		return Reading{}, err
+	}
+	takenAt := time.Now()
+	if !override {
+		c.noise.add(raw)
+		if err := c.spreadError(); err != nil {
+			c.recordError(err, false)
+			return Reading{}, err
+		}
 	}
-	lines = append(lines, convLines...)
 
 	// ---------------------------------------------------------------------
 	// 2) Convert raw ADC -> volts (gain-scaled) then clamp
 	// ---------------------------------------------------------------------
-	voltsRaw, voltsLines, err := c.rawToVoltsDebug(raw)
+	voltsRaw, clampedHigh, clampedLow, err := c.rawToVolts(raw, gain, t)
 	if err != nil {
-		return 0, 0, 0, 0, lines, err
+		c.recordError(err, false)
+		return Reading{}, err
+	}
+	if !override {
+		c.checkClip(raw, voltsRaw, gain)
+	}
+	if err := c.clampError(raw, gain, clampedHigh, clampedLow); err != nil {
+		c.recordError(err, false)
+		return Reading{}, err
+	}
+	if c.includeHistory && !override {
+		c.history.add(historySample{at: takenAt, raw: raw, volts: voltsRaw})
 	}
-	lines = append(lines, voltsLines...)
 
 	// ---------------------------------------------------------------------
-	// 3) Optional: Temperature normalize volts to RefTempC
+	// 3) Temperature normalization and linear output (computeTDS)
 	// ---------------------------------------------------------------------
 	temp, injected, updatedAt := c.getTemperatureC()
-
-	voltsRef = voltsRaw
 	if c.doTempComp {
-		voltsRef = tempNormalize(voltsRaw, temp, c.alphaPerC, c.refTempC)
-
-		// Stale / missing temperature detection (matches your RoboTank behavior)
-		if !injected {
-			lines = append(lines,
-				fmt.Sprintf("TEMP: enabled but temperature has never been injected; using RefTempC=%.2fC (normalization is no-op).", c.refTempC),
-			)
-		} else {
-			age := time.Since(updatedAt)
-			if age > tempStaleWarn {
-				lines = append(lines,
-					fmt.Sprintf("TEMP: WARNING temperature is stale (age=%v, temp=%.2fC). Check temp_sensor_id / temperature subsystem updates.", age, temp),
-				)
-			}
-		}
+		alpha = alphaAt(c.alphaTable, temp, alpha)
+	}
+	k, off := c.coeffs()
+	fs, _ := fsVoltsForGain(gain) // validated by rawToVolts
+	r := computeTDS(raw, tdsParams{
+		fs:           fs,
+		clampV:       c.clampV,
+		negRawPolicy: c.negRawPolicy,
+		doTempComp:   c.doTempComp,
+		compOrder:    c.compOrder,
+		tempC:        temp,
+		alpha:        alpha,
+		refTempC:     refTempC,
+		k:            k,
+		off:          off,
+	})
+	r.TakenAt = takenAt
+	if t != nil {
+		c.traceTDS(t, r, temp, injected, updatedAt, alpha, refTempC, k, off)
+	}
 
-		lines = append(lines,
-			fmt.Sprintf("TEMP: normalize volts -> volts@RefTempC"),
-			fmt.Sprintf("TEMP:   DoTempComp=true temp=%.2fC (injected=%v) RefTempC=%.2fC alpha=%.4f",
-				temp, injected, c.refTempC, c.alphaPerC),
-			fmt.Sprintf("TEMP:   volts_ref = volts / (1 + alpha*(T-RefTempC))"),
-			fmt.Sprintf("TEMP:   %.9f -> %.9f", voltsRaw, voltsRef),
-		)
+	c.lastMu.Lock()
+	if override {
+		r.Smoothed = r.Value
 	} else {
-		lines = append(lines,
-			fmt.Sprintf("TEMP: disabled (DoTempComp=false). volts_ref := volts_raw (no normalization)"),
-		)
+		r.Smoothed = c.smoothLocked(r.Value)
+		c.last = r
 	}
-
-	// ---------------------------------------------------------------------
-	// 4) Linear output (calibrated domain)
-	// ---------------------------------------------------------------------
-	out = (c.tdsK * voltsRef) + c.tdsOffset
-	lines = append(lines,
-		fmt.Sprintf("TDS: out = (k * volts_ref) + offset"),
-		fmt.Sprintf("TDS:   k=%.9f volts_ref=%.9f => k*volts=%.9f", c.tdsK, voltsRef, c.tdsK*voltsRef),
-		fmt.Sprintf("TDS:   + offset=%.9f => out=%.9f", c.tdsOffset, out),
-	)
-
-	return raw, voltsRaw, voltsRef, out, lines, nil
+	c.recordReadLocked(raw, clampedHigh, clampedLow)
+	c.lastMu.Unlock()
+	return r, nil
 }
 
-// performConversionDebug starts a single-shot conversion and returns raw ADC counts.
-func (c *tdsChannel) performConversionDebug() (int16, []string, error) {
-	lines := []string{}
-
+// performConversion starts a conversion (or reuses a continuous one) and returns raw ADC counts.
+func (c *tdsChannel) performConversion(t *trace, gain uint16) (int16, error) {
 	logBusTypeOnce.Do(func() {
 		c.dbg("INJECTED I2C BUS TYPE = %T", c.bus)
 	})
 
-	// Build config word:
-	// - Single-shot conversion
+	// Serialize with every other channel on the same chip.
+	c.chip.mu.Lock()
+	defer c.chip.mu.Unlock()
+
+	// Config word (base cached on the shared chip; only mux differs per channel):
+	// - Single-shot (OS set) or continuous conversion
 	// - Single-ended mux AINx vs GND
 	// - Selected PGA gain
 	// - 860 SPS
 	// - Comparator disabled
-	config := uint16(
-		configOsSingle |
-			configModeSingle |
-			configComparatorModeTraditional |
-			configComparitorNonLatching |
-			configComparitorPolarityActiveLow |
-			configComparitorQueueNone |
-			c.mux |
-			c.gainConfig |
-			configDataRate860,
-	)
-
-	lines = append(lines,
-		fmt.Sprintf("ADS: build config register"),
-		fmt.Sprintf("ADS:   OS(single)=0x%04X mode(single)=0x%04X datarate(860)=0x%04X comp(disabled bits)=0x%04X",
+	config := c.chip.configFor(c.mux, gain, c.continuous)
+	c.regs.setConfig(config)
+
+	if t != nil {
+		t.addf("ADS: build config register (continuous=%v)", c.continuous)
+		t.addf("ADS:   OS(single)=0x%04X mode(single)=0x%04X datarate(860)=0x%04X comp(disabled bits)=0x%04X",
 			configOsSingle, configModeSingle, configDataRate860,
-			(configComparatorModeTraditional|configComparitorNonLatching|configComparitorPolarityActiveLow|configComparitorQueueNone),
-		),
-		fmt.Sprintf("ADS:   mux=0x%04X gain=0x%04X (%s)", c.mux, c.gainConfig, gainLabel(c.gainConfig)),
-		fmt.Sprintf("ADS:   FINAL cfg=0x%04X", config),
-	)
+			(configComparatorModeTraditional | configComparitorNonLatching | configComparitorPolarityActiveLow | configComparitorQueueNone),
+		)
+		t.addf("ADS:   mux=0x%04X gain=0x%04X (%s)", c.mux, gain, gainLabel(gain))
+		t.addf("ADS:   FINAL cfg=0x%04X", config)
+	}
+
+	muxChanged := c.muxChangedLocked() && c.discardFirstN > 0
+
+	var raw int16
+	var err error
+	if c.continuous {
+		raw, err = c.readContinuousLocked(config, muxChanged, t)
+	} else {
+		if muxChanged {
+			if err := c.discardAfterMuxLocked(config, t); err != nil {
+				return 0, err
+			}
+		}
+		raw, err = c.convertSingleLocked(config, t)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := c.takeWarmupLocked(); err != nil {
+		return 0, err
+	}
+	return raw, nil
+}
 
-	c.dbg("write cfg=0x%04X mux=0x%04X gain=0x%04X", config, c.mux, c.gainConfig)
+// convertSingleLocked runs one single-shot conversion: write config, wait for
+// the OS bit, read the result. The write replaces whatever a continuous channel
+// left running, so the chip's continuous config cache is dropped. Caller holds
+// c.chip.mu.
+func (c *tdsChannel) convertSingleLocked(config uint16, t *trace) (int16, error) {
+	if c.debug {
+		c.dbg("write cfg=0x%04X mux=0x%04X gain=0x%04X", config, c.mux, config&configGainMask)
+	}
 
 	// Write config register (starts conversion)
 	buf := []byte{byte(config >> 8), byte(config)}
-	if c.debug {
-		lines = append(lines, fmt.Sprintf("I2C: write reg=0x%02X bytes=%02X %02X", regConfig, buf[0], buf[1]))
+	if t != nil {
+		t.addf("I2C: write reg=0x%02X bytes=%02X %02X", regConfig, buf[0], buf[1])
 	}
+	c.chip.lastConfigValid = false
 	if err := c.bus.WriteToReg(c.address, regConfig, buf); err != nil {
-		return 0, lines, fmt.Errorf("ads1115: write config: %w", err)
+		return 0, fmt.Errorf("ads1115: write config: %w", err)
 	}
 
-	// Poll OS bit until conversion complete
-	deadline := time.Now().Add(convTimeout)
+	// Poll OS bit until conversion complete. The sleep strategy waits out one
+	// conversion first and then re-checks once per conversion period.
+	deadline := time.Now().Add(c.convTimeout)
 	cfg := make([]byte, 2)
 
 	polls := 0
 	var lastCfg uint16
 	start := time.Now()
 
+	wait := c.pollWait
+	if c.pollStrategy == pollStrategySleep {
+		wait = convPeriod + convSleepMargin
+		time.Sleep(wait)
+	}
+
 	for {
 		if err := c.bus.ReadFromReg(c.address, regConfig, cfg); err != nil {
-			return 0, lines, fmt.Errorf("ads1115: read config: %w", err)
+			return 0, fmt.Errorf("ads1115: read config: %w", err)
 		}
 		lastCfg = binary.BigEndian.Uint16(cfg)
 		polls++
@@ -467,111 +956,195 @@ func (c *tdsChannel) performConversionDebug() (int16, []string, error) {
 			break
 		}
 		if time.Now().After(deadline) {
-			elapsed := time.Since(start)
-			lines = append(lines,
-				fmt.Sprintf("ADS: poll OS bit TIMEOUT after %v polls=%d last_cfg=0x%04X (bytes=%02X %02X)",
-					elapsed, polls, lastCfg, cfg[0], cfg[1]),
-			)
-			return 0, lines, fmt.Errorf("ads1115: conversion timeout (last cfg=0x%04X)", lastCfg)
+			t.addf("ADS: poll OS bit TIMEOUT after %v polls=%d last_cfg=0x%04X (bytes=%02X %02X)",
+				time.Since(start), polls, lastCfg, cfg[0], cfg[1])
+			return 0, fmt.Errorf("%w (last cfg=0x%04X)", ErrConversionTimeout, lastCfg)
+		}
+		time.Sleep(wait)
+	}
+
+	if t != nil {
+		t.addf("ADS: poll OS bit DONE polls=%d elapsed=%v last_cfg=0x%04X (bytes=%02X %02X)",
+			polls, time.Since(start), lastCfg, cfg[0], cfg[1])
+	}
+
+	return c.readConversionLocked(t)
+}
+
+// readContinuousLocked handles continuous mode: the config is only rewritten when it
+// differs from what the chip already runs (e.g. another channel changed the mux).
+// discard drops DiscardFirstN results after the config write. Caller holds c.chip.mu.
+func (c *tdsChannel) readContinuousLocked(config uint16, discard bool, t *trace) (int16, error) {
+	if c.chip.lastConfigValid && c.chip.lastConfig == config {
+		if t != nil {
+			t.addf("ADS: continuous config unchanged; skipping config write")
 		}
-		time.Sleep(convPollWait)
+		return c.readConversionLocked(t)
 	}
 
 	if c.debug {
-		elapsed := time.Since(start)
-		lines = append(lines,
-			fmt.Sprintf("ADS: poll OS bit DONE polls=%d elapsed=%v last_cfg=0x%04X (bytes=%02X %02X)",
-				polls, elapsed, lastCfg, cfg[0], cfg[1]),
-		)
+		c.dbg("write cfg=0x%04X mux=0x%04X gain=0x%04X (continuous)", config, c.mux, config&configGainMask)
+	}
+
+	buf := []byte{byte(config >> 8), byte(config)}
+	if t != nil {
+		t.addf("I2C: write reg=0x%02X bytes=%02X %02X", regConfig, buf[0], buf[1])
+	}
+	if err := c.bus.WriteToReg(c.address, regConfig, buf); err != nil {
+		c.chip.lastConfigValid = false
+		return 0, fmt.Errorf("ads1115: write config: %w", err)
+	}
+	c.chip.lastConfig, c.chip.lastConfigValid = config, true
+
+	// First result after a config change needs a full conversion period.
+	time.Sleep(contSettle)
+	if t != nil {
+		t.addf("ADS: continuous config changed; waited %v for first conversion", contSettle)
+	}
+	if discard {
+		if err := c.discardAfterMuxLocked(config, t); err != nil {
+			return 0, err
+		}
 	}
 
-	// Read conversion register
+	return c.readConversionLocked(t)
+}
+
+// readConversionLocked reads the conversion register. Caller holds c.chip.mu.
+func (c *tdsChannel) readConversionLocked(t *trace) (int16, error) {
 	b := make([]byte, 2)
 	if err := c.bus.ReadFromReg(c.address, regConversion, b); err != nil {
-		return 0, lines, fmt.Errorf("ads1115: read conversion: %w", err)
+		return 0, fmt.Errorf("ads1115: read conversion: %w", err)
 	}
 	raw := int16(binary.BigEndian.Uint16(b))
+	c.regs.setConv(b)
 
-	lines = append(lines,
-		fmt.Sprintf("I2C: read reg=0x%02X bytes=%02X %02X", regConversion, b[0], b[1]),
-		fmt.Sprintf("ADC: raw=int16(be16)=0x%04X => %d", uint16(raw), raw),
-	)
-
-	c.dbg("conv bytes=%02X %02X raw=%d (0x%04X)", b[0], b[1], raw, uint16(raw))
-	return raw, lines, nil
+	if t != nil {
+		t.addf("I2C: read reg=0x%02X bytes=%02X %02X", regConversion, b[0], b[1])
+		t.addf("ADC: raw=int16(be16)=0x%04X => %d", uint16(raw), raw)
+	}
+	if c.debug {
+		c.dbg("conv bytes=%02X %02X raw=%d (0x%04X)", b[0], b[1], raw, uint16(raw))
+	}
+	return raw, nil
 }
 
-// rawToVoltsDebug converts raw ADC counts into volts using the selected gain.
-// Then clamps to [0..ClampV] for single-ended usage.
-func (c *tdsChannel) rawToVoltsDebug(raw int16) (float64, []string, error) {
-	lines := []string{}
-
-	fs, ok := fsVoltsForGain(c.gainConfig)
+// rawToVolts converts raw ADC counts into volts using gain.
+// Then clamps to ClampV, and handles negatives per NegativeRawPolicy
+// (default: clamp to 0 for single-ended usage), reporting which clamp applied.
+func (c *tdsChannel) rawToVolts(raw int16, gain uint16, t *trace) (volts float64, clampedHigh, clampedLow bool, err error) {
+	fs, ok := fsVoltsForGain(gain)
 	if !ok {
-		return 0, lines, fmt.Errorf("ads1115: unknown gain config: 0x%04X", c.gainConfig)
+		return 0, false, false, fmt.Errorf("ads1115: unknown gain config: 0x%04X", gain)
 	}
 
-	// ADS1115 code range is -32768..32767 for full scale.
-	// Use /32768.0 so -32768 maps to -FS and 32767 maps to (FS - 1 LSB).
-	rawF := float64(raw)
-	voltsUnclamped := (rawF / 32768.0) * fs
+	volts, clampedHigh, clampedLow = voltsFromRaw(raw, fs, c.clampV, c.negRawPolicy)
+	if t != nil {
+		c.traceVolts(t, raw, gain, fs, volts, clampedHigh, clampedLow)
+	}
 
-	lines = append(lines,
-		fmt.Sprintf("VOLTS: full-scale fs=%.6fV from gain=0x%04X (%s)", fs, c.gainConfig, gainLabel(c.gainConfig)),
-		fmt.Sprintf("VOLTS: volts_unclamped = (raw / 32768.0) * fs"),
-		fmt.Sprintf("VOLTS:   raw=%d => raw/32768=%.9f", raw, rawF/32768.0),
-		fmt.Sprintf("VOLTS:   * fs=%.6f => volts_unclamped=%.9f", fs, voltsUnclamped),
-	)
+	// Guard against NaN/Inf
+	if math.IsNaN(volts) || math.IsInf(volts, 0) {
+		return 0, false, false, fmt.Errorf("ads1115: computed volts invalid: %v", volts)
+	}
 
-	volts := voltsUnclamped
+	return volts, clampedHigh, clampedLow, nil
+}
 
-	// Clamp for single-ended expectation.
-	// If wiring is truly AINx vs GND and inputs are within range, raw should typically be >= 0.
-	clampedHigh := false
-	clampedLow := false
+// traceTDS adds the temperature and linear-output debug lines for one
+// conversion computed by computeTDS.
+func (c *tdsChannel) traceTDS(t *trace, r Reading, temp float64, injected bool, updatedAt time.Time, alpha, refTempC, k, off float64) {
+	linearFirst := c.doTempComp && c.compOrder == compLinearFirst
+	if linearFirst {
+		t.addf("TEMP: CompOrder=%s; volts_ref := volts_raw, the TDS output is normalized after the linear step", compLinearFirst)
+	} else if c.doTempComp {
+		// Stale / missing temperature detection (matches your RoboTank behavior)
+		if !injected {
+			t.addf("TEMP: enabled but temperature has never been injected; using RefTempC=%.2fC (normalization is no-op).", refTempC)
+		} else {
+			age := time.Since(updatedAt)
+			if age > tempStaleWarn {
+				t.addf("TEMP: WARNING temperature is stale (age=%v, temp=%.2fC). Check temp_sensor_id / temperature subsystem updates.", age, temp)
+			}
+		}
 
-	if volts > c.clampV {
-		volts = c.clampV
-		clampedHigh = true
+		t.addf("TEMP: normalize volts -> volts@RefTempC")
+		t.addf("TEMP:   DoTempComp=true temp=%.2fC (injected=%v) RefTempC=%.2fC alpha=%.4f",
+			temp, injected, refTempC, alpha)
+		t.addf("TEMP:   volts_ref = volts / (1 + alpha*(T-RefTempC))")
+		t.addf("TEMP:   %.9f -> %.9f", r.VoltsRaw, r.VoltsRef)
+	} else {
+		t.addf("TEMP: disabled (DoTempComp=false). volts_ref := volts_raw (no normalization)")
 	}
-	if volts < 0 {
-		volts = 0
-		clampedLow = true
+
+	outT := (k * r.VoltsRef) + off
+	t.addf("TDS: out = (k * volts_ref) + offset")
+	t.addf("TDS:   k=%.9f volts_ref=%.9f => k*volts=%.9f", k, r.VoltsRef, k*r.VoltsRef)
+	t.addf("TDS:   + offset=%.9f => out=%.9f", off, outT)
+	if linearFirst {
+		t.addf("TEMP: normalize output -> output@RefTempC")
+		t.addf("TEMP:   temp=%.2fC (injected=%v) RefTempC=%.2fC alpha=%.4f", temp, injected, refTempC, alpha)
+		t.addf("TEMP:   out = out_T / (1 + alpha*(T-RefTempC)): %.9f -> %.9f", outT, r.Value)
 	}
+}
 
-	if c.debug {
-		if clampedHigh || clampedLow {
-			lines = append(lines,
-				fmt.Sprintf("VOLTS: clamp single-ended: clampV=%.3fV low=0V => volts=%.9f (high_clamp=%v low_clamp=%v)",
-					c.clampV, volts, clampedHigh, clampedLow),
-			)
-		} else {
-			lines = append(lines, fmt.Sprintf("VOLTS: no clamp applied => volts=%.9f", volts))
+// traceVolts adds the rawToVolts debug lines for one conversion.
+func (c *tdsChannel) traceVolts(t *trace, raw int16, gain uint16, fs, volts float64, clampedHigh, clampedLow bool) {
+	// ADS1115 code range is -32768..32767 for full scale.
+	// Use /32768.0 so -32768 maps to -FS and 32767 maps to (FS - 1 LSB).
+	rawF := float64(raw)
+	voltsUnclamped := (rawF / 32768.0) * fs
+
+	t.addf("VOLTS: full-scale fs=%.6fV from gain=0x%04X (%s)", fs, gain, gainLabel(gain))
+	t.addf("VOLTS: volts_unclamped = (raw / 32768.0) * fs")
+	t.addf("VOLTS:   raw=%d => raw/32768=%.9f", raw, rawF/32768.0)
+	t.addf("VOLTS:   * fs=%.6f => volts_unclamped=%.9f", fs, voltsUnclamped)
+
+	if voltsUnclamped < 0 {
+		switch c.negRawPolicy {
+		case negRawPassthrough:
+			t.addf("VOLTS: negative kept (NegativeRawPolicy=passthrough) volts=%.9f", volts)
+		case negRawReflect:
+			t.addf("VOLTS: negative reflected (NegativeRawPolicy=reflect) volts=%.9f", volts)
 		}
 	}
 
+	if clampedHigh || clampedLow {
+		t.addf("VOLTS: clamp single-ended: clampV=%.3fV low=0V => volts=%.9f (high_clamp=%v low_clamp=%v)",
+			c.clampV, volts, clampedHigh, clampedLow)
+	} else {
+		t.addf("VOLTS: no clamp applied => volts=%.9f", volts)
+	}
+
 	// LSB size for context (FS / 32768)
-	lsb := fs / 32768.0
-	lines = append(lines, fmt.Sprintf("VOLTS: LSB ~= fs/32768 = %.12f V/count", lsb))
+	t.addf("VOLTS: LSB ~= fs/32768 = %.12f V/count", fs/32768.0)
 
 	// If raw is negative and you expect single-ended, call it out.
-	if raw < 0 && c.debug {
-		lines = append(lines,
-			fmt.Sprintf("WARN: raw is negative (%d). For true single-ended AINx vs GND, raw should typically be >=0. Check wiring/reference/mux.", raw),
-		)
+	if raw < 0 {
+		t.addf("WARN: raw is negative (%d). For true single-ended AINx vs GND, raw should typically be >=0. Check wiring/reference/mux.", raw)
 	}
+}
 
-	// Guard against NaN/Inf
-	if math.IsNaN(volts) || math.IsInf(volts, 0) {
-		return 0, lines, fmt.Errorf("ads1115: computed volts invalid: %v", volts)
+// unit is the snapshot unit: UnitLabel when set, else "tds".
+func (c *tdsChannel) unit() string {
+	if c.unitLabel != "" {
+		return c.unitLabel
 	}
+	return "tds"
+}
 
-	return volts, lines, nil
+// primaryName is the display name of the primary value.
+func (c *tdsChannel) primaryName() string {
+	if c.unitLabel != "" {
+		return c.unitLabel
+	}
+	return "TDS"
 }
 
 // Snapshot implements hal.SnapshotCapable so Chemistry can show raw/derived signals and wire the wizard.
 func (c *tdsChannel) Snapshot() (hal.Snapshot, error) {
-	raw, voltsRaw, voltsRef, out, dbgLines, err := c.measureAllDebug()
+	alpha, refTempC := c.tempComp()
+	raw, voltsRaw, voltsRef, out, smoothed, dbgLines, err := c.measureAllDebug()
 	if err != nil {
 		return hal.Snapshot{}, err
 	}
@@ -585,13 +1158,20 @@ func (c *tdsChannel) Snapshot() (hal.Snapshot, error) {
 	}
 
 	temp, injected, updatedAt := c.getTemperatureC()
+	tempRejected, tempRejectedAt := c.tempRejection()
 	var tempAgeSec float64
 	if !updatedAt.IsZero() {
 		tempAgeSec = time.Since(updatedAt).Seconds()
 	}
 
-	// UI: primary reading is "value".
-	// "volts" is the observed key used by the calibration wizard:
+	c.calMu.Lock()
+	tdsK, tdsOffset := c.tdsK, c.tdsOffset
+	calPoints := append([]calPoint(nil), c.calPoints...)
+	fit := c.calFit
+	c.calMu.Unlock()
+
+	// UI: primary reading is "value" (smoothed when Smoothing is set; "value_instant" is not).
+	// "volts" is the observed key used by the calibration wizard, never smoothed:
 	// - If DoTempComp=true: volts == volts@RefTempC
 	// - If DoTempComp=false: volts == volts_raw
 	meta := map[string]any{
@@ -601,61 +1181,139 @@ func (c *tdsChannel) Snapshot() (hal.Snapshot, error) {
 		"gain":    fmt.Sprintf("0x%04X", c.gainConfig),
 		"mux":     fmt.Sprintf("0x%04X", c.mux),
 
-		"tdsK":      c.tdsK,
-		"tdsOffset": c.tdsOffset,
+		"continuous":    c.continuous,
+		"poll_strategy": c.pollStrategy,
+
+		"negative_raw_policy": c.negRawPolicy,
+		"comp_order":          c.compOrder,
+
+		"tdsK":      tdsK,
+		"tdsOffset": tdsOffset,
 		"clampV":    c.clampV,
 
+		// FailOnClamp: clamped readings fail instead (see clip.go).
+		"fail_on_clamp": c.failOnClamp,
+
+		// MaxSpreadCounts / FailOnSpread: floating-input detection (see spread.go).
+		"max_spread_counts": c.maxSpreadCounts,
+		"fail_on_spread":    c.failOnSpread,
+
 		// Calibration wizard wiring
 		"calibration_observed_key": "volts",
 
 		"raw_signal_key":        "volts",
 		"primary_signal_key":    "value",
-		"secondary_signal_keys": []string{"volts_raw", "raw", "temp_c"},
+		"secondary_signal_keys": []string{"value_instant", "volts_raw", "raw", "temp_c", "noise_counts", "noise_mv"},
+
+		// Smoothing applies to "value" only; calibration reads the instantaneous "volts".
+		"smoothing":          c.smoothingName(),
+		"primary_smoothed":   c.smooth != nil,
+		"instant_signal_key": "value_instant",
+
+		// OutputMode "delta": value is the change from baseline (see baseline.go).
+		"output_mode": c.outputMode,
 
 		"signal_decimals": map[string]any{
-			"value":     3,
-			"volts":     4,
-			"volts_raw": 4,
-			"raw":       0,
-			"temp_c":    2,
+			"value":         3,
+			"value_instant": 3,
+			"volts":         4,
+			"volts_raw":     4,
+			"raw":           0,
+			"temp_c":        2,
+			"noise_counts":  2,
+			"noise_mv":      3,
 		},
 
 		"display_names": map[string]any{
-			"value":     "TDS",
-			"volts":     func() string {
+			"value": c.primaryName(),
+			"volts": func() string {
 				if c.doTempComp {
-					return fmt.Sprintf("Observed (V @%.0f°C)", c.refTempC)
+					return fmt.Sprintf("Observed (V @%.0f°C)", refTempC)
 				}
 				return "Observed (V)"
 			}(),
-			"volts_raw": "Raw Voltage (V)",
-			"raw":       "ADC Raw",
-			"temp_c":    "Temperature (°C)",
+			"value_instant": c.primaryName() + " (instant)",
+			"volts_raw":     "Raw Voltage (V)",
+			"raw":           "ADC Raw",
+			"temp_c":        "Temperature (°C)",
+			"noise_counts":  "Noise (counts RMS)",
+			"noise_mv":      "Noise (mV RMS)",
 		},
 		"display_help": map[string]any{
-			"value":     "TDS computed from observed volts: (TdsK * volts) + TdsOffset. If temp compensation is enabled, volts is normalized to RefTempC.",
-			"volts":     "Observed electrical signal used by calibration wizard. If temp compensation is enabled, this is volts normalized to RefTempC; otherwise it's raw volts.",
-			"volts_raw": "Raw ADC input voltage after ADS1115 scaling and clamp (single-ended).",
-			"raw":       "Raw ADS1115 conversion reading (signed 16-bit).",
-			"temp_c":    "Injected temperature from reef-pi temperature subsystem (if configured).",
+			"value":         "TDS computed from observed volts: (TdsK * volts) + TdsOffset. If temp compensation is enabled, volts is normalized to RefTempC. Smoothed when Smoothing is set.",
+			"value_instant": "The absolute output from this reading alone, before Smoothing and OutputMode. Equals value when Smoothing is off and OutputMode is absolute.",
+			"volts":         "Observed electrical signal used by calibration wizard. If temp compensation is enabled, this is volts normalized to RefTempC; otherwise it's raw volts.",
+			"volts_raw":     "Raw ADC input voltage after ADS1115 scaling and clamp (single-ended).",
+			"raw":           "Raw ADS1115 conversion reading (signed 16-bit).",
+			"temp_c":        "Injected temperature from reef-pi temperature subsystem (if configured).",
+			"noise_mv":      fmt.Sprintf("Standard deviation of the last %d raw conversions. A sudden jump usually means a ground or shielding problem.", noiseWindow),
 		},
 
 		"temp_compensation": map[string]any{
 			"enabled":        c.doTempComp,
 			"model":          "volts_ref = volts / (1 + alpha*(T-RefTempC))",
-			"alpha_per_c":    c.alphaPerC,
-			"ref_c":          c.refTempC,
+			"alpha_per_c":    alphaAt(c.alphaTable, temp, alpha),
+			"alpha_table":    formatAlphaTable(c.alphaTable),
+			"ref_c":          refTempC,
 			"temp_used_c":    temp,
 			"temp_injected":  injected,
 			"temp_age_sec":   tempAgeSec,
 			"stale_warn_sec": tempStaleWarn.Seconds(),
+			"source_channel": c.tempSourceCh,
+			"temp_unit":      tempUnitLabel(c.tempUnit),
+			"temp_rejected":  tempRejected,
 		},
 	}
 
+	noiseCounts, noiseSamples, _ := c.noise.stddev()
+	fs, _ := fsVoltsForGain(c.gainConfig)
+	noiseMV := noiseCounts * fs / 32768.0 * 1000.0
+	meta["noise_samples"] = noiseSamples
+	c.regs.addMeta(meta)
+	unstable, spreadCounts, spreadSamples := c.unstable()
+	meta["spread_counts"] = spreadCounts
+	meta["spread_samples"] = spreadSamples
+	meta["unstable_input"] = unstable
+	meta["output_step"] = c.outputStep
+	meta["discard_first_n"] = c.discardFirstN
+	if c.includeHistory {
+		meta["history"] = c.history.meta()
+	}
+	meta["clip_streak"] = c.clip.current()
+	meta["stats"] = c.Stats()
+	meta["taken_at"] = c.lastReading().TakenAt.Format(time.RFC3339Nano)
+
 	notes := []string{}
+	if unstable {
+		notes = append(notes, fmt.Sprintf(
+			"WARNING: unstable/floating input, raw counts spread %d over the last %d reads (MaxSpreadCounts=%d); probe disconnected?",
+			spreadCounts, spreadSamples, c.maxSpreadCounts))
+	}
+	primary := c.output(smoothed)
+	if c.outputMode == outputDelta {
+		base, set, at := c.baseline.get()
+		meta["absolute_value"] = smoothed
+		meta["baseline"] = base
+		meta["baseline_set"] = set
+		if set {
+			meta["baseline_at"] = at.Format(time.RFC3339)
+			notes = append(notes, fmt.Sprintf("OutputMode delta: value is the change from baseline %.3f %s (absolute %.3f).", base, c.unit(), smoothed))
+		} else {
+			notes = append(notes, fmt.Sprintf("OutputMode delta: waiting for %d stable readings (or SetBaseline) to capture a baseline; value reads 0 until then.", baselineStableN))
+		}
+	}
+	if c.smooth != nil {
+		notes = append(notes, fmt.Sprintf("Smoothing %s applies to value only; calibration uses the instantaneous volts signal.", c.smoothSpec))
+	}
+	notes = c.addSnapMeta(meta, notes)
 	if c.doTempComp {
-		notes = append(notes, fmt.Sprintf("Temperature compensation ENABLED: volts normalized to %.2f°C before TDS conversion.", c.refTempC))
-		if !injected {
+		notes = append(notes, fmt.Sprintf("Temperature compensation ENABLED: volts normalized to %.2f°C before TDS conversion.", refTempC))
+		if !injected && time.Since(c.createdAt) > tempSourceGrace {
+			meta["temp_comp_inactive"] = true
+			notes = append(notes, fmt.Sprintf(
+				"WARNING: temperature compensation enabled but no temperature source — currently inactive. Nothing has supplied a temperature in %v; set a temperature sensor for this input, configure TempChannel, or turn DoTempComp off.",
+				time.Since(c.createdAt).Round(time.Second)))
+		} else if !injected {
 			notes = append(notes, "No temperature injected yet; assuming RefTempC (normalization is no-op).")
 		} else if !updatedAt.IsZero() && time.Since(updatedAt) > tempStaleWarn {
 			notes = append(notes, fmt.Sprintf("WARNING: temperature is stale (age=%v). Check temp sensor updates.", time.Since(updatedAt)))
@@ -663,11 +1321,45 @@ func (c *tdsChannel) Snapshot() (hal.Snapshot, error) {
 	} else {
 		notes = append(notes, "Temperature compensation DISABLED: volts used as-is (raw volts after clamp).")
 	}
+	if tempRejected != "" {
+		notes = append(notes, fmt.Sprintf("WARNING: injected temperature ignored %v ago: %s.",
+			time.Since(tempRejectedAt).Round(time.Second), tempRejected))
+	}
+	if c.tempSourceCh >= 0 {
+		if _, at, ok := c.chip.temperature(c.tempSourceCh); !ok || time.Since(at) > tempStaleWarn {
+			notes = append(notes, fmt.Sprintf("TempChannel=AIN%d has no fresh published temperature; using injected temperature instead.", c.tempSourceCh))
+		}
+	}
+
+	if len(calPoints) > 0 {
+		meta["calibration"] = map[string]any{
+			"points":       calPoints,
+			"tdsK":         tdsK,
+			"offset":       tdsOffset,
+			"r2":           fit.R2,
+			"max_residual": fit.MaxResidual,
+		}
+		notes = append(notes, "Calibration updated at runtime; copy TdsK/TdsOffset from meta into the driver config to persist.")
+		if w := calTempWarning(calPoints, c.doTempComp); w != "" {
+			notes = append(notes, "WARNING: "+w)
+		}
+		if w := calFitWarning(calPoints, fit); w != "" {
+			notes = append(notes, "WARNING: "+w)
+		}
+	}
+
+	if streak := c.clip.current(); streak >= clipWarnStreak {
+		notes = append(notes, fmt.Sprintf(
+			"WARNING: last %d readings clipped high (ADC full scale or ClampV); the signal is truncated. Check Gain and ClampV.", streak))
+	}
 
 	return hal.Snapshot{
-		Value: out,
-		Unit:  "tds",
+		Value: quantize(primary, c.outputStep),
+		Unit:  c.unit(),
 		Signals: map[string]hal.Signal{
+			// Unsmoothed output of this reading
+			"value_instant": {Now: quantize(out, c.outputStep), Unit: c.unit()},
+
 			// Raw ADC
 			"raw": {Now: float64(raw), Unit: "counts"},
 
@@ -677,6 +1369,10 @@ func (c *tdsChannel) Snapshot() (hal.Snapshot, error) {
 
 			// Temperature used (refTempC if never injected)
 			"temp_c": {Now: temp, Unit: "C"},
+
+			// Short-term noise of raw counts (0 until two samples exist)
+			"noise_counts": {Now: noiseCounts, Unit: "counts"},
+			"noise_mv":     {Now: noiseMV, Unit: "mV"},
 		},
 		Meta:  meta,
 		Notes: notes,
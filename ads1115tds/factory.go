@@ -18,12 +18,16 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/reef-pi/drivers/filter"
+	"github.com/reef-pi/drivers/i2creg"
+	"github.com/reef-pi/drivers/nobus"
 	"github.com/reef-pi/hal"
-	"github.com/reef-pi/rpi/i2c"
 )
 
 const (
@@ -38,8 +42,71 @@ const (
 	paramAlphaPer   = "AlphaPerC"   // e.g. 0.02
 	paramDoTempComp = "DoTempComp"  // checkbox
 	paramRefTempC   = "RefTempC"    // reference temperature for compensation
+	paramContinuous = "Continuous"  // continuous conversion mode (skip config rewrite when unchanged)
+
+	// Advanced I2C timing knobs (bounded). Defaults are fine for almost every board.
+	paramConvTimeoutMs = "ConvTimeoutMs" // give up waiting for a conversion after this long
+	paramConvPollUs    = "ConvPollUs"    // interval between OS-bit polls
+	paramPollStrategy  = "PollStrategy"  // "poll" (OS-bit polling) or "sleep" (one conversion period, then read)
+
+	// Temperature from another channel of the same chip (-1 = off), see PublishTemperatureC
+	paramTempChannel = "TempChannel"
+
+	// Volts below 0 on the single-ended input: clamp (default), passthrough or reflect
+	paramNegativeRawPolicy = "NegativeRawPolicy"
+
+	// Custom unit / display label when the channel is not a TDS probe (e.g. "NTU", "level %")
+	paramUnitLabel = "UnitLabel"
+
+	// Several channels in one driver, as JSON (see channels.go), e.g.
+	// [{"ch":0,"gain":"1","tdsK":2.3},{"ch":2,"gain":"4"}]. Replaces Channel when set.
+	paramChannelsSpec = "ChannelsSpec"
+
+	// Add the last few raw/volts samples to snapshot meta as "history" (see history.go)
+	paramIncludeHistory = "IncludeHistory"
+
+	// Alpha by temperature, "tempC:alpha" pairs, e.g. "10:0.0215,20:0.02,30:0.019".
+	// Replaces AlphaPerC when set (see alphatable.go).
+	paramAlphaTable = "AlphaTable"
+
+	// Snap the reported value to multiples of this step (e.g. 1 or 5 ppm); 0 = off
+	paramOutputStep = "OutputStep"
+
+	// Drop the first N conversions after start-up and after a mux change (see warmup.go); 0 = off
+	paramDiscardFirstN = "DiscardFirstN"
+
+	// Temperature compensation before (normalize-then-linear, default) or after
+	// (linear-then-normalize) the TdsK/TdsOffset step
+	paramCompOrder = "CompOrder"
+
+	// Probe 0x48..0x4B at start-up and use the one address that answers (see autodetect.go)
+	paramAutoDetectAddress = "AutoDetectAddress"
+
+	// Filter for the reported value only, e.g. "ema:0.2" or "median:5" (see smooth.go)
+	paramSmoothing = "Smoothing"
+
+	// "absolute" (default) or "delta": report the change from a baseline (see baseline.go)
+	paramOutputMode = "OutputMode"
+
+	// Return ErrOutOfRange instead of clamping (strict mode for setup, see clip.go)
+	paramFailOnClamp = "FailOnClamp"
+
+	// Flag a floating input when recent raw counts spread more than this; 0 = off (see spread.go)
+	paramMaxSpreadCounts = "MaxSpreadCounts"
+
+	// Return ErrUnstableInput while the spread exceeds MaxSpreadCounts (see spread.go)
+	paramFailOnSpread = "FailOnSpread"
+
+	// Unit SetTemperatureC takes: "C" (default) or "F" (see tempunit.go)
+	paramTempUnit = "TempUnit"
+
+	// Smoothing jumps to a reading that differs from the smoothed value by more
+	// than this (output units); 0 = off (see smooth.go)
+	paramSmoothSnapThreshold = "SmoothSnapThreshold"
 )
 
+const maxUnitLabelLen = 24
+
 // Default alpha (typical conductivity temp coefficient)
 const defaultAlphaPerC = 0.02
 
@@ -61,6 +128,7 @@ func Factory() hal.DriverFactory {
 			},
 			parameters: []hal.ConfigParameter{
 				{Name: paramDebug, Type: hal.Boolean, Order: 0, Default: false},
+				// Address also accepts "0x48:2" (address:channel), which overrides Channel.
 				{Name: paramAddress, Type: hal.String, Order: 1, Default: "0x48"},
 				{Name: paramChannel, Type: hal.Integer, Order: 2, Default: 0},
 				{Name: paramGain, Type: hal.String, Order: 3, Default: "1"},
@@ -76,6 +144,32 @@ func Factory() hal.DriverFactory {
 				// Temperature compensation controls
 				{Name: paramRefTempC, Type: hal.Decimal, Order: 8, Default: 25.0},
 				{Name: paramDoTempComp, Type: hal.Boolean, Order: 9, Default: false},
+
+				// Continuous conversion: faster polling, shared chip skips config rewrites.
+				{Name: paramContinuous, Type: hal.Boolean, Order: 10, Default: false},
+
+				// Advanced: conversion wait tuning for long/busy buses.
+				{Name: paramConvTimeoutMs, Type: hal.Integer, Order: 11, Default: int(convTimeout / time.Millisecond)},
+				{Name: paramConvPollUs, Type: hal.Integer, Order: 12, Default: int(convPollWait / time.Microsecond)},
+
+				{Name: paramTempChannel, Type: hal.Integer, Order: 13, Default: -1},
+				{Name: paramNegativeRawPolicy, Type: hal.String, Order: 14, Default: negRawClamp},
+				{Name: paramUnitLabel, Type: hal.String, Order: 15, Default: ""},
+				{Name: paramPollStrategy, Type: hal.String, Order: 16, Default: pollStrategyPoll},
+				{Name: paramChannelsSpec, Type: hal.String, Order: 17, Default: ""},
+				{Name: paramIncludeHistory, Type: hal.Boolean, Order: 18, Default: false},
+				{Name: paramAlphaTable, Type: hal.String, Order: 19, Default: ""},
+				{Name: paramOutputStep, Type: hal.Decimal, Order: 20, Default: 0.0},
+				{Name: paramDiscardFirstN, Type: hal.Integer, Order: 21, Default: 0},
+				{Name: paramCompOrder, Type: hal.String, Order: 22, Default: compNormalizeFirst},
+				{Name: paramAutoDetectAddress, Type: hal.Boolean, Order: 23, Default: false},
+				{Name: paramSmoothing, Type: hal.String, Order: 24, Default: ""},
+				{Name: paramOutputMode, Type: hal.String, Order: 25, Default: outputAbsolute},
+				{Name: paramFailOnClamp, Type: hal.Boolean, Order: 26, Default: false},
+				{Name: paramMaxSpreadCounts, Type: hal.Integer, Order: 27, Default: 0},
+				{Name: paramFailOnSpread, Type: hal.Boolean, Order: 28, Default: false},
+				{Name: paramTempUnit, Type: hal.String, Order: 29, Default: "C"},
+				{Name: paramSmoothSnapThreshold, Type: hal.Decimal, Order: 30, Default: 0.0},
 			},
 		}
 	})
@@ -90,7 +184,7 @@ func (f *factory) ValidateParameters(p map[string]interface{}) (bool, map[string
 	fail := map[string][]string{}
 
 	if v, ok := getAny(p, paramAddress, "address"); ok {
-		if _, err := parseI2CAddress(v); err != nil {
+		if _, _, _, err := parseAddressChannel(v); err != nil {
 			fail[paramAddress] = append(fail[paramAddress], err.Error())
 		}
 	}
@@ -133,7 +227,7 @@ func (f *factory) ValidateParameters(p map[string]interface{}) (bool, map[string
 		fv, err := convertToFloat(v)
 		if err != nil {
 			fail[paramAlphaPer] = append(fail[paramAlphaPer], "must be a number (e.g. 0.02)")
-		} else if fv < 0 || fv > 0.1 {
+		} else if fv < minAlphaPerC || fv > maxAlphaPerC {
 			fail[paramAlphaPer] = append(fail[paramAlphaPer], "must be 0..0.1 (typical is ~0.02)")
 		}
 	}
@@ -142,13 +236,160 @@ func (f *factory) ValidateParameters(p map[string]interface{}) (bool, map[string
 		fv, err := convertToFloat(v)
 		if err != nil {
 			fail[paramRefTempC] = append(fail[paramRefTempC], "must be a number (e.g. 25.0)")
-		} else if fv < 0 || fv > 60 {
+		} else if fv < minRefTempC || fv > maxRefTempC {
 			fail[paramRefTempC] = append(fail[paramRefTempC], "must be 0..60 °C")
 		}
 	}
 
 	// DoTempComp is bool; tolerate typical values. No strict validation needed.
 
+	if v, ok := getAny(p, paramConvTimeoutMs, "convtimeoutms"); ok {
+		i, ok2 := hal.ConvertToInt(v)
+		if d := time.Duration(i) * time.Millisecond; !ok2 || d < minConvTimeout || d > maxConvTimeout {
+			fail[paramConvTimeoutMs] = append(fail[paramConvTimeoutMs],
+				fmt.Sprintf("must be %d..%d ms", minConvTimeout/time.Millisecond, maxConvTimeout/time.Millisecond))
+		}
+	}
+
+	if v, ok := getAny(p, paramConvPollUs, "convpollus"); ok {
+		i, ok2 := hal.ConvertToInt(v)
+		if d := time.Duration(i) * time.Microsecond; !ok2 || d < minConvPollWait || d > maxConvPollWait {
+			fail[paramConvPollUs] = append(fail[paramConvPollUs],
+				fmt.Sprintf("must be %d..%d µs", minConvPollWait/time.Microsecond, maxConvPollWait/time.Microsecond))
+		}
+	}
+
+	if v, ok := getAny(p, paramPollStrategy, "pollstrategy"); ok {
+		s, _ := v.(string)
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case pollStrategyPoll, pollStrategySleep:
+		default:
+			fail[paramPollStrategy] = append(fail[paramPollStrategy], "must be poll or sleep")
+		}
+	}
+
+	if v, ok := getAny(p, paramTempChannel, "tempchannel"); ok {
+		i, ok2 := hal.ConvertToInt(v)
+		ch := 0
+		if cv, ok := getAny(p, paramChannel, "channel"); ok {
+			ch, _ = hal.ConvertToInt(cv)
+		}
+		if av, ok := getAny(p, paramAddress, "address"); ok {
+			if _, c, hasC, err := parseAddressChannel(av); err == nil && hasC {
+				ch = c
+			}
+		}
+		if !ok2 || i < -1 || i > 3 {
+			fail[paramTempChannel] = append(fail[paramTempChannel], "must be -1 (off) or 0..3 (AIN0..AIN3)")
+		} else if i == ch {
+			fail[paramTempChannel] = append(fail[paramTempChannel], "must differ from Channel")
+		}
+	}
+
+	if v, ok := getAny(p, paramNegativeRawPolicy, "negativerawpolicy"); ok {
+		s, _ := v.(string)
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case negRawClamp, negRawPassthrough, negRawReflect:
+		default:
+			fail[paramNegativeRawPolicy] = append(fail[paramNegativeRawPolicy], "must be clamp, passthrough or reflect")
+		}
+	}
+
+	if v, ok := getAny(p, paramCompOrder, "comporder"); ok {
+		s, _ := v.(string)
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case compNormalizeFirst, compLinearFirst:
+		default:
+			fail[paramCompOrder] = append(fail[paramCompOrder], "must be "+compNormalizeFirst+" or "+compLinearFirst)
+		}
+	}
+
+	if v, ok := getAny(p, paramTempUnit, "tempunit"); ok {
+		s, _ := v.(string)
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case tempUnitC, tempUnitF:
+		default:
+			fail[paramTempUnit] = append(fail[paramTempUnit], "must be C or F")
+		}
+	}
+
+	if v, ok := getAny(p, paramOutputMode, "outputmode"); ok {
+		s, _ := v.(string)
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case outputAbsolute, outputDelta:
+		default:
+			fail[paramOutputMode] = append(fail[paramOutputMode], "must be "+outputAbsolute+" or "+outputDelta)
+		}
+	}
+
+	if v, ok := getAny(p, paramUnitLabel, "unitlabel", "unit"); ok {
+		s, ok2 := v.(string)
+		if !ok2 {
+			fail[paramUnitLabel] = append(fail[paramUnitLabel], "must be a string")
+		} else if s = strings.TrimSpace(s); len([]rune(s)) > maxUnitLabelLen {
+			fail[paramUnitLabel] = append(fail[paramUnitLabel], fmt.Sprintf("must be at most %d characters", maxUnitLabelLen))
+		} else if strings.ContainsAny(s, "\r\n\t") {
+			fail[paramUnitLabel] = append(fail[paramUnitLabel], "must be a single line")
+		}
+	}
+
+	if v, ok := getAny(p, paramOutputStep, "outputstep"); ok {
+		step, err := convertToFloat(v)
+		if err != nil {
+			fail[paramOutputStep] = append(fail[paramOutputStep], "must be a number (e.g. 1 or 5)")
+		} else if step < 0 || math.IsNaN(step) {
+			fail[paramOutputStep] = append(fail[paramOutputStep], "must be >= 0 (0 = off)")
+		}
+	}
+
+	if v, ok := getAny(p, paramDiscardFirstN, "discardfirstn"); ok {
+		if i, ok2 := hal.ConvertToInt(v); !ok2 || i < 0 || i > maxDiscardFirstN {
+			fail[paramDiscardFirstN] = append(fail[paramDiscardFirstN], fmt.Sprintf("must be 0..%d (0 = off)", maxDiscardFirstN))
+		}
+	}
+
+	if v, ok := getAny(p, paramMaxSpreadCounts, "maxspreadcounts"); ok {
+		if i, ok2 := hal.ConvertToInt(v); !ok2 || i < 0 || i > maxMaxSpreadCounts {
+			fail[paramMaxSpreadCounts] = append(fail[paramMaxSpreadCounts], fmt.Sprintf("must be 0..%d counts (0 = off)", maxMaxSpreadCounts))
+		}
+	}
+
+	if v, ok := getAny(p, paramSmoothing, "smoothing"); ok {
+		if s, ok2 := v.(string); !ok2 {
+			fail[paramSmoothing] = append(fail[paramSmoothing], "must be a string like ema:0.2 or median:5")
+		} else if _, err := parseSmoothing(s); err != nil {
+			fail[paramSmoothing] = append(fail[paramSmoothing], err.Error())
+		}
+	}
+
+	if v, ok := getAny(p, paramSmoothSnapThreshold, "smoothsnapthreshold"); ok {
+		th, err := convertToFloat(v)
+		if err != nil {
+			fail[paramSmoothSnapThreshold] = append(fail[paramSmoothSnapThreshold], "must be a number (e.g. 50)")
+		} else if th < 0 || math.IsNaN(th) || math.IsInf(th, 0) {
+			fail[paramSmoothSnapThreshold] = append(fail[paramSmoothSnapThreshold], "must be >= 0 (0 = off)")
+		}
+	}
+
+	if v, ok := getAny(p, paramAlphaTable, "alphatable"); ok {
+		if s, ok2 := v.(string); !ok2 {
+			fail[paramAlphaTable] = append(fail[paramAlphaTable], "must be a string like 10:0.0215,20:0.02,30:0.019")
+		} else if _, err := parseAlphaTable(s); err != nil {
+			fail[paramAlphaTable] = append(fail[paramAlphaTable], err.Error())
+		}
+	}
+
+	if specs, err := parseChannelsSpec(p); err != nil {
+		fail[paramChannelsSpec] = append(fail[paramChannelsSpec], err.Error())
+	} else if v, ok := getAny(p, paramTempChannel, "tempchannel"); ok {
+		tempCh, _ := hal.ConvertToInt(v)
+		for _, s := range specs {
+			if *s.Ch == tempCh {
+				fail[paramChannelsSpec] = append(fail[paramChannelsSpec], fmt.Sprintf("ch %d is the TempChannel", *s.Ch))
+			}
+		}
+	}
+
 	return len(fail) == 0, fail
 }
 
@@ -167,28 +408,45 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
 		}
 	}
 
-	bus, ok := hardwareResources.(i2c.Bus)
-	if !ok {
-		return nil, fmt.Errorf("ads1115tds: expected i2c.Bus as hardware resource, got %T", hardwareResources)
+	// A nil hardware resource gives a config-only driver: DescribePipeline and
+	// metadata work, conversions fail with nobus.ErrNoBus.
+	bus, err := nobus.I2C("ads1115tds", hardwareResources)
+	if err != nil {
+		return nil, err
 	}
 
-	// Address default (0x48) unless overridden
+	// Address default (0x48) unless overridden; "0x48:2" also selects the channel
 	addr := byte(0x48)
+	addrCh, hasAddrCh := 0, false
 	if v, ok := getAny(parameters, paramAddress, "address"); ok {
-		a, err := parseI2CAddress(v)
+		a, c, hasC, err := parseAddressChannel(v)
 		if err != nil {
 			return nil, err
 		}
+		addr, addrCh, hasAddrCh = a, c, hasC
+	}
+
+	// AutoDetectAddress replaces the address part of Address; a "0x48:2"
+	// channel still applies. Config-only drivers have nothing to probe.
+	if getBoolAny(parameters, false, paramAutoDetectAddress, "autodetectaddress") && !nobus.Is(bus) {
+		a, err := detectAddress(bus)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("ads1115tds AutoDetectAddress: found ADS1115 at 0x%02X", a)
 		addr = a
 	}
 
-	// Channel default 0 unless overridden
+	// Channel default 0 unless overridden; the Address shorthand wins
 	ch := 0
 	if v, ok := getAny(parameters, paramChannel, "channel"); ok {
 		if i, ok2 := hal.ConvertToInt(v); ok2 {
 			ch = i
 		}
 	}
+	if hasAddrCh {
+		ch = addrCh
+	}
 
 	mux, okMux := muxForChannel(ch)
 	if !okMux {
@@ -218,6 +476,7 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
 	// Temp compensation controls
 	refTempC := getFloatAny(parameters, 25.0, paramRefTempC, "reftempc", "ref_temp_c")
 	doTempComp := getBoolAny(parameters, false, paramDoTempComp, "dotempcomp", "do_tc", "dotc")
+	continuous := getBoolAny(parameters, false, paramContinuous, "continuous")
 
 	if debug {
 		fs, _ := fsVoltsForGain(gain)
@@ -232,48 +491,164 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
 		alpha,
 		doTempComp,
 		refTempC,
+		continuous,
 		debug,
 		f.meta,
 	)
 
+	applyChannelOptions(pin, parameters)
+
+	if doTempComp && pin.tempSourceCh < 0 {
+		log.Printf("ads1115tds addr=0x%02X ch=%d: DoTempComp is on but there is no TempChannel; compensation stays inactive until a temperature is injected (flagged in snapshot after %v)",
+			addr, ch, tempSourceGrace)
+	}
+
 	// Keep a one-line init log (useful even when debug=false)
-	log.Printf("ads1115tds init addr=0x%02X ch=%d gain=0x%04X k=%.6f off=%.6f clampV=%.3f alpha=%.4f DoTC=%v RefTempC=%.2f debug=%v",
-		addr, ch, gain, tdsK, tdsOff, clampV, alpha, doTempComp, refTempC, debug)
+	log.Printf("ads1115tds init addr=0x%02X ch=%d gain=0x%04X k=%.6f off=%.6f clampV=%.3f alpha=%.4f DoTC=%v RefTempC=%.2f continuous=%v tempCh=%d debug=%v",
+		addr, ch, gain, tdsK, tdsOff, clampV, alpha, doTempComp, refTempC, continuous, pin.tempSourceCh, debug)
+
+	pins := []*tdsChannel{pin}
+	if specs, _ := parseChannelsSpec(parameters); len(specs) > 0 {
+		// ChannelsSpec replaces the single Channel; every entry inherits the
+		// driver-wide values above and overrides what it sets.
+		pins = pins[:0]
+		for _, s := range specs {
+			c := s.newChannel(bus, addr, pin, parameters)
+			log.Printf("ads1115tds init addr=0x%02X ch=%d (ChannelsSpec) gain=0x%04X k=%.6f off=%.6f clampV=%.3f unit=%q",
+				addr, c.channel, c.gainConfig, c.tdsK, c.tdsOffset, c.clampV, c.unit())
+			pins = append(pins, c)
+		}
+	}
+
+	// Every channel of one ADS1115 belongs to this driver type; cooperating
+	// drivers on the same chip use ChipLock rather than a second claim.
+	var claim *i2creg.Handle
+	if !nobus.Is(bus) {
+		if claim, err = i2creg.Claim(bus, addr, driverType); err != nil {
+			return nil, err
+		}
+	}
 
 	return &Driver{
-		meta: f.meta,
-		pin:  pin,
+		meta:  f.meta,
+		pins:  pins,
+		claim: claim,
 	}, nil
 }
 
-// ---------- parsing helpers ----------
+// applyChannelOptions sets the optional per-channel tuning parameters on c.
+func applyChannelOptions(c *tdsChannel, parameters map[string]interface{}) {
+	if v, ok := getAny(parameters, paramConvTimeoutMs, "convtimeoutms"); ok {
+		if i, ok2 := hal.ConvertToInt(v); ok2 {
+			c.convTimeout = time.Duration(i) * time.Millisecond
+		}
+	}
+	if v, ok := getAny(parameters, paramConvPollUs, "convpollus"); ok {
+		if i, ok2 := hal.ConvertToInt(v); ok2 {
+			c.pollWait = time.Duration(i) * time.Microsecond
+		}
+	}
 
-// parseI2CAddress accepts "0x48" or int-like values and returns a 7-bit address.
-func parseI2CAddress(v interface{}) (byte, error) {
-	switch t := v.(type) {
-	case string:
-		s := strings.TrimSpace(t)
-		base := 10
-		if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
-			base = 0
+	if v, ok := getAny(parameters, paramPollStrategy, "pollstrategy"); ok {
+		if s, ok2 := v.(string); ok2 {
+			c.pollStrategy = strings.ToLower(strings.TrimSpace(s))
 		}
-		n64, err := strconv.ParseInt(s, base, 32)
-		if err != nil {
-			return 0, fmt.Errorf("Address must be int or hex string like 0x48: %v", err)
+	}
+
+	if v, ok := getAny(parameters, paramTempChannel, "tempchannel"); ok {
+		if i, ok2 := hal.ConvertToInt(v); ok2 {
+			c.tempSourceCh = i
 		}
-		if n64 < 0 || n64 > 127 {
-			return 0, fmt.Errorf("Address out of range (0..127): %d", n64)
+	}
+
+	if v, ok := getAny(parameters, paramNegativeRawPolicy, "negativerawpolicy"); ok {
+		if s, ok2 := v.(string); ok2 {
+			c.negRawPolicy = strings.ToLower(strings.TrimSpace(s))
 		}
-		return byte(n64), nil
-	default:
-		if i, ok := hal.ConvertToInt(v); ok {
-			if i < 0 || i > 127 {
-				return 0, fmt.Errorf("Address out of range (0..127): %d", i)
+	}
+
+	if v, ok := getAny(parameters, paramCompOrder, "comporder"); ok {
+		if s, ok2 := v.(string); ok2 {
+			c.compOrder = strings.ToLower(strings.TrimSpace(s))
+		}
+	}
+
+	if v, ok := getAny(parameters, paramUnitLabel, "unitlabel", "unit"); ok {
+		if s, ok2 := v.(string); ok2 {
+			c.unitLabel = strings.TrimSpace(s)
+		}
+	}
+
+	if v, ok := getAny(parameters, paramTempUnit, "tempunit"); ok {
+		if s, ok2 := v.(string); ok2 {
+			c.tempUnit = strings.ToLower(strings.TrimSpace(s))
+		}
+	}
+
+	if v, ok := getAny(parameters, paramOutputMode, "outputmode"); ok {
+		if s, ok2 := v.(string); ok2 {
+			c.outputMode = strings.ToLower(strings.TrimSpace(s))
+		}
+	}
+
+	c.includeHistory = getBoolAny(parameters, false, paramIncludeHistory, "includehistory")
+	c.failOnClamp = getBoolAny(parameters, false, paramFailOnClamp, "failonclamp")
+	c.failOnSpread = getBoolAny(parameters, false, paramFailOnSpread, "failonspread")
+	if v, ok := getAny(parameters, paramMaxSpreadCounts, "maxspreadcounts"); ok {
+		if i, ok2 := hal.ConvertToInt(v); ok2 && i > 0 {
+			c.maxSpreadCounts = i
+		}
+	}
+
+	c.outputStep = getFloatAny(parameters, 0, paramOutputStep, "outputstep")
+
+	// Each channel gets its own filter instance.
+	if v, ok := getAny(parameters, paramSmoothing, "smoothing"); ok {
+		if s, ok2 := v.(string); ok2 {
+			if sm, err := parseSmoothing(s); err == nil && sm != nil {
+				c.smooth, c.smoothSpec = sm, strings.ToLower(strings.TrimSpace(s))
+				c.snapThreshold = getFloatAny(parameters, 0, paramSmoothSnapThreshold, "smoothsnapthreshold")
+				c.smooth = filter.SnapOnJump(c.smooth, c.snapThreshold)
 			}
-			return byte(i), nil
 		}
-		return 0, fmt.Errorf("Address must be int or hex string like 0x48")
 	}
+
+	if v, ok := getAny(parameters, paramDiscardFirstN, "discardfirstn"); ok {
+		if i, ok2 := hal.ConvertToInt(v); ok2 && i > 0 {
+			c.discardFirstN, c.warmupLeft = i, i
+		}
+	}
+
+	if v, ok := getAny(parameters, paramAlphaTable, "alphatable"); ok {
+		if s, ok2 := v.(string); ok2 {
+			c.alphaTable, _ = parseAlphaTable(s)
+		}
+	}
+}
+
+// ---------- parsing helpers ----------
+
+// parseAddressChannel accepts everything i2creg.ParseI2CAddress does plus the
+// "0x48:2" shorthand (address 0x48, channel 2). hasCh reports whether a channel was given.
+func parseAddressChannel(v interface{}) (addr byte, ch int, hasCh bool, err error) {
+	s, ok := v.(string)
+	if !ok {
+		addr, err = i2creg.ParseI2CAddress(v)
+		return addr, 0, false, err
+	}
+	i := strings.IndexByte(s, ':')
+	if i < 0 {
+		addr, err = i2creg.ParseI2CAddress(s)
+		return addr, 0, false, err
+	}
+	if addr, err = i2creg.ParseI2CAddress(s[:i]); err != nil {
+		return 0, 0, false, err
+	}
+	ch, err = strconv.Atoi(strings.TrimSpace(s[i+1:]))
+	if err != nil || ch < 0 || ch > 3 {
+		return 0, 0, false, fmt.Errorf("Address channel must be 0..3 (AIN0..AIN3) as in 0x48:2, got %q", s[i+1:])
+	}
+	return addr, ch, true, nil
 }
 
 // parseGain accepts "2/3", "1", "2", "4", "8", "16" or int 0..5.
@@ -0,0 +1,40 @@
+// gain.go
+//
+// MeasureWithGain: one reading at an explicit PGA gain.
+//
+// Value() and Measure() always convert at the configured Gain. Diagnostics and
+// calibration procedures (e.g. sweeping gains to find where a probe clips)
+// can pick the gain for a single read instead. The read takes the shared chip
+// lock like any other, so the config word is rebuilt and re-latched with the
+// requested gain and the next normal read switches back to the configured one.
+// The result goes through the conversion pipeline (ClampV, temperature
+// compensation, TdsK/TdsOffset) and Stats like a normal read, but counts at a
+// foreign gain would corrupt the per-channel state: a read at any other gain
+// never feeds the noise ring, clip streak, history or smoothing and does not
+// replace the last reading. Its Smoothed equals Value.
+//
+package ads1115tds
+
+import (
+	"fmt"
+)
+
+// PGA gains accepted by MeasureWithGain (config register values).
+const (
+	GainTwoThirds = configGainTwoThirds // ±6.144V
+	GainOne       = configGainOne       // ±4.096V
+	GainTwo       = configGainTwo       // ±2.048V
+	GainFour      = configGainFour      // ±1.024V
+	GainEight     = configGainEight     // ±0.512V
+	GainSixteen   = configGainSixteen   // ±0.256V
+)
+
+// MeasureWithGain runs one conversion at gain (one of the Gain* constants)
+// instead of the configured Gain and returns every pipeline stage.
+func (c *tdsChannel) MeasureWithGain(gain uint16) (Reading, error) {
+	if _, ok := fsVoltsForGain(gain); !ok {
+		return Reading{}, fmt.Errorf("ads1115tds addr=0x%02X ch=%d: MeasureWithGain: unknown gain 0x%04X (use a Gain* constant)",
+			c.address, c.channel, gain)
+	}
+	return c.measureGain(nil, gain)
+}
@@ -0,0 +1,56 @@
+// history.go
+//
+// Recent sample history for the calibration UI.
+//
+// With IncludeHistory set, every conversion is kept in a small ring and Snapshot
+// adds it as meta "history" (oldest first), so the wizard can draw a sparkline
+// and judge stability without polling. Off by default to keep snapshots small.
+//
+package ads1115tds
+
+import (
+	"sync"
+	"time"
+)
+
+// historyWindow is the number of recent samples reported in meta "history".
+const historyWindow = 16
+
+type historySample struct {
+	at    time.Time
+	raw   int16
+	volts float64
+}
+
+type historyRing struct {
+	mu   sync.Mutex
+	buf  []historySample
+	next int
+}
+
+func (r *historyRing) add(s historySample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.buf) < historyWindow {
+		r.buf = append(r.buf, s)
+		return
+	}
+	r.buf[r.next] = s
+	r.next = (r.next + 1) % historyWindow
+}
+
+// meta returns the samples oldest first, shaped for snapshot meta.
+func (r *historyRing) meta() []map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]map[string]any, 0, len(r.buf))
+	for i := range r.buf {
+		s := r.buf[(r.next+i)%len(r.buf)]
+		out = append(out, map[string]any{
+			"t":     s.at.Format(time.RFC3339Nano),
+			"raw":   s.raw,
+			"volts": s.volts,
+		})
+	}
+	return out
+}
@@ -0,0 +1,56 @@
+// noise.go
+//
+// Short-term noise of the raw ADC counts.
+//
+// Every conversion feeds a small ring buffer; Snapshot reports the sample standard
+// deviation as noise_counts / noise_mv. A jump usually means a ground or shielding
+// problem in the analog front-end rather than a change in the solution.
+//
+package ads1115tds
+
+import (
+	"math"
+	"sync"
+)
+
+// noiseWindow is the number of recent raw samples used for the noise estimate.
+const noiseWindow = 32
+
+type noiseRing struct {
+	mu   sync.Mutex
+	buf  []int16
+	next int
+}
+
+func (r *noiseRing) add(raw int16) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.buf) < noiseWindow {
+		r.buf = append(r.buf, raw)
+		return
+	}
+	r.buf[r.next] = raw
+	r.next = (r.next + 1) % noiseWindow
+}
+
+// stddev returns the sample standard deviation in counts and the sample count.
+// ok is false until at least two samples exist.
+func (r *noiseRing) stddev() (counts float64, n int, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n = len(r.buf)
+	if n < 2 {
+		return 0, n, false
+	}
+	mean := 0.0
+	for _, v := range r.buf {
+		mean += float64(v)
+	}
+	mean /= float64(n)
+	ss := 0.0
+	for _, v := range r.buf {
+		d := float64(v) - mean
+		ss += d * d
+	}
+	return math.Sqrt(ss / float64(n-1)), n, true
+}
@@ -0,0 +1,85 @@
+// registers.go
+//
+// Last register traffic, for support.
+//
+// Each channel keeps the config word of its last conversion and the two bytes
+// read back from the conversion register. Snapshot reports them (and the
+// config word decoded field by field) so a bug report with one snapshot dump
+// shows exactly what the chip was told and what it answered, without turning
+// on Debug and reproducing. Only the last values are kept.
+//
+package ads1115tds
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Config word fields beyond those in driver.go.
+const (
+	configMuxMask      uint16 = 0x7000
+	configDataRateMask uint16 = 0x00E0
+	configCompQueMask  uint16 = 0x0003
+)
+
+// dataRatesSPS are the ADS1115 data rates by DR field value (bits 7:5).
+var dataRatesSPS = [8]int{8, 16, 32, 64, 128, 250, 475, 860}
+
+type regTrace struct {
+	mu       sync.Mutex
+	config   uint16
+	conv     [2]byte
+	haveCfg  bool
+	haveConv bool
+}
+
+func (r *regTrace) setConfig(config uint16) {
+	r.mu.Lock()
+	r.config, r.haveCfg = config, true
+	r.mu.Unlock()
+}
+
+func (r *regTrace) setConv(b []byte) {
+	r.mu.Lock()
+	r.conv[0], r.conv[1], r.haveConv = b[0], b[1], true
+	r.mu.Unlock()
+}
+
+// addMeta adds last_config_hex, last_config (decoded) and last_conv_bytes to
+// meta once a conversion has run.
+func (r *regTrace) addMeta(meta map[string]any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.haveCfg {
+		meta["last_config_hex"] = fmt.Sprintf("0x%04X", r.config)
+		meta["last_config"] = decodeConfig(r.config)
+	}
+	if r.haveConv {
+		meta["last_conv_bytes"] = fmt.Sprintf("%02X %02X", r.conv[0], r.conv[1])
+	}
+}
+
+// decodeConfig splits an ADS1115 config word into its fields.
+func decodeConfig(cfg uint16) map[string]any {
+	mux := (cfg & configMuxMask) >> 12
+	muxName := fmt.Sprintf("AIN%d-GND", mux-4)
+	if mux < 4 {
+		muxName = [4]string{"AIN0-AIN1", "AIN0-AIN3", "AIN1-AIN3", "AIN2-AIN3"}[mux]
+	}
+	mode := "continuous"
+	if cfg&configModeSingle != 0 {
+		mode = "single-shot"
+	}
+	comp := "enabled"
+	if cfg&configCompQueMask == configComparitorQueueNone {
+		comp = "disabled"
+	}
+	return map[string]any{
+		"os":         cfg&configOsSingle != 0,
+		"mux":        muxName,
+		"gain":       gainLabel(cfg & configGainMask),
+		"mode":       mode,
+		"data_rate":  dataRatesSPS[(cfg&configDataRateMask)>>5],
+		"comparator": comp,
+	}
+}
@@ -0,0 +1,91 @@
+// smooth.go
+//
+// Smoothing of the reported value.
+//
+// Smoothing takes a filter spec (see filter.Parse: "median:5", "ema:0.2",
+// "window:30s") and applies it to the calibrated output only. Measure and the
+// snapshot "value" are smoothed; "value_instant" keeps the unsmoothed output,
+// and the calibration wizard still reads the instantaneous "volts" signal, so
+// the filter's lag never ends up in a fitted TdsK/TdsOffset.
+//
+// SmoothSnapThreshold adds jump detection: a reading further than the
+// threshold from the smoothed value resets the filter, so the value snaps to
+// the new level (e.g. after dosing) instead of lagging behind it, while small
+// changes are still smoothed. Snaps are counted and flagged in snapshot meta.
+//
+package ads1115tds
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/reef-pi/drivers/filter"
+)
+
+// smoothLocked feeds v into the channel's filter and returns the smoothed
+// value; v itself when Smoothing is off. Caller holds c.lastMu.
+func (c *tdsChannel) smoothLocked(v float64) float64 {
+	if c.smooth == nil {
+		return v
+	}
+	out := c.smooth.Add(v)
+	c.snapped = false
+	if s, ok := c.smooth.(filter.Snapper); ok && s.Snapped() {
+		c.snapped, c.snaps, c.lastSnapAt = true, c.snaps+1, time.Now()
+		if c.debug {
+			c.dbg("smoothing snapped to %.3f (step larger than SmoothSnapThreshold=%g)", v, c.snapThreshold)
+		}
+	}
+	return out
+}
+
+// addSnapMeta reports SmoothSnapThreshold and whether the last reading snapped.
+func (c *tdsChannel) addSnapMeta(meta map[string]any, notes []string) []string {
+	if c.smooth == nil || c.snapThreshold <= 0 {
+		return notes
+	}
+	c.lastMu.Lock()
+	snapped, snaps, at := c.snapped, c.snaps, c.lastSnapAt
+	c.lastMu.Unlock()
+
+	meta["smooth_snap_threshold"] = c.snapThreshold
+	meta["smooth_snapped"] = snapped
+	meta["smooth_snaps"] = snaps
+	if !at.IsZero() {
+		meta["smooth_last_snap_age_sec"] = time.Since(at).Seconds()
+	}
+	if snapped {
+		notes = append(notes, fmt.Sprintf("Smoothing snapped to this reading: it moved more than SmoothSnapThreshold=%g %s from the smoothed value.",
+			c.snapThreshold, c.unit()))
+	}
+	return notes
+}
+
+// resetSmoothing drops the filter history, e.g. after the coefficients
+// change, so old outputs are not averaged into new ones.
+func (c *tdsChannel) resetSmoothing() {
+	c.lastMu.Lock()
+	defer c.lastMu.Unlock()
+	if c.smooth != nil {
+		c.smooth.Reset()
+	}
+	c.snapped = false
+}
+
+// smoothingName is the Smoothing spec for meta and debug lines ("none" when off).
+func (c *tdsChannel) smoothingName() string {
+	if c.smooth == nil {
+		return "none"
+	}
+	return c.smoothSpec
+}
+
+// parseSmoothing returns the filter for spec; nil for "" or "none".
+func parseSmoothing(spec string) (filter.Filter, error) {
+	switch strings.ToLower(strings.TrimSpace(spec)) {
+	case "", "none":
+		return nil, nil
+	}
+	return filter.Parse(spec)
+}
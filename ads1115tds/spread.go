@@ -0,0 +1,84 @@
+// spread.go
+//
+// Floating-input detection from the spread of recent raw counts.
+//
+// A disconnected probe leaves the ADC input floating: consecutive conversions
+// swing over a wide range instead of sitting near one value. When
+// MaxSpreadCounts is set, the spread (max - min) of the last spreadWindow raw
+// counts is compared against it and Snapshot flags "unstable/floating input".
+// FailOnSpread is the strict variant: Measure returns ErrUnstableInput while
+// the spread stays above the limit.
+//
+// The window is short on purpose so the flag clears within a few reads after
+// the probe is reconnected.
+//
+package ads1115tds
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnstableInput is returned (wrapped) with FailOnSpread when recent raw
+// counts spread more than MaxSpreadCounts.
+var ErrUnstableInput = errors.New("ads1115: unstable input")
+
+const (
+	// spreadWindow is the number of most recent raw counts compared.
+	spreadWindow = 8
+
+	// Fewer samples than this never flag (start-up, first reads after a gap).
+	spreadMinSamples = 4
+
+	// Upper bound for MaxSpreadCounts: the full int16 range.
+	maxMaxSpreadCounts = 65535
+)
+
+// spread returns max - min over the last k raw counts and how many were used.
+func (r *noiseRing) spread(k int) (counts int, n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n = len(r.buf)
+	if k < n {
+		n = k
+	}
+	if n == 0 {
+		return 0, 0
+	}
+	// Newest sample sits just before next once the ring is full, else at the end.
+	end := len(r.buf)
+	if len(r.buf) == noiseWindow {
+		end = r.next
+	}
+	lo, hi := 0, 0
+	for i := 0; i < n; i++ {
+		v := int(r.buf[(end-1-i+len(r.buf))%len(r.buf)])
+		if i == 0 || v < lo {
+			lo = v
+		}
+		if i == 0 || v > hi {
+			hi = v
+		}
+	}
+	return hi - lo, n
+}
+
+// unstable reports whether the recent spread exceeds MaxSpreadCounts; always
+// false when MaxSpreadCounts is 0.
+func (c *tdsChannel) unstable() (flag bool, counts, n int) {
+	counts, n = c.noise.spread(spreadWindow)
+	return c.maxSpreadCounts > 0 && n >= spreadMinSamples && counts > c.maxSpreadCounts, counts, n
+}
+
+// spreadError returns the FailOnSpread error, nil when FailOnSpread is off or
+// the input is stable.
+func (c *tdsChannel) spreadError() error {
+	if !c.failOnSpread {
+		return nil
+	}
+	if flag, counts, n := c.unstable(); flag {
+		return fmt.Errorf("%w: raw counts spread %d over the last %d reads (MaxSpreadCounts=%d); floating input, probe disconnected?",
+			ErrUnstableInput, counts, n, c.maxSpreadCounts)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// stats.go
+//
+// Per-channel read counters.
+//
+// Every pass through measure is counted: successful readings, failures split
+// by cause, and readings that hit a clamp or look like a disconnected input.
+// Counters live on the channel and are guarded by lastMu together with the
+// last reading. They are reported by Stats() and in Snapshot meta "stats", so
+// a flaky probe shows up as an error rate rather than scattered log lines.
+//
+package ads1115tds
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrConversionTimeout is returned when a single-shot conversion never reports
+// ready within ConvTimeoutMs.
+var ErrConversionTimeout = errors.New("ads1115: conversion timeout")
+
+// Stats holds the read counters for one channel.
+type Stats struct {
+	Reads     uint64 `json:"reads"`      // successful readings
+	Errors    uint64 `json:"errors"`     // failed readings, any cause
+	Timeouts  uint64 `json:"timeouts"`   // conversion never finished
+	I2CErrors uint64 `json:"i2c_errors"` // bus read/write failures
+	WarmUp    uint64 `json:"warm_up"`    // reads refused during DiscardFirstN (not errors)
+
+	ClampHigh uint64 `json:"clamp_high"` // volts limited to ClampV or ADC full scale
+	ClampLow  uint64 `json:"clamp_low"`  // negative volts clamped to 0
+
+	// DisconnectSuspect counts readings pinned at either ADC rail, which on a
+	// single-ended input usually means an open or shorted probe connection.
+	DisconnectSuspect uint64 `json:"disconnect_suspect"`
+
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at,omitempty"`
+}
+
+// Stats returns a copy of the channel's read counters.
+func (c *tdsChannel) Stats() Stats {
+	c.lastMu.Lock()
+	defer c.lastMu.Unlock()
+	return c.stats
+}
+
+// recordError counts a failed reading; bus marks errors from the conversion
+// itself (I2C unless it is a timeout). ErrWarmingUp is counted separately.
+func (c *tdsChannel) recordError(err error, bus bool) {
+	c.lastMu.Lock()
+	defer c.lastMu.Unlock()
+	if errors.Is(err, ErrWarmingUp) {
+		c.stats.WarmUp++
+		return
+	}
+	c.stats.Errors++
+	switch {
+	case errors.Is(err, ErrConversionTimeout):
+		c.stats.Timeouts++
+	case bus:
+		c.stats.I2CErrors++
+	}
+	c.stats.LastError = err.Error()
+	c.stats.LastErrorAt = time.Now()
+}
+
+// recordReadLocked counts a successful reading. Caller holds c.lastMu.
+func (c *tdsChannel) recordReadLocked(raw int16, clampedHigh, clampedLow bool) {
+	c.stats.Reads++
+	if clampedHigh {
+		c.stats.ClampHigh++
+	}
+	if clampedLow {
+		c.stats.ClampLow++
+	}
+	if raw == math.MaxInt16 || raw == math.MinInt16 {
+		c.stats.DisconnectSuspect++
+	}
+}
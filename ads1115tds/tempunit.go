@@ -0,0 +1,122 @@
+// tempunit.go
+//
+// TempUnit: injected temperatures in °F.
+//
+// SetTemperatureC is the hook the core calls, and it has always meant °C. With
+// TempUnit=F the same hook takes °F (for setups whose temperature source
+// reports Fahrenheit); SetTemperatureF always takes °F. Either way the value is
+// converted to °C before it is stored.
+//
+// Every injected value must land in a plausible water range after conversion.
+// A value outside it is dropped (the previous temperature stays in use) and
+// reported in snapshot meta, with a hint when the other unit would have fit:
+// "77" injected as °C is rejected with "looks like °F (25.0 °C)".
+//
+package ads1115tds
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	tempUnitC = "c"
+	tempUnitF = "f"
+
+	// Plausible water temperature after conversion; anything else is a unit
+	// mismatch or a broken sensor, never a reason to compensate.
+	minWaterTempC = 0.0
+	maxWaterTempC = 40.0
+)
+
+// FahrenheitSetter is the °F counterpart of TemperatureSetter.
+type FahrenheitSetter interface {
+	SetTemperatureF(tempF float64)
+}
+
+// tempUnitLabel returns "C" or "F" for display.
+func tempUnitLabel(unit string) string { return strings.ToUpper(unit) }
+
+func fToC(f float64) float64 { return (f - 32) * 5 / 9 }
+
+func plausibleWaterTempC(t float64) bool {
+	return !math.IsNaN(t) && t >= minWaterTempC && t <= maxWaterTempC
+}
+
+// checkInjectedTemp validates value given in unit (tempUnitC/tempUnitF) and
+// returns it in °C, or a reason it was rejected.
+func checkInjectedTemp(value float64, unit string) (tempC float64, reason string) {
+	tempC = value
+	if unit == tempUnitF {
+		tempC = fToC(value)
+	}
+	if plausibleWaterTempC(tempC) {
+		return tempC, ""
+	}
+	switch {
+	case unit == tempUnitF && plausibleWaterTempC(value):
+		reason = fmt.Sprintf("%.2f °F is %.2f °C, outside %g..%g °C; looks like °C, set TempUnit=C",
+			value, tempC, minWaterTempC, maxWaterTempC)
+	case unit == tempUnitF:
+		reason = fmt.Sprintf("%.2f °F is %.2f °C, outside %g..%g °C", value, tempC, minWaterTempC, maxWaterTempC)
+	case plausibleWaterTempC(fToC(value)):
+		reason = fmt.Sprintf("%.2f °C is outside %g..%g °C; looks like °F (%.1f °C), set TempUnit=F or use SetTemperatureF",
+			value, minWaterTempC, maxWaterTempC, fToC(value))
+	default:
+		reason = fmt.Sprintf("%.2f °C is outside %g..%g °C", value, minWaterTempC, maxWaterTempC)
+	}
+	return tempC, reason
+}
+
+// SetTemperatureF injects a temperature in °F (converted to °C internally).
+func (c *tdsChannel) SetTemperatureF(tempF float64) {
+	c.injectTemperature(tempF, tempUnitF)
+}
+
+// SetTemperatureF forwards a °F temperature to every channel.
+func (d *Driver) SetTemperatureF(tempF float64) {
+	for _, p := range d.pins {
+		p.SetTemperatureF(tempF)
+	}
+}
+
+// injectTemperature stores value (in unit) as the compensation temperature,
+// or records why it was dropped. A rejection is logged when its reason
+// changes (every time with Debug), so a feed stuck on a bad value does not
+// flood the log; Snapshot always shows the latest one.
+func (c *tdsChannel) injectTemperature(value float64, unit string) {
+	tempC, reason := checkInjectedTemp(value, unit)
+
+	c.tempMu.Lock()
+	if reason != "" {
+		changed := reason != c.tempRejected
+		c.tempRejected, c.tempRejectedAt = reason, time.Now()
+		c.tempMu.Unlock()
+		if changed || c.debug {
+			log.Printf("ads1115tds addr=0x%02X ch=%d: injected temperature ignored: %s", c.address, c.channel, reason)
+		}
+		return
+	}
+	old := c.tempC
+	c.tempC = tempC
+	c.tempUpdatedAt = time.Now()
+	c.tempRejected = ""
+	alpha, refTempC := c.alphaPerC, c.refTempC
+	c.tempMu.Unlock()
+
+	if c.debug {
+		log.Printf("ads1115tds addr=0x%02X ch=%d SetTemperature: %.2f%s -> %.2fC (was %.2fC, DoTempComp=%v RefTempC=%.2f alpha=%.4f)",
+			c.address, c.channel, value, tempUnitLabel(unit), tempC, old, c.doTempComp, refTempC, alpha)
+	}
+}
+
+// tempRejection returns the reason the last injected temperature was dropped
+// ("" once a plausible one arrives).
+func (c *tdsChannel) tempRejection() (reason string, at time.Time) {
+	c.tempMu.Lock()
+	defer c.tempMu.Unlock()
+	return c.tempRejected, c.tempRejectedAt
+}
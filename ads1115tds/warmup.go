@@ -0,0 +1,76 @@
+// warmup.go
+//
+// DiscardFirstN: throw away unsettled conversions.
+//
+// The first result after power-up, or after the mux switches to another input,
+// can still carry charge from the previous state. With DiscardFirstN=N:
+//   - the first N reads after construction fail with ErrWarmingUp (one
+//     conversion is spent and dropped per read), so the first reported value
+//     is conversion N+1
+//   - when the shared chip last converted a different channel, N extra
+//     conversions are taken and dropped inside the same read while the chip
+//     lock is held, so multi-channel setups never stall on warm-up
+//
+// Discarded conversions never reach noise, history, clip or last-reading state.
+//
+package ads1115tds
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxDiscardFirstN bounds DiscardFirstN; each discard costs a full conversion.
+const maxDiscardFirstN = 16
+
+// ErrWarmingUp is returned while the first DiscardFirstN conversions after
+// construction are being discarded.
+var ErrWarmingUp = errors.New("ads1115: warming up")
+
+// takeWarmupLocked consumes one start-up discard. Caller holds c.chip.mu.
+func (c *tdsChannel) takeWarmupLocked() error {
+	if c.warmupLeft <= 0 {
+		return nil
+	}
+	c.warmupLeft--
+	if c.debug {
+		c.dbg("warm-up: discarded conversion (%d left)", c.warmupLeft)
+	}
+	return fmt.Errorf("%w: discarding first %d conversions (%d left)", ErrWarmingUp, c.discardFirstN, c.warmupLeft)
+}
+
+// muxChangedLocked reports whether the chip last converted another input, and
+// records c.mux as the current one. Caller holds c.chip.mu.
+func (c *tdsChannel) muxChangedLocked() bool {
+	changed := c.chip.lastMuxValid && c.chip.lastMux != c.mux
+	c.chip.lastMux, c.chip.lastMuxValid = c.mux, true
+	return changed
+}
+
+// discardAfterMuxLocked drops discardFirstN conversions after a mux change.
+// Caller holds c.chip.mu.
+func (c *tdsChannel) discardAfterMuxLocked(config uint16, t *trace) error {
+	for i := 0; i < c.discardFirstN; i++ {
+		if c.continuous {
+			// Continuous mode produces a new result every conversion period.
+			time.Sleep(convPeriod + convSleepMargin)
+			if _, err := c.readConversionLocked(nil); err != nil {
+				return err
+			}
+		} else if _, err := c.convertSingleLocked(config, nil); err != nil {
+			return err
+		}
+	}
+	if t != nil {
+		t.addf("ADS: mux changed; discarded %d conversions (DiscardFirstN)", c.discardFirstN)
+	}
+	return nil
+}
+
+// warmupRemaining returns the start-up discards still pending.
+func (c *tdsChannel) warmupRemaining() int {
+	c.chip.mu.Lock()
+	defer c.chip.mu.Unlock()
+	return c.warmupLeft
+}
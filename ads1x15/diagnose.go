@@ -0,0 +1,30 @@
+// diagnose.go
+//
+// Health report (see package diag).
+//
+// The liveness check reads the config register, the same probe NewDriver
+// uses. A read starts no conversion and changes nothing on the ADC.
+//
+package ads1x15
+
+import (
+	"github.com/reef-pi/drivers/diag"
+)
+
+var _ diag.Diagnoser = (*driver)(nil)
+
+// Diagnose reads the config register once.
+func (d *driver) Diagnose() diag.Diagnostics {
+	r := diag.New()
+
+	var config [2]byte
+	err := d.bus.ReadFromReg(d.address, 0x01, config[:])
+	if r.Check("read config register", err) {
+		v := uint16(config[0])<<8 | uint16(config[1])
+		r.Infof("config=0x%04X", v)
+		if v&configOsNotBusy == 0 {
+			r.Infof("a conversion is in progress")
+		}
+	}
+	return r
+}
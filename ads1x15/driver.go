@@ -4,11 +4,14 @@ import (
 	"fmt"
 
 	"github.com/reef-pi/hal"
+	"github.com/reef-pi/rpi/i2c"
 )
 
 type driver struct {
 	channels []hal.AnalogInputPin
 	meta     hal.Metadata
+	bus      i2c.Bus
+	address  byte
 }
 
 func (d *driver) Metadata() hal.Metadata {
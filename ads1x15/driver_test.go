@@ -215,3 +215,25 @@ func TestAds1115Driver(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestDiagnose(t *testing.T) {
+	bus := mocki2cBus()
+	d, err := Ads1115Factory().NewDriver(params, bus)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bus.Bytes = []byte{0x85, 0x83}
+	r := d.(*driver).Diagnose()
+	if !r.OK || len(r.Messages) != 2 || r.Messages[1] != "config=0x8583" {
+		t.Errorf("idle ADC: %+v", r)
+	}
+
+	cfg, err := Ads1115Factory().NewDriver(params, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := cfg.(*driver).Diagnose(); r.OK || r.LastError == "" {
+		t.Errorf("config-only driver should not be OK: %+v", r)
+	}
+}
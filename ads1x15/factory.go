@@ -6,8 +6,9 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/reef-pi/drivers/i2creg"
+	"github.com/reef-pi/drivers/nobus"
 	"github.com/reef-pi/hal"
-	"github.com/reef-pi/rpi/i2c"
 )
 
 const addressParam = "Address"
@@ -61,18 +62,11 @@ func (f *ads1X15Factory) ValidateParameters(parameters map[string]interface{}) (
 
 	var failures = make(map[string][]string)
 	var v interface{}
-	var val interface{}
 	var ok bool
 
 	if v, ok = parameters[addressParam]; ok {
-		val, ok = hal.ConvertToInt(v)
-		if !ok {
-			failure := fmt.Sprint(addressParam, " is not a number. ", v, " was received.")
-			failures[addressParam] = append(failures[addressParam], failure)
-		}
-		if val.(int) <= 0 || val.(int) >= 256 {
-			failure := fmt.Sprint(addressParam, " is out of range (1 - 255). ", v, " was received.")
-			failures[addressParam] = append(failures[addressParam], failure)
+		if _, err := i2creg.ParseI2CAddress(v); err != nil {
+			failures[addressParam] = append(failures[addressParam], err.Error())
 		}
 	} else {
 		failure := fmt.Sprint(addressParam, " is required parameter, but was not received.")
@@ -122,18 +116,24 @@ func (f *ads1X15Factory) newDriver(parameters map[string]interface{}, hardwareRe
 		return nil, errors.New(hal.ToErrorString(failures))
 	}
 
-	intAddress, _ := hal.ConvertToInt(parameters[addressParam])
-	address := byte(intAddress)
-	bus := hardwareResources.(i2c.Bus)
-
-	var configRegister [2]byte
-	if err := bus.ReadFromReg(address, 0x01, configRegister[:]); err != nil {
+	address, _ := i2creg.ParseI2CAddress(parameters[addressParam])
+	bus, err := nobus.I2C("ads1x15", hardwareResources)
+	if err != nil {
 		return nil, err
 	}
 
+	if !nobus.Is(bus) {
+		var configRegister [2]byte
+		if err := bus.ReadFromReg(address, 0x01, configRegister[:]); err != nil {
+			return nil, err
+		}
+	}
+
 	var driver = driver{
 		meta:     f.meta,
 		channels: []hal.AnalogInputPin{},
+		bus:      bus,
+		address:  address,
 	}
 
 	// Create the 4 channels the hardware has
@@ -0,0 +1,118 @@
+// calfit.go
+//
+// Multi-point ORP calibration: ORP = Scale*observed_mv + Offset.
+//
+// One point (or several readings of the same solution) only moves Offset and
+// keeps Scale. Two or more reference solutions at least calMinSpreadMV apart
+// fit Scale and Offset by least squares; with three or more the residuals show
+// whether the solutions agree with each other. The points, their residuals and
+// the fit quality are kept and reported in Snapshot meta, and a point that
+// misses the fit by more than calMaxResidualMV is flagged. The least-squares
+// math is shared with ads1115tds (internal/linfit).
+//
+package aliexpress_orp
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/reef-pi/drivers/internal/linfit"
+)
+
+const (
+	// maxCalPoints bounds one Calibrate call.
+	maxCalPoints = 8
+
+	// Reference solutions closer than this (expected or observed mV) cannot
+	// define a slope; the call falls back to an offset-only fit.
+	calMinSpreadMV = 50.0
+
+	// Points missing the fit by more than this are flagged in Snapshot.
+	calMaxResidualMV = 10.0
+
+	// Accepted Scale (fitted or configured). An ORP electrode's span does not
+	// drift this far; beyond it a solution was mislabeled or not settled.
+	minScale = 0.8
+	maxScale = 1.25
+)
+
+// Calibration fit modes reported in Snapshot meta (cal_mode).
+const (
+	calModeOffset = "offset"
+	calModeLinear = "linear"
+)
+
+// calPoint is one calibration measurement and how far the fit misses it.
+type calPoint struct {
+	ExpectedMV float64 `json:"expected_mv"`
+	ObservedMV float64 `json:"observed_mv"`
+	ResidualMV float64 `json:"residual_mv"` // expected - fitted
+}
+
+// calFit describes the last calibration (see fitCalibration).
+type calFit struct {
+	Mode          string  `json:"mode"`
+	R2            float64 `json:"r2"`
+	MaxResidualMV float64 `json:"max_residual_mv"`
+}
+
+// checkScale returns an error when scale is outside minScale..maxScale.
+func checkScale(scale float64) error {
+	if math.IsNaN(scale) || scale < minScale || scale > maxScale {
+		return fmt.Errorf("Scale %.4f is outside %.2f..%.2f; check that each reference solution is labeled right and the probe was settled", scale, minScale, maxScale)
+	}
+	return nil
+}
+
+// fitCalibration fits points. With enough spread it fits scale and offset by
+// least squares; otherwise it keeps scale and sets offset to the mean
+// correction. Residuals are filled in on points.
+func fitCalibration(points []calPoint, scale float64) (newScale, offset float64, fit calFit) {
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		xs[i], ys[i] = p.ObservedMV, p.ExpectedMV
+	}
+
+	fit.Mode = calModeOffset
+	newScale, offset = scale, mean(ys)-scale*mean(xs)
+	if len(points) >= 2 && spread(xs) >= calMinSpreadMV && spread(ys) >= calMinSpreadMV {
+		if k, off, err := linfit.Fit(xs, ys); err == nil {
+			fit.Mode = calModeLinear
+			newScale, offset = k, off
+		}
+	}
+
+	residuals, q := linfit.Residuals(xs, ys, newScale, offset)
+	for i := range points {
+		points[i].ResidualMV = residuals[i]
+	}
+	fit.R2, fit.MaxResidualMV = q.R2, q.MaxResidual
+	return newScale, offset, fit
+}
+
+func mean(v []float64) float64 {
+	var s float64
+	for _, x := range v {
+		s += x
+	}
+	return s / float64(len(v))
+}
+
+// spread returns max(v) - min(v).
+func spread(v []float64) float64 {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, x := range v {
+		lo, hi = math.Min(lo, x), math.Max(hi, x)
+	}
+	return hi - lo
+}
+
+// calFitWarning flags a multi-point calibration whose solutions disagree.
+func calFitWarning(points []calPoint, fit calFit) string {
+	if len(points) < 2 || fit.MaxResidualMV <= calMaxResidualMV {
+		return ""
+	}
+	return fmt.Sprintf("calibration (%s fit over %d points) misses a point by %.1f mV (>%.0f mV); the reference solutions disagree or one reading was not settled. Check cal_points.",
+		fit.Mode, len(points), fit.MaxResidualMV, calMaxResidualMV)
+}
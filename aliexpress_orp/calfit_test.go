@@ -0,0 +1,70 @@
+package aliexpress_orp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFitCalibrationOffsetOnly(t *testing.T) {
+	// One solution: Scale is kept, Offset moves.
+	points := []calPoint{{ExpectedMV: 225, ObservedMV: 215}}
+	scale, offset, fit := fitCalibration(points, 1.0)
+	if fit.Mode != calModeOffset || scale != 1.0 || math.Abs(offset-10) > 1e-9 {
+		t.Fatalf("got scale=%v offset=%v mode=%s; want 1, 10, %s", scale, offset, fit.Mode, calModeOffset)
+	}
+	if math.Abs(points[0].ResidualMV) > 1e-9 {
+		t.Errorf("residual %v, want 0", points[0].ResidualMV)
+	}
+
+	// Two solutions closer than calMinSpreadMV do not define a slope.
+	points = []calPoint{{ExpectedMV: 225, ObservedMV: 215}, {ExpectedMV: 245, ObservedMV: 235}}
+	if scale, _, fit := fitCalibration(points, 1.1); fit.Mode != calModeOffset || scale != 1.1 {
+		t.Errorf("narrow spread: scale=%v mode=%s; want 1.1, %s", scale, fit.Mode, calModeOffset)
+	}
+}
+
+func TestFitCalibrationLinear(t *testing.T) {
+	// ORP = 1.05*observed - 5
+	points := []calPoint{
+		{ExpectedMV: 1.05*200 - 5, ObservedMV: 200},
+		{ExpectedMV: 1.05*450 - 5, ObservedMV: 450},
+	}
+	scale, offset, fit := fitCalibration(points, 1.0)
+	if fit.Mode != calModeLinear || math.Abs(scale-1.05) > 1e-9 || math.Abs(offset+5) > 1e-9 {
+		t.Fatalf("got scale=%v offset=%v mode=%s; want 1.05, -5, %s", scale, offset, fit.Mode, calModeLinear)
+	}
+	if math.Abs(fit.R2-1) > 1e-12 || fit.MaxResidualMV > 1e-9 {
+		t.Errorf("exact fit: %+v", fit)
+	}
+	if w := calFitWarning(points, fit); w != "" {
+		t.Errorf("exact fit must not warn: %q", w)
+	}
+}
+
+func TestFitCalibrationFlagsDisagreement(t *testing.T) {
+	points := []calPoint{
+		{ExpectedMV: 200, ObservedMV: 200},
+		{ExpectedMV: 300, ObservedMV: 330},
+		{ExpectedMV: 450, ObservedMV: 450},
+	}
+	_, _, fit := fitCalibration(points, 1.0)
+	if fit.MaxResidualMV <= calMaxResidualMV {
+		t.Fatalf("max residual %v, want > %v", fit.MaxResidualMV, calMaxResidualMV)
+	}
+	if calFitWarning(points, fit) == "" {
+		t.Error("expected a calibration fit warning")
+	}
+}
+
+func TestCheckScale(t *testing.T) {
+	for _, s := range []float64{minScale, 1.0, maxScale} {
+		if err := checkScale(s); err != nil {
+			t.Errorf("checkScale(%v) = %v, want nil", s, err)
+		}
+	}
+	for _, s := range []float64{minScale - 0.01, maxScale + 0.01, 0, -1, math.NaN()} {
+		if err := checkScale(s); err == nil {
+			t.Errorf("checkScale(%v) = nil, want an error", s)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// diagnose.go
+//
+// Health report (see package diag).
+//
+// The liveness check is one module read (readObservedMV: a cache hit means
+// the module answered within CacheMaxAgeMs). The calibrated ORP is checked
+// against the plausible range and the last calibration fit is reviewed;
+// read-rate counters come from rate.go.
+//
+package aliexpress_orp
+
+import (
+	"github.com/reef-pi/drivers/diag"
+)
+
+var _ diag.Diagnoser = (*AliExpressORP)(nil)
+
+// Diagnose reads the module once and checks the calibration.
+func (d *AliExpressORP) Diagnose() diag.Diagnostics {
+	r := diag.New()
+
+	mv, _, code, err := d.readObservedMV()
+	if r.Check("read module", err) {
+		scale, offset := d.calibration()
+		out := scale*mv + offset
+		r.Infof("observed_mv=%.2f adc=0x%08X orp=%.1f mV", mv, uint32(code), out)
+		if out < plausibleMinMV || out > plausibleMaxMV {
+			r.Warnf("ORP %.1f mV is outside the plausible %.0f..%.0f mV; check Offset/Scale and the probe",
+				out, plausibleMinMV, plausibleMaxMV)
+		}
+	}
+
+	d.mu.Lock()
+	calPoints, fit := d.calPoints, d.calFit
+	reads, hits := d.rate.reads, 0
+	for _, h := range d.rate.hits {
+		if h {
+			hits++
+		}
+	}
+	d.mu.Unlock()
+	if w := calFitWarning(calPoints, fit); w != "" {
+		r.Warnf("%s", w)
+	}
+
+	_, settled, samples := d.stability()
+	if samples >= stabilityMinSamples && !settled {
+		r.Infof("probe not settled yet (std dev above SettleThresholdMv over %d readings)", samples)
+	}
+
+	r.Counters["reads"] = uint64(reads)
+	r.Counters["recent_cache_hits"] = uint64(hits)
+	return r
+}
@@ -4,26 +4,48 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/reef-pi/drivers/filter"
+	"github.com/reef-pi/drivers/i2creg"
+	"github.com/reef-pi/drivers/internal/adc24"
 	"github.com/reef-pi/hal"
 	"github.com/reef-pi/rpi/i2c"
 )
 
 const (
+	driverType = "aliexpress-orp" // stable identifier, see DriverType
 	driverName = "AliExpress I2C ORP (ADC→mV)"
 
-	adcOffsetBinaryMid = 0x20000000
-	adcScale           = 536870912.0 // 2^29
-
-	// Timing tuning (cheap modules often need breathing room)
-	minI2CGap       = 35 * time.Millisecond  // minimum spacing between I2C transactions
-	settleAfterRead = 2 * time.Millisecond   // small settle delay after successful read
-	cacheMaxAge     = 250 * time.Millisecond // serve Snapshot from cache to avoid double-hit
-
-	retryDelay = 20 * time.Millisecond // wait before retry on transient error
+	// Timing tuning (cheap modules often need breathing room).
+	// Defaults; MinI2CGapMs / CacheMaxAgeMs / SettleAfterReadMs / RetryDelayMs override them.
+	defaultMinI2CGap       = 35 * time.Millisecond  // minimum spacing between I2C transactions
+	defaultSettleAfterRead = 2 * time.Millisecond   // small settle delay after successful read
+	defaultCacheMaxAge     = 250 * time.Millisecond // serve Snapshot from cache to avoid double-hit
+	defaultRetryDelay      = 20 * time.Millisecond  // wait before retry on transient error
+
+	// Zobell solution ORP vs Ag/AgCl reference, linearised from the standard table
+	// (~+231 mV @15°C, ~+207 mV @25°C). Valid roughly 0..50°C.
+	zobellMV25C    = 207.0
+	zobellMVPerC   = -2.4
+	zobellMinTempC = 0.0
+	zobellMaxTempC = 50.0
+
+	calSolutionManual = "manual"
+	calSolutionZobell = "zobell"
+
+	// Stability: stddev of the last stabilityWindow fresh reads (cache hits are not
+	// counted). "settled" needs at least stabilityMinSamples and stddev below threshold.
+	stabilityWindow          = 20
+	stabilityMinSamples      = 5
+	defaultSettleThresholdMV = 2.0
+
+	// observed_mv_avg: rolling mean of the last N fresh reads (ObservedAvgWindow).
+	defaultObservedAvgWindow = 10
+	maxObservedAvgWindow     = 50
 )
 
 var (
@@ -43,28 +65,80 @@ func lockForAddr(addr byte) *sync.Mutex {
 }
 
 // AliExpressORP exposes a single analog channel:
-// 0 = ORP in mV (observed electrode mV + configured offset)
+// 0 = ORP in mV (scale * observed electrode mV + configured offset)
 type AliExpressORP struct {
 	addr byte
 	bus  i2c.Bus
 	meta hal.Metadata
 
+	// claim is this instance's i2creg address claim (nil when config-only).
+	claim *i2creg.Handle
+
 	vrefV  float64
 	offset float64 // mV offset applied after reading raw mV
+	scale  float64 // span correction applied before offset (see calfit.go)
 	debug  bool
 
+	// Points and fit quality of the last Calibrate (see calfit.go).
+	calPoints []calPoint
+	calFit    calFit
+
+	// maxOffsetMV bounds offset (MaxOffsetMv); implausible is set while the
+	// calibrated ORP is outside plausibleMinMV..plausibleMaxMV (guarded by mu).
+	maxOffsetMV float64
+	implausible bool
+
+	// decoder turns the 3 reply bytes into an ADC code (ModuleVariant).
+	decoder adc24.Decoder
+
+	// calSolution selects how Calibrate derives the expected mV:
+	// "manual" uses Measurement.Expected, "zobell" uses zobellMV(tempC).
+	calSolution string
+
+	// Injected temperature; only used to look up the Zobell value during calibration.
+	tempC         float64
+	tempUpdatedAt time.Time
+
+	// Advisory TempSensitivityMvPerC and the temperature the estimate is
+	// measured from (see tempsens.go).
+	tempSensMVPerC float64
+	tempRefC       float64
+	tempRefSet     bool
+
 	pins []*orpPin
 
 	// Optional extra protection if your i2c.Bus implementation is not thread-safe.
 	// The GLOBAL per-address lock above is the important one for same-address devices.
 	mu sync.Mutex
 
+	// Per-driver timing (see default* constants)
+	minI2CGap       time.Duration
+	settleAfterRead time.Duration
+	cacheMaxAge     time.Duration // 0 disables the cache
+	retryDelay      time.Duration
+
 	// Timing + caching to prevent "read then snapshot" hammering
 	lastXferAt   time.Time
 	lastSampleAt time.Time
 	lastMV       float64
 	lastRaw      []byte
 	lastCode     int32
+
+	// Ring buffer of recent observed mV for the settling indicator (guarded by mu).
+	recentMV          []float64
+	recentNext        int
+	settleThresholdMV float64
+
+	// Rolling mean of fresh observed mV (guarded by mu). avgSamples counts the
+	// samples in the window. useAveragedCal makes Calibrate's live read use avgMV.
+	avg            filter.Filter
+	avgWindow      int
+	avgMV          float64
+	avgSamples     int
+	useAveragedCal bool
+
+	// rate tracks real reads vs cache hits (guarded by mu, see rate.go).
+	rate rateTracker
 }
 
 type orpPin struct {
@@ -72,9 +146,44 @@ type orpPin struct {
 	ch     int
 }
 
-// Optional: silence "pin does not implement TemperatureSetter" logs.
-// ORP driver intentionally ignores temperature injection.
-func (p *orpPin) SetTemperatureC(tempC float64) {}
+// SetTemperatureC stores injected temperature. Readings are never compensated;
+// temperature is only used to pick the Zobell value when calibrating and for
+// the advisory TempSensitivityMvPerC estimate.
+func (p *orpPin) SetTemperatureC(tempC float64) {
+	d := p.parent
+	d.mu.Lock()
+	d.tempC = tempC
+	d.tempUpdatedAt = time.Now()
+	if !d.tempRefSet {
+		d.tempRefC, d.tempRefSet = tempC, true
+	}
+	d.mu.Unlock()
+}
+
+// zobellMV returns the expected ORP (mV) of Zobell's solution at tempC.
+// Temperatures outside the table range are clamped to its ends.
+func zobellMV(tempC float64) float64 {
+	if tempC < zobellMinTempC {
+		tempC = zobellMinTempC
+	}
+	if tempC > zobellMaxTempC {
+		tempC = zobellMaxTempC
+	}
+	return zobellMV25C + zobellMVPerC*(tempC-25.0)
+}
+
+// zobellTempC returns the temperature to look up the Zobell value at: the
+// injected one, or 25°C when none was injected or it is older than
+// tempSensStaleAfter (fresh is false then).
+func (d *AliExpressORP) zobellTempC() (tempC float64, fresh bool) {
+	d.mu.Lock()
+	tempC, updatedAt := d.tempC, d.tempUpdatedAt
+	d.mu.Unlock()
+	if updatedAt.IsZero() || time.Since(updatedAt) > tempSensStaleAfter {
+		return 25.0, false
+	}
+	return tempC, true
+}
 
 // ---------------- Low-level ADC read ----------------
 
@@ -112,16 +221,17 @@ func (d *AliExpressORP) readObservedMV() (mv float64, raw []byte, adcCode int32,
 	defer d.mu.Unlock()
 
 	// 1) Cache: if a fresh sample exists, return it (prevents /read + /snapshot double-hit)
-	if !d.lastSampleAt.IsZero() && time.Since(d.lastSampleAt) < cacheMaxAge {
+	if !d.lastSampleAt.IsZero() && time.Since(d.lastSampleAt) < d.cacheMaxAge {
 		if d.debug {
 			log.Printf("aliexpress_orp addr=0x%02X cache hit age=%v mv=%.2f",
 				d.addr, time.Since(d.lastSampleAt), d.lastMV)
 		}
+		d.recordReadLocked(true)
 		return d.lastMV, append([]byte(nil), d.lastRaw...), d.lastCode, nil
 	}
 
 	// 2) Rate-limit actual I2C transactions to this device
-	d.enforceMinGap(minI2CGap)
+	d.enforceMinGap(d.minI2CGap)
 
 	// 3) Attempt read with one retry on transient error
 	var lastErr error
@@ -135,7 +245,7 @@ func (d *AliExpressORP) readObservedMV() (mv float64, raw []byte, adcCode int32,
 				log.Printf("aliexpress_orp addr=0x%02X read attempt=%d error=%v", d.addr, attempt, e)
 			}
 			if attempt == 1 && isTransientI2C(e) {
-				time.Sleep(retryDelay)
+				time.Sleep(d.retryDelay)
 				continue
 			}
 			return 0, nil, 0, e
@@ -166,8 +276,8 @@ func (d *AliExpressORP) readObservedMV() (mv float64, raw []byte, adcCode int32,
 			return 0, payload, 0, lastErr
 		}
 
-		code := adcI2C24ToCode(payload)
-		v := adcCodeToVolts(code, d.vrefV)
+		code := d.decoder.Code(payload)
+		v := d.decoder.Volts(code, d.vrefV)
 		mv := v * 1000.0
 
 		// 4) Cache last good sample (Snapshot can reuse it)
@@ -175,9 +285,11 @@ func (d *AliExpressORP) readObservedMV() (mv float64, raw []byte, adcCode int32,
 		d.lastMV = mv
 		d.lastRaw = append([]byte(nil), payload...)
 		d.lastCode = code
+		d.recordStabilityLocked(mv)
+		d.recordReadLocked(false)
 
 		// 5) Small settle delay (helps cheap boards)
-		time.Sleep(settleAfterRead)
+		time.Sleep(d.settleAfterRead)
 
 		return mv, payload, code, nil
 	}
@@ -185,16 +297,57 @@ func (d *AliExpressORP) readObservedMV() (mv float64, raw []byte, adcCode int32,
 	return 0, nil, 0, lastErr
 }
 
-func adcI2C24ToCode(b []byte) int32 {
-	u32 := uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8
-	u32 >>= 2
-	u32 &= 0x3FFFFFFF
-	return int32(u32)
+// recordStabilityLocked adds a fresh sample to the stability ring and the
+// rolling average. Caller holds d.mu.
+func (d *AliExpressORP) recordStabilityLocked(mv float64) {
+	d.avgMV = d.avg.Add(mv)
+	if d.avgSamples < d.avgWindow {
+		d.avgSamples++
+	}
+
+	if len(d.recentMV) < stabilityWindow {
+		d.recentMV = append(d.recentMV, mv)
+		return
+	}
+	d.recentMV[d.recentNext] = mv
+	d.recentNext = (d.recentNext + 1) % stabilityWindow
 }
 
-func adcCodeToVolts(code int32, vref float64) float64 {
-	signed := float64(int64(code) - int64(adcOffsetBinaryMid))
-	return (signed / adcScale) * vref
+// averagedMV returns the rolling mean of recent fresh reads and how many it covers.
+func (d *AliExpressORP) averagedMV() (mv float64, n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.avgMV, d.avgSamples
+}
+
+// stability returns the standard deviation of recent observed mV and whether
+// the probe counts as settled.
+func (d *AliExpressORP) stability() (stddev float64, settled bool, n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	n = len(d.recentMV)
+	if n < 2 {
+		return 0, false, n
+	}
+	mean := 0.0
+	for _, v := range d.recentMV {
+		mean += v
+	}
+	mean /= float64(n)
+	ss := 0.0
+	for _, v := range d.recentMV {
+		ss += (v - mean) * (v - mean)
+	}
+	stddev = math.Sqrt(ss / float64(n-1))
+	return stddev, n >= stabilityMinSamples && stddev < d.settleThresholdMV, n
+}
+
+// calibration returns the scale and offset set by the factory or Calibrate.
+func (d *AliExpressORP) calibration() (scale, offset float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.scale, d.offset
 }
 
 // ---------------- orpPin: hal.AnalogInputPin ----------------
@@ -208,36 +361,91 @@ func (p *orpPin) Value() (float64, error) {
 		return 0, err
 	}
 
-	out := mv + p.parent.offset
+	scale, offset := p.parent.calibration()
+	out := scale*mv + offset
 
 	if p.parent.debug {
-		log.Printf("aliexpress_orp addr=0x%02X raw=% X adc=0x%08X observed_mv=%.2f offset=%.2f out=%.2f",
-			p.parent.addr, raw, uint32(code), mv, p.parent.offset, out)
+		log.Printf("aliexpress_orp addr=0x%02X raw=% X adc=0x%08X observed_mv=%.2f scale=%.4f offset=%.2f out=%.2f",
+			p.parent.addr, raw, uint32(code), mv, scale, offset, out)
 	}
+	p.parent.plausibleORP(out)
 	return out, nil
 }
 
 func (p *orpPin) Measure() (float64, error) { return p.Value() }
 
-// Calibrate uses a simple offset model:
-// offset = Expected - Observed
-// Expected = known ORP solution (mV), Observed = observed_mv from snapshot.
-// If Observed is 0, read live.
+// Calibrate fits ORP = Scale*Observed + Offset to all measurements at once
+// (see calfit.go). Expected = known ORP solution (mV), Observed = observed_mv
+// from snapshot. One solution only sets Offset; two or more solutions far
+// enough apart also fit Scale by least squares.
+// If Observed is 0, read live; with UseAveragedCal the rolling mean
+// (observed_mv_avg) including that read is used instead of the single sample.
+//
+// With CalSolution "zobell", Expected is ignored and replaced by the Zobell
+// value at the injected temperature (25°C if none was injected, or the last one
+// is older than tempSensStaleAfter).
 func (p *orpPin) Calibrate(ms []hal.Measurement) error {
+	if len(ms) > maxCalPoints {
+		return fmt.Errorf("aliexpress_orp calibration: %d points, at most %d", len(ms), maxCalPoints)
+	}
+	points := make([]calPoint, 0, len(ms))
 	for _, m := range ms {
 		exp := m.Expected
 		obs := m.Observed
 
+		if p.parent.calSolution == calSolutionZobell {
+			tempC, fresh := p.parent.zobellTempC()
+			if !fresh {
+				log.Printf("aliexpress_orp zobell calibration: no temperature injected, assuming 25.0C")
+			}
+			exp = zobellMV(tempC)
+			log.Printf("aliexpress_orp zobell calibration: temp=%.2fC expected=%.1f mV (ignoring Expected=%.2f)",
+				tempC, exp, m.Expected)
+		}
+
 		if obs == 0 {
 			mv, _, _, err := p.parent.readObservedMV()
 			if err != nil {
 				return err
 			}
 			obs = mv
+			if p.parent.useAveragedCal {
+				avg, n := p.parent.averagedMV()
+				log.Printf("aliexpress_orp calibration: using averaged observed=%.2f mV over %d reads (last read %.2f mV)",
+					avg, n, mv)
+				obs = avg
+			}
 		}
 
-		p.parent.offset = exp - obs
-		log.Printf("aliexpress_orp calibrated offset=%.2f (expected=%.2f observed=%.2f)", p.parent.offset, exp, obs)
+		points = append(points, calPoint{ExpectedMV: exp, ObservedMV: obs})
+	}
+	if len(points) == 0 {
+		return nil
+	}
+
+	prevScale, _ := p.parent.calibration()
+	scale, offset, fit := fitCalibration(points, prevScale)
+	if err := checkScale(scale); err != nil {
+		return fmt.Errorf("aliexpress_orp calibration (%s fit over %d points): %w", fit.Mode, len(points), err)
+	}
+	if err := checkOffset(offset, p.parent.maxOffsetMV); err != nil {
+		return fmt.Errorf("aliexpress_orp calibration (%s fit over %d points): %w", fit.Mode, len(points), err)
+	}
+	p.parent.mu.Lock()
+	p.parent.scale, p.parent.offset = scale, offset
+	p.parent.calPoints, p.parent.calFit = points, fit
+	if !p.parent.tempUpdatedAt.IsZero() {
+		p.parent.tempRefC, p.parent.tempRefSet = p.parent.tempC, true
+	}
+	p.parent.mu.Unlock()
+
+	log.Printf("aliexpress_orp calibrated scale=%.4f offset=%.2f (%s fit over %d points, r2=%.4f max_residual=%.2f mV)",
+		scale, offset, fit.Mode, len(points), fit.R2, fit.MaxResidualMV)
+	for _, pt := range points {
+		log.Printf("aliexpress_orp   point expected=%.2f observed=%.2f residual=%.2f", pt.ExpectedMV, pt.ObservedMV, pt.ResidualMV)
+	}
+	if w := calFitWarning(points, fit); w != "" {
+		log.Printf("aliexpress_orp WARNING: %s", w)
 	}
 	return nil
 }
@@ -253,7 +461,16 @@ func (p *orpPin) Snapshot() (hal.Snapshot, error) {
 	if err != nil {
 		return hal.Snapshot{}, err
 	}
-	out := mv + p.parent.offset
+	scale, offset := p.parent.calibration()
+	out := scale*mv + offset
+	plausible := p.parent.plausibleORP(out)
+	stddev, settled, samples := p.parent.stability()
+	avgMV, avgSamples := p.parent.averagedMV()
+	readInterval, hitRatio, rateReads := p.parent.readRate()
+	settledSig := 0.0
+	if settled {
+		settledSig = 1
+	}
 
 	meta := map[string]any{
 		"channel": p.ch,
@@ -262,28 +479,37 @@ func (p *orpPin) Snapshot() (hal.Snapshot, error) {
 		"calibration_observed_key": "observed_mv",
 		"raw_signal_key":           "observed_mv",
 		"primary_signal_key":       "value",
-		"secondary_signal_keys":    []string{"offset_mv", "adc_code"},
+		"secondary_signal_keys":    []string{"observed_mv_avg", "stddev_mv", "settled", "offset_mv", "adc_code"},
 
 		"display_roles": map[string]any{
 			"primary":  "Primary (ORP)",
 			"observed": "Observed (electrode mV)",
 		},
 		"display_names": map[string]any{
-			"value":       "ORP (mV, calibrated)",
-			"observed_mv": "Electrode (mV)",
-			"offset_mv":   "Offset (mV)",
-			"adc_code":    "ADC code (offset-binary)",
-			"raw_hex":     "Raw bytes (hex)",
+			"value":           "ORP (mV, calibrated)",
+			"observed_mv":     "Electrode (mV)",
+			"observed_mv_avg": "Electrode avg (mV)",
+			"offset_mv":       "Offset (mV)",
+			"adc_code":        "ADC code (offset-binary)",
+			"raw_hex":         "Raw bytes (hex)",
+			"stddev_mv":       "Stability (std dev, mV)",
+			"settled":         "Settled",
 		},
 		"display_help": map[string]any{
-			"observed_mv": "Raw physical electrode millivolts from the I2C ADC module. Calibration adjusts via Offset.",
-			"offset_mv":   "Software offset applied: ORP = observed_mv + offset.",
+			"observed_mv":     "Raw physical electrode millivolts from the I2C ADC module. Calibration adjusts via Offset.",
+			"observed_mv_avg": "Rolling mean of the last ObservedAvgWindow electrode readings. Used by calibration when UseAveragedCal is on.",
+			"offset_mv":       "Software offset applied: ORP = scale*observed_mv + offset.",
+			"stddev_mv":       "Standard deviation of recent electrode mV readings. Falls as the probe settles.",
+			"settled":         "1 when the std dev is below SettleThresholdMv over enough readings; wait for this before acting on ORP.",
 		},
 		"signal_decimals": map[string]any{
-			"value":       1,
-			"observed_mv": 2,
-			"offset_mv":   2,
-			"adc_code":    0,
+			"value":           1,
+			"observed_mv":     2,
+			"observed_mv_avg": 2,
+			"offset_mv":       2,
+			"adc_code":        0,
+			"stddev_mv":       2,
+			"settled":         0,
 		},
 
 		// Temperature handling (explicit!)
@@ -292,32 +518,100 @@ func (p *orpPin) Snapshot() (hal.Snapshot, error) {
 			"reason":  "ORP is reported in mV; temperature compensation is not applied by this driver.",
 			"ref_c":   25.0,
 		},
+
+		"cal_solution": p.parent.calSolution,
+
+		"max_offset_mv": p.parent.maxOffsetMV,
+		"plausible":     plausible,
+
+		"scale": scale,
+
+		"module_variant": p.parent.decoder.Variant,
+		"decode_shift":   p.parent.decoder.Shift,
+		"decode_mask":    p.parent.decoder.MaskString(),
+
+		"stddev_mv":           stddev,
+		"settled":             settled,
+		"stability_samples":   samples,
+		"settle_threshold_mv": p.parent.settleThresholdMV,
+
+		"observed_avg_window":  p.parent.avgWindow,
+		"observed_avg_samples": avgSamples,
+		"use_averaged_cal":     p.parent.useAveragedCal,
+
+		// Effective read rate over the last rateWindow reads (see rate.go)
+		"read_interval_sec": readInterval.Seconds(),
+		"cache_hit_ratio":   hitRatio,
+		"rate_reads":        rateReads,
+	}
+
+	notes := []string{
+		"Driver reports raw electrode mV from hardware; calibration is a software scale and offset.",
+		"Driver includes min-gap + cache + retry to avoid I2C timing failures during calibration UI.",
+		"If you run pH + ORP drivers at the same I2C address, a global per-address lock prevents read collisions.",
+	}
+	if !plausible {
+		notes = append(notes, fmt.Sprintf(
+			"WARNING: ORP %.1f mV is outside the plausible %.0f..%.0f mV; check Offset (%.2f mV) and the probe.",
+			out, plausibleMinMV, plausibleMaxMV, offset))
+	}
+	if rateReads >= rateWindow && hitRatio > highCacheHitRatio {
+		notes = append(notes, fmt.Sprintf(
+			"%.0f%% of recent reads were served from the cache (CacheMaxAgeMs=%d); polling faster than that does not give fresher values.",
+			hitRatio*100, p.parent.cacheMaxAge.Milliseconds()))
+	}
+
+	notes = p.parent.tempSensitivityMeta(meta, notes)
+
+	p.parent.mu.Lock()
+	calPoints, fit := p.parent.calPoints, p.parent.calFit
+	p.parent.mu.Unlock()
+	if len(calPoints) > 0 {
+		meta["cal_mode"] = fit.Mode
+		meta["cal_points"] = calPoints
+		meta["cal_r2"] = fit.R2
+		meta["cal_max_residual_mv"] = fit.MaxResidualMV
+		if w := calFitWarning(calPoints, fit); w != "" {
+			notes = append(notes, "WARNING: "+w)
+		}
+	}
+
+	if p.parent.calSolution == calSolutionZobell {
+		tempC, _ := p.parent.zobellTempC()
+		meta["zobell_expected_mv"] = zobellMV(tempC)
+		meta["zobell_temp_c"] = tempC
 	}
 
 	return hal.Snapshot{
 		Value: out,
 		Unit:  "mV",
 		Signals: map[string]hal.Signal{
-			"observed_mv": {Now: mv, Unit: "mV"},
-			"offset_mv":   {Now: p.parent.offset, Unit: "mV"},
-			"adc_code":    {Now: float64(code), Unit: ""},
-			"raw_hex":     {Now: 0, Unit: fmt.Sprintf("% X", raw)},
-		},
-		Meta: meta,
-		Notes: []string{
-			"Driver reports raw electrode mV from hardware; calibration is software offset only.",
-			"Driver includes min-gap + cache + retry to avoid I2C timing failures during calibration UI.",
-			"If you run pH + ORP drivers at the same I2C address, a global per-address lock prevents read collisions.",
+			"observed_mv":     {Now: mv, Unit: "mV"},
+			"observed_mv_avg": {Now: avgMV, Unit: "mV"},
+			"offset_mv":       {Now: offset, Unit: "mV"},
+			"adc_code":        {Now: float64(code), Unit: ""},
+			"raw_hex":         {Now: 0, Unit: fmt.Sprintf("% X", raw)},
+			"stddev_mv":       {Now: stddev, Unit: "mV"},
+			"settled":         {Now: settledSig, Unit: ""},
 		},
+		Meta:  meta,
+		Notes: notes,
 	}, nil
 }
 
 // ---------------- hal.Driver plumbing ----------------
 
 func (d *AliExpressORP) Name() string           { return driverName }
-func (d *AliExpressORP) Close() error           { return nil }
 func (d *AliExpressORP) Metadata() hal.Metadata { return d.meta }
 
+func (d *AliExpressORP) Close() error {
+	d.claim.Release()
+	return nil
+}
+
+// DriverType returns the stable driver kind for host-side routing.
+func (d *AliExpressORP) DriverType() string { return driverType }
+
 func (d *AliExpressORP) AnalogInputPin(n int) (hal.AnalogInputPin, error) {
 	if n != 0 {
 		return nil, fmt.Errorf("%s supports only channel 0 (mV). Asked:%d", driverName, n)
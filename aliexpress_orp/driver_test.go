@@ -0,0 +1,184 @@
+package aliexpress_orp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/reef-pi/drivers/filter"
+	"github.com/reef-pi/drivers/internal/adc24"
+	"github.com/reef-pi/hal"
+)
+
+// mvBus is an i2c.Bus that replies with the default-variant payload of each
+// queued mV in turn, repeating the last one.
+type mvBus struct {
+	mvs   []float64
+	reads int
+}
+
+func (b *mvBus) SetAddress(_ byte) error { return nil }
+func (b *mvBus) ReadBytes(_ byte, _ int) ([]byte, error) {
+	mv := b.mvs[len(b.mvs)-1]
+	if b.reads < len(b.mvs) {
+		mv = b.mvs[b.reads]
+	}
+	b.reads++
+	return payloadForMV(mv), nil
+}
+func (b *mvBus) WriteBytes(_ byte, _ []byte) error     { return nil }
+func (b *mvBus) ReadFromReg(_, _ byte, _ []byte) error { return nil }
+func (b *mvBus) WriteToReg(_, _ byte, _ []byte) error  { return nil }
+func (b *mvBus) Close() error                          { return nil }
+
+// payloadForMV encodes mv at Vref 2.5 V for adc24.Default.
+func payloadForMV(mv float64) []byte {
+	mid := float64(adc24.Default.Mid())
+	code := uint32(math.Round(mid + mv/2500*mid))
+	u := code << 2
+	return []byte{byte(u >> 24), byte(u >> 16), byte(u >> 8)}
+}
+
+// newTestORP returns a driver with no cache or I2C delays reading mvs.
+func newTestORP(mvs ...float64) (*AliExpressORP, *mvBus) {
+	bus := &mvBus{mvs: mvs}
+	d := &AliExpressORP{
+		addr:              0x24,
+		bus:               bus,
+		vrefV:             2.5,
+		scale:             1,
+		decoder:           adc24.Default,
+		calSolution:       calSolutionManual,
+		tempC:             25,
+		maxOffsetMV:       defaultMaxOffsetMV,
+		settleThresholdMV: defaultSettleThresholdMV,
+		avg:               filter.Mean(defaultObservedAvgWindow),
+		avgWindow:         defaultObservedAvgWindow,
+	}
+	d.pins = []*orpPin{{parent: d, ch: 0}}
+	return d, bus
+}
+
+func TestZobellMV(t *testing.T) {
+	cases := []struct{ tempC, want float64 }{
+		{25, zobellMV25C},
+		{15, 231},
+		{35, 183},
+		{-5, 267}, // clamped to 0°C
+		{60, 147}, // clamped to 50°C
+	}
+	for _, c := range cases {
+		if got := zobellMV(c.tempC); math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("zobellMV(%v) = %v, want %v", c.tempC, got, c.want)
+		}
+	}
+}
+
+func TestZobellCalibration(t *testing.T) {
+	d, _ := newTestORP(200)
+	d.calSolution = calSolutionZobell
+	p := d.pins[0]
+	ms := []hal.Measurement{{Expected: 999}} // Expected is ignored, Observed read live
+
+	// No temperature injected: 25°C.
+	if err := p.Calibrate(ms); err != nil {
+		t.Fatal(err)
+	}
+	if _, offset := d.calibration(); math.Abs(offset-(zobellMV25C-200)) > 0.01 {
+		t.Errorf("no temperature: offset %v, want %v", offset, zobellMV25C-200)
+	}
+
+	p.SetTemperatureC(15)
+	if err := p.Calibrate(ms); err != nil {
+		t.Fatal(err)
+	}
+	if _, offset := d.calibration(); math.Abs(offset-31) > 0.01 {
+		t.Errorf("15°C: offset %v, want 31", offset)
+	}
+
+	// A stale temperature is not trusted: back to 25°C.
+	d.mu.Lock()
+	d.tempUpdatedAt = time.Now().Add(-tempSensStaleAfter - time.Minute)
+	d.mu.Unlock()
+	if err := p.Calibrate(ms); err != nil {
+		t.Fatal(err)
+	}
+	if _, offset := d.calibration(); math.Abs(offset-(zobellMV25C-200)) > 0.01 {
+		t.Errorf("stale temperature: offset %v, want %v", offset, zobellMV25C-200)
+	}
+	if tempC, fresh := d.zobellTempC(); fresh || tempC != 25 {
+		t.Errorf("zobellTempC() = %v, %v; want 25, false", tempC, fresh)
+	}
+}
+
+func TestStability(t *testing.T) {
+	noisy := []float64{200, 210, 190, 205, 195}
+	stable := func(n int) []float64 {
+		out := make([]float64, n)
+		for i := range out {
+			out[i] = 300 + 0.5*float64(i%2)
+		}
+		return out
+	}
+	cases := []struct {
+		name        string
+		mvs         []float64
+		wantN       int
+		wantSettled bool
+	}{
+		{"one read", []float64{200}, 1, false},
+		{"below min samples", stable(stabilityMinSamples - 1), stabilityMinSamples - 1, false},
+		{"min samples, steady", stable(stabilityMinSamples), stabilityMinSamples, true},
+		{"noisy", noisy, len(noisy), false},
+		// Old noisy reads leave the ring once stabilityWindow steady ones follow.
+		{"ring wraps", append(append([]float64(nil), noisy...), stable(stabilityWindow)...), stabilityWindow, true},
+	}
+	for _, c := range cases {
+		d, _ := newTestORP(c.mvs...)
+		for range c.mvs {
+			if _, _, _, err := d.readObservedMV(); err != nil {
+				t.Fatalf("%s: %v", c.name, err)
+			}
+		}
+		stddev, settled, n := d.stability()
+		if n != c.wantN || settled != c.wantSettled {
+			t.Errorf("%s: n=%d settled=%v (stddev %.3f); want n=%d settled=%v",
+				c.name, n, settled, stddev, c.wantN, c.wantSettled)
+		}
+	}
+
+	// Cache hits are not counted as samples.
+	d, bus := newTestORP(300)
+	d.cacheMaxAge = time.Hour
+	for i := 0; i < 3; i++ {
+		if _, _, _, err := d.readObservedMV(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, _, n := d.stability(); n != 1 || bus.reads != 1 {
+		t.Errorf("cache hits: n=%d reads=%d; want 1, 1", n, bus.reads)
+	}
+}
+
+func TestCalibrateUsesAveragedObserved(t *testing.T) {
+	for _, averaged := range []bool{false, true} {
+		d, _ := newTestORP(200, 210, 220)
+		d.useAveragedCal = averaged
+		// Two earlier reads feed the rolling mean; Calibrate's live read is the third.
+		for i := 0; i < 2; i++ {
+			if _, _, _, err := d.readObservedMV(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := d.pins[0].Calibrate([]hal.Measurement{{Expected: 250}}); err != nil {
+			t.Fatal(err)
+		}
+		want := 250.0 - 220 // the single live sample
+		if averaged {
+			want = 250.0 - 210 // observed_mv_avg over 200, 210, 220
+		}
+		if _, offset := d.calibration(); math.Abs(offset-want) > 0.01 {
+			t.Errorf("UseAveragedCal=%v: offset %v, want %v", averaged, offset, want)
+		}
+	}
+}
@@ -3,13 +3,20 @@ package aliexpress_orp
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
+	"math"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/reef-pi/drivers/filter"
+	"github.com/reef-pi/drivers/i2creg"
+	"github.com/reef-pi/drivers/internal/adc24"
+	"github.com/reef-pi/drivers/internal/timing"
+	"github.com/reef-pi/drivers/nobus"
 	"github.com/reef-pi/hal"
-	"github.com/reef-pi/rpi/i2c"
 )
 
 type factory struct {
@@ -22,6 +29,37 @@ const (
 	vrefParam    = "Vref"
 	offsetParam  = "Offset"
 	debugParam   = "Debug"
+
+	// CalSolution: "manual" (Expected mV as entered) or "zobell" (temperature-corrected Zobell value)
+	calSolutionParam = "CalSolution"
+
+	// Advanced I2C timing (ms), bounded; defaults match the tuned constants in driver.go
+	minI2CGapMsParam       = timing.MinI2CGapMs
+	cacheMaxAgeMsParam     = timing.CacheMaxAgeMs
+	settleAfterReadMsParam = timing.SettleAfterReadMs
+	retryDelayMsParam      = timing.RetryDelayMs
+
+	// Stability: std dev (mV) of recent readings below which the probe counts as settled
+	settleThresholdMvParam = "SettleThresholdMv"
+
+	// Rolling mean reported as observed_mv_avg; UseAveragedCal calibrates against it
+	observedAvgWindowParam = "ObservedAvgWindow"
+	useAveragedCalParam    = "UseAveragedCal"
+
+	// Reply decoding: ModuleVariant "default" (ADC.cpp), "raw24" or "custom";
+	// DecodeShift / DecodeMask only apply to "custom" (see internal/adc24)
+	moduleVariantParam = "ModuleVariant"
+	decodeShiftParam   = "DecodeShift"
+	decodeMaskParam    = "DecodeMask"
+
+	// Offset must stay within ±MaxOffsetMv (see plausible.go)
+	maxOffsetMvParam = "MaxOffsetMv"
+
+	// Advisory ORP temperature sensitivity (mV/°C) for the snapshot note; never applied (see tempsens.go)
+	tempSensitivityParam = "TempSensitivityMvPerC"
+
+	// Electrode span correction: ORP = Scale*observed_mv + Offset (see calfit.go)
+	scaleParam = "Scale"
 )
 
 var f *factory
@@ -32,7 +70,7 @@ func Factory() hal.DriverFactory {
 		f = &factory{
 			meta: hal.Metadata{
 				Name:         driverName,
-				Description:  "AliExpress I2C ADC module: electrode mV → ORP mV via software offset.",
+				Description:  "AliExpress I2C ADC module: electrode mV → ORP mV via software scale and offset.",
 				Capabilities: []hal.Capability{hal.AnalogInput},
 			},
 			parameters: []hal.ConfigParameter{
@@ -40,6 +78,25 @@ func Factory() hal.DriverFactory {
 				{Name: vrefParam, Type: hal.Decimal, Order: 1, Default: 2.5},
 				{Name: offsetParam, Type: hal.Decimal, Order: 2, Default: 0.0},
 				{Name: debugParam, Type: hal.Boolean, Order: 3, Default: false},
+				{Name: calSolutionParam, Type: hal.String, Order: 4, Default: calSolutionManual},
+
+				{Name: minI2CGapMsParam, Type: hal.Integer, Order: 5, Default: int(defaultMinI2CGap / time.Millisecond)},
+				{Name: cacheMaxAgeMsParam, Type: hal.Integer, Order: 6, Default: int(defaultCacheMaxAge / time.Millisecond)},
+				{Name: settleAfterReadMsParam, Type: hal.Integer, Order: 7, Default: int(defaultSettleAfterRead / time.Millisecond)},
+				{Name: retryDelayMsParam, Type: hal.Integer, Order: 8, Default: int(defaultRetryDelay / time.Millisecond)},
+
+				{Name: settleThresholdMvParam, Type: hal.Decimal, Order: 9, Default: defaultSettleThresholdMV},
+
+				{Name: observedAvgWindowParam, Type: hal.Integer, Order: 10, Default: defaultObservedAvgWindow},
+				{Name: useAveragedCalParam, Type: hal.Boolean, Order: 11, Default: false},
+
+				{Name: moduleVariantParam, Type: hal.String, Order: 12, Default: adc24.VariantDefault},
+				{Name: decodeShiftParam, Type: hal.Integer, Order: 13, Default: int(adc24.Default.Shift)},
+				{Name: decodeMaskParam, Type: hal.String, Order: 14, Default: adc24.DefaultMask},
+
+				{Name: maxOffsetMvParam, Type: hal.Decimal, Order: 15, Default: defaultMaxOffsetMV},
+				{Name: tempSensitivityParam, Type: hal.Decimal, Order: 16, Default: 0.0},
+				{Name: scaleParam, Type: hal.Decimal, Order: 17, Default: 1.0},
 			},
 		}
 	})
@@ -56,11 +113,8 @@ func (f *factory) ValidateParameters(parameters map[string]interface{}) (bool, m
 	if !ok {
 		failures[addressParam] = append(failures[addressParam], "Address parameter is required")
 	} else {
-		addr, ok := toInt(addrRaw)
-		if !ok {
-			failures[addressParam] = append(failures[addressParam], "Address must be an integer (0..127)")
-		} else if addr < 0 || addr > 127 {
-			failures[addressParam] = append(failures[addressParam], "Address must be 0..127 (7-bit)")
+		if _, err := i2creg.ParseI2CAddress(addrRaw); err != nil {
+			failures[addressParam] = append(failures[addressParam], err.Error())
 		}
 	}
 
@@ -69,6 +123,44 @@ func (f *factory) ValidateParameters(parameters map[string]interface{}) (bool, m
 		failures[vrefParam] = append(failures[vrefParam], "Vref must be >0 and reasonable (e.g. 2.5)")
 	}
 
+	maxOffset := getFloatAny(parameters, defaultMaxOffsetMV, maxOffsetMvParam, "maxoffsetmv")
+	if maxOffset <= 0 || maxOffset > maxMaxOffsetMV {
+		failures[maxOffsetMvParam] = append(failures[maxOffsetMvParam],
+			fmt.Sprintf("MaxOffsetMv must be >0 and <=%.0f mV", maxMaxOffsetMV))
+	} else if err := checkOffset(getFloatAny(parameters, 0.0, offsetParam, "offset"), maxOffset); err != nil {
+		failures[offsetParam] = append(failures[offsetParam], err.Error())
+	}
+
+	if s := getFloatAny(parameters, 0.0, tempSensitivityParam, "tempsensitivitymvperc"); math.IsNaN(s) || math.Abs(s) > maxTempSensitivityMVPerC {
+		failures[tempSensitivityParam] = append(failures[tempSensitivityParam],
+			fmt.Sprintf("TempSensitivityMvPerC must be within ±%.0f mV/°C (0 = no note)", maxTempSensitivityMVPerC))
+	}
+
+	if err := checkScale(getFloatAny(parameters, 1.0, scaleParam, "scale")); err != nil {
+		failures[scaleParam] = append(failures[scaleParam], err.Error())
+	}
+
+	switch getStringAny(parameters, calSolutionManual, calSolutionParam, "calsolution") {
+	case calSolutionManual, calSolutionZobell:
+	default:
+		failures[calSolutionParam] = append(failures[calSolutionParam], "CalSolution must be \"manual\" or \"zobell\"")
+	}
+
+	timing.Validate(parameters, failures, timing.I2CBounds...)
+
+	if st := getFloatAny(parameters, defaultSettleThresholdMV, settleThresholdMvParam, "settlethresholdmv"); st <= 0 || st > 50 {
+		failures[settleThresholdMvParam] = append(failures[settleThresholdMvParam], "SettleThresholdMv must be >0 and <=50 mV")
+	}
+
+	if n := getIntAny(parameters, defaultObservedAvgWindow, observedAvgWindowParam, "observedavgwindow"); n < 1 || n > maxObservedAvgWindow {
+		failures[observedAvgWindowParam] = append(failures[observedAvgWindowParam],
+			fmt.Sprintf("ObservedAvgWindow must be 1..%d readings", maxObservedAvgWindow))
+	}
+
+	if _, err := decoderFromParams(parameters); err != nil {
+		failures[moduleVariantParam] = append(failures[moduleVariantParam], err.Error())
+	}
+
 	return len(failures) == 0, failures
 }
 
@@ -85,26 +177,73 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
 		}
 	}
 
-	addrInt := getIntAny(parameters, 36, addressParam, "address")
+	addr := byte(36)
+	if v, ok := getAny(parameters, addressParam, "address"); ok {
+		addr, _ = i2creg.ParseI2CAddress(v)
+	}
 	vref := getFloatAny(parameters, 2.5, vrefParam, "vref")
 	offset := getFloatAny(parameters, 0.0, offsetParam, "offset")
+	calSolution := getStringAny(parameters, calSolutionManual, calSolutionParam, "calsolution")
+	avgWindow := getIntAny(parameters, defaultObservedAvgWindow, observedAvgWindowParam, "observedavgwindow")
+	decoder, _ := decoderFromParams(parameters) // validated above
+
+	bus, err := nobus.I2C("aliexpress_orp", hardwareResources)
+	if err != nil {
+		return nil, err
+	}
 
 	d := &AliExpressORP{
-		addr:   byte(addrInt),
-		bus:    hardwareResources.(i2c.Bus),
+		addr:   addr,
+		bus:    bus,
 		vrefV:  vref,
 		offset: offset,
+		scale:  getFloatAny(parameters, 1.0, scaleParam, "scale"),
 		debug:  debug,
+
+		maxOffsetMV: getFloatAny(parameters, defaultMaxOffsetMV, maxOffsetMvParam, "maxoffsetmv"),
+
+		tempSensMVPerC: getFloatAny(parameters, 0.0, tempSensitivityParam, "tempsensitivitymvperc"),
+
+		decoder: decoder,
+
+		calSolution: calSolution,
+		tempC:       25.0,
+
+		minI2CGap:       timing.Ms(parameters, defaultMinI2CGap, minI2CGapMsParam, "mini2cgapms"),
+		cacheMaxAge:     timing.Ms(parameters, defaultCacheMaxAge, cacheMaxAgeMsParam, "cachemaxagems"),
+		settleAfterRead: timing.Ms(parameters, defaultSettleAfterRead, settleAfterReadMsParam, "settleafterreadms"),
+		retryDelay:      timing.Ms(parameters, defaultRetryDelay, retryDelayMsParam, "retrydelayms"),
+
+		settleThresholdMV: getFloatAny(parameters, defaultSettleThresholdMV, settleThresholdMvParam, "settlethresholdmv"),
+
+		avg:            filter.Mean(avgWindow),
+		avgWindow:      avgWindow,
+		useAveragedCal: getBoolAny(parameters, false, useAveragedCalParam, "useaveragedcal"),
+
 		meta: hal.Metadata{
 			Name:         driverName,
-			Description:  "AliExpress I2C ADC module: electrode mV → ORP mV via offset",
+			Description:  "AliExpress I2C ADC module: electrode mV → ORP mV via scale and offset",
 			Capabilities: []hal.Capability{hal.AnalogInput},
 		},
 	}
 	d.pins = []*orpPin{{parent: d, ch: 0}}
 
 	if debug {
-		log.Printf("aliexpress_orp init addr=%d (0x%02X) vref=%.3f offset=%.2f", addrInt, addrInt, vref, offset)
+		log.Printf("aliexpress_orp init addr=%d (0x%02X) vref=%.3f offset=%.2f calSolution=%s", addr, addr, vref, offset, calSolution)
+		log.Printf("aliexpress_orp timing addr=0x%02X gap=%v cache=%v settle=%v retry=%v",
+			addr, d.minI2CGap, d.cacheMaxAge, d.settleAfterRead, d.retryDelay)
+		log.Printf("aliexpress_orp decode addr=0x%02X variant=%s shift=%d mask=%s",
+			addr, decoder.Variant, decoder.Shift, decoder.MaskString())
+	}
+
+	// pH and ORP modules may share an address; the per-address lock serializes them.
+	// Config-only drivers (nil bus) hold no address.
+	if !nobus.Is(bus) {
+		claim, err := i2creg.Claim(d.bus, d.addr, driverType, "aliexpress-ph")
+		if err != nil {
+			return nil, err
+		}
+		d.claim = claim
 	}
 
 	return d, nil
@@ -112,6 +251,14 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
 
 // ---------------- helpers (same style as your robotank factory) ----------------
 
+// decoderFromParams builds the reply decoder from ModuleVariant, DecodeShift and DecodeMask.
+func decoderFromParams(parameters map[string]interface{}) (adc24.Decoder, error) {
+	return adc24.New(
+		getStringAny(parameters, adc24.VariantDefault, moduleVariantParam, "modulevariant"),
+		getIntAny(parameters, int(adc24.Default.Shift), decodeShiftParam, "decodeshift"),
+		getStringAny(parameters, adc24.DefaultMask, decodeMaskParam, "decodemask"))
+}
+
 func getAny(m map[string]interface{}, keys ...string) (interface{}, bool) {
 	for _, k := range keys {
 		if v, ok := m[k]; ok {
@@ -146,6 +293,23 @@ func getIntAny(m map[string]interface{}, def int, keys ...string) int {
 	return def
 }
 
+// getStringAny returns a trimmed, lower-cased string value (empty counts as missing).
+func getStringAny(m map[string]interface{}, def string, keys ...string) string {
+	v, ok := getAny(m, keys...)
+	if !ok {
+		return def
+	}
+	s, ok := v.(string)
+	if !ok {
+		return def
+	}
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return def
+	}
+	return s
+}
+
 func getBoolAny(m map[string]interface{}, def bool, keys ...string) bool {
 	v, ok := getAny(m, keys...)
 	if !ok {
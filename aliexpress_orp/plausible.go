@@ -0,0 +1,53 @@
+// plausible.go
+//
+// Offset bounds and a sanity check on the calibrated ORP.
+//
+// Offset shifts every reading one-for-one, so a mistyped value (20000 instead
+// of 200) corrupts all of them without any error. MaxOffsetMv bounds Offset in
+// the factory and in Calibrate, and readings outside what water can show
+// (plausibleMinMV..plausibleMaxMV) are logged and flagged in Snapshot.
+//
+package aliexpress_orp
+
+import (
+	"fmt"
+	"log"
+	"math"
+)
+
+const (
+	// Default and hard limit for MaxOffsetMv. The module's own span is a few
+	// hundred mV; offsets beyond that point at a typo or a wiring fault.
+	defaultMaxOffsetMV = 2000.0
+	maxMaxOffsetMV     = 5000.0
+
+	// ORP of natural and treated water; ozone dosing tops out near +1200 mV.
+	plausibleMinMV = -1000.0
+	plausibleMaxMV = 1500.0
+)
+
+// checkOffset returns an error when offset is outside ±max.
+func checkOffset(offset, max float64) error {
+	if math.IsNaN(offset) || math.Abs(offset) > max {
+		return fmt.Errorf("Offset %.2f mV is outside ±%.0f mV (MaxOffsetMv)", offset, max)
+	}
+	return nil
+}
+
+// plausibleORP reports whether out is a believable ORP, logging once each
+// time the reading leaves the plausible range.
+func (d *AliExpressORP) plausibleORP(out float64) bool {
+	ok := out >= plausibleMinMV && out <= plausibleMaxMV
+
+	d.mu.Lock()
+	warn := !ok && !d.implausible
+	d.implausible = !ok
+	offset := d.offset
+	d.mu.Unlock()
+
+	if warn {
+		log.Printf("aliexpress_orp addr=0x%02X WARNING: ORP %.1f mV outside %.0f..%.0f mV (offset=%.2f); check Offset and the probe",
+			d.addr, out, plausibleMinMV, plausibleMaxMV, offset)
+	}
+	return ok
+}
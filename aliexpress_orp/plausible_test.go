@@ -0,0 +1,88 @@
+package aliexpress_orp
+
+import (
+	"bytes"
+	"log"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/reef-pi/hal"
+)
+
+func TestCheckOffset(t *testing.T) {
+	for _, c := range []struct {
+		offset, max float64
+		ok          bool
+	}{
+		{0, 2000, true},
+		{-2000, 2000, true},
+		{2000.5, 2000, false},
+		{math.NaN(), 2000, false},
+	} {
+		if err := checkOffset(c.offset, c.max); (err == nil) != c.ok {
+			t.Errorf("checkOffset(%v, %v) = %v; want ok=%v", c.offset, c.max, err, c.ok)
+		}
+	}
+}
+
+func TestFactoryBoundsOffset(t *testing.T) {
+	cases := []struct {
+		params map[string]interface{}
+		bad    string
+	}{
+		{map[string]interface{}{addressParam: 36, offsetParam: 150.0}, ""},
+		{map[string]interface{}{addressParam: 36, offsetParam: 20000.0}, offsetParam},
+		{map[string]interface{}{addressParam: 36, offsetParam: 2500.0, maxOffsetMvParam: 3000.0}, ""},
+		{map[string]interface{}{addressParam: 36, maxOffsetMvParam: maxMaxOffsetMV + 1}, maxOffsetMvParam},
+	}
+	for _, c := range cases {
+		ok, failures := Factory().ValidateParameters(c.params)
+		if c.bad == "" && !ok {
+			t.Errorf("%v: unexpected failures %v", c.params, failures)
+		}
+		if c.bad != "" && len(failures[c.bad]) == 0 {
+			t.Errorf("%v: want a %s failure, got %v", c.params, c.bad, failures)
+		}
+	}
+}
+
+func TestCalibrateRejectsOffset(t *testing.T) {
+	d, _ := newTestORP(200)
+	d.offset = 5
+	d.maxOffsetMV = 100
+	err := d.pins[0].Calibrate([]hal.Measurement{{Expected: 500, Observed: 200}})
+	if err == nil || !strings.Contains(err.Error(), "MaxOffsetMv") {
+		t.Fatalf("want a MaxOffsetMv error, got %v", err)
+	}
+	if scale, offset := d.calibration(); scale != 1 || offset != 5 {
+		t.Errorf("rejected calibration changed scale=%v offset=%v", scale, offset)
+	}
+}
+
+func TestPlausibleORPLogsOnce(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	d, _ := newTestORP()
+	for _, c := range []struct {
+		out  float64
+		want bool
+		logs int
+	}{
+		{250, true, 0},
+		{plausibleMaxMV + 100, false, 1},
+		{plausibleMaxMV + 200, false, 1}, // still out of range: no new line
+		{250, true, 1},
+		{plausibleMinMV - 1, false, 2}, // left the range again
+	} {
+		if got := d.plausibleORP(c.out); got != c.want {
+			t.Errorf("plausibleORP(%v) = %v, want %v", c.out, got, c.want)
+		}
+		if n := strings.Count(buf.String(), "WARNING"); n != c.logs {
+			t.Errorf("after %v: %d warnings logged, want %d", c.out, n, c.logs)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// rate.go
+//
+// Effective read rate: how often the module is really read versus served from
+// the cache. Between MinI2CGapMs, CacheMaxAgeMs and retries the achieved rate
+// is not obvious; Snapshot reports the mean interval between real reads and
+// the cache hit ratio over the last rateWindow reads.
+//
+package aliexpress_orp
+
+import (
+	"log"
+	"time"
+)
+
+const (
+	// rateWindow is the number of recent reads (hits and real reads) tracked.
+	rateWindow = 20
+
+	// A hit ratio above this means the poll rate outruns CacheMaxAgeMs.
+	highCacheHitRatio = 0.8
+)
+
+// rateTracker is guarded by the driver's mu.
+type rateTracker struct {
+	hits      []bool // last rateWindow reads, true = cache hit
+	hitNext   int
+	intervals []time.Duration // between consecutive real reads
+	intNext   int
+	lastFresh time.Time
+	reads     int
+}
+
+func (r *rateTracker) record(hit bool, now time.Time) {
+	r.hitNext = ringPut(&r.hits, r.hitNext, hit)
+	if !hit {
+		if !r.lastFresh.IsZero() {
+			r.intNext = ringPut(&r.intervals, r.intNext, now.Sub(r.lastFresh))
+		}
+		r.lastFresh = now
+	}
+	r.reads++
+}
+
+// stats returns the mean interval between real reads (0 until two exist), the
+// cache hit ratio and the number of reads it covers.
+func (r *rateTracker) stats() (interval time.Duration, hitRatio float64, n int) {
+	for _, d := range r.intervals {
+		interval += d
+	}
+	if len(r.intervals) > 0 {
+		interval /= time.Duration(len(r.intervals))
+	}
+	hits := 0
+	for _, h := range r.hits {
+		if h {
+			hits++
+		}
+	}
+	if len(r.hits) > 0 {
+		hitRatio = float64(hits) / float64(len(r.hits))
+	}
+	return interval, hitRatio, len(r.hits)
+}
+
+// ringPut stores v in a rateWindow-sized ring and returns the next index.
+func ringPut[T any](buf *[]T, next int, v T) int {
+	if len(*buf) < rateWindow {
+		*buf = append(*buf, v)
+		return next
+	}
+	(*buf)[next] = v
+	return (next + 1) % rateWindow
+}
+
+// recordReadLocked tracks one read; in debug mode it logs the achieved rate
+// once per rateWindow reads. Caller holds d.mu.
+func (d *AliExpressORP) recordReadLocked(hit bool) {
+	d.rate.record(hit, time.Now())
+	if d.debug && d.rate.reads%rateWindow == 0 {
+		interval, ratio, n := d.rate.stats()
+		log.Printf("aliexpress_orp addr=0x%02X read rate: real read every %v, cache hits %.0f%% of last %d reads",
+			d.addr, interval.Round(time.Millisecond), ratio*100, n)
+	}
+}
+
+// readRate returns the rate stats under d.mu.
+func (d *AliExpressORP) readRate() (interval time.Duration, hitRatio float64, n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.rate.stats()
+}
@@ -0,0 +1,56 @@
+package aliexpress_orp
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRateTracker(t *testing.T) {
+	var r rateTracker
+	if interval, ratio, n := r.stats(); interval != 0 || ratio != 0 || n != 0 {
+		t.Fatalf("empty: %v %v %d", interval, ratio, n)
+	}
+
+	// Real reads every 100 ms, each followed by three cache hits.
+	t0 := time.Unix(0, 0)
+	for i := 0; i < 4; i++ {
+		now := t0.Add(time.Duration(i) * 100 * time.Millisecond)
+		r.record(false, now)
+		for j := 0; j < 3; j++ {
+			r.record(true, now)
+		}
+	}
+	interval, ratio, n := r.stats()
+	if interval != 100*time.Millisecond || math.Abs(ratio-0.75) > 1e-9 || n != 16 {
+		t.Errorf("got interval=%v ratio=%v n=%d; want 100ms, 0.75, 16", interval, ratio, n)
+	}
+
+	// Once the ring wraps, only the last rateWindow reads count: 20 real reads
+	// 50 ms apart push out every hit and every 100 ms interval.
+	last := t0.Add(300 * time.Millisecond)
+	for i := 1; i <= rateWindow; i++ {
+		r.record(false, last.Add(time.Duration(i)*50*time.Millisecond))
+	}
+	interval, ratio, n = r.stats()
+	if interval != 50*time.Millisecond || ratio != 0 || n != rateWindow {
+		t.Errorf("after wrap: interval=%v ratio=%v n=%d; want 50ms, 0, %d", interval, ratio, n, rateWindow)
+	}
+	if len(r.hits) != rateWindow || len(r.intervals) != rateWindow {
+		t.Errorf("ring grew past rateWindow: hits=%d intervals=%d", len(r.hits), len(r.intervals))
+	}
+}
+
+func TestReadRateCountsCacheHits(t *testing.T) {
+	d, bus := newTestORP(300)
+	d.cacheMaxAge = time.Hour
+	for i := 0; i < 5; i++ {
+		if _, _, _, err := d.readObservedMV(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	interval, ratio, n := d.readRate()
+	if bus.reads != 1 || n != 5 || math.Abs(ratio-0.8) > 1e-9 || interval != 0 {
+		t.Errorf("reads=%d n=%d ratio=%v interval=%v; want 1, 5, 0.8, 0", bus.reads, n, ratio, interval)
+	}
+}
@@ -0,0 +1,58 @@
+// tempsens.go
+//
+// Advisory temperature sensitivity (TempSensitivityMvPerC).
+//
+// ORP readings drift with temperature, but the driver never corrects for it.
+// With a sensitivity configured and a temperature injected, Snapshot estimates
+// how much of the ORP change since the reference temperature could be
+// temperature-driven. The reference is the first injected temperature, moved
+// to the current one by every calibration. Nothing here alters a reading.
+//
+package aliexpress_orp
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// Limit for TempSensitivityMvPerC; real electrodes sit within a few mV/°C.
+	maxTempSensitivityMVPerC = 20.0
+
+	// Temperatures older than this are not used for the estimate.
+	tempSensStaleAfter = 5 * time.Minute
+
+	// Estimated temperature-driven changes below this are not worth a note.
+	tempSensNoteMinMV = 2.0
+)
+
+// tempSensitivityMeta adds the advisory estimate to meta, and a note when the
+// estimate is large enough to matter.
+func (d *AliExpressORP) tempSensitivityMeta(meta map[string]any, notes []string) []string {
+	d.mu.Lock()
+	sens := d.tempSensMVPerC
+	tempC, updatedAt := d.tempC, d.tempUpdatedAt
+	refC, refSet := d.tempRefC, d.tempRefSet
+	d.mu.Unlock()
+
+	meta["temp_sensitivity_mv_per_c"] = sens
+	if !updatedAt.IsZero() {
+		meta["temp_c"] = tempC
+		meta["temp_age_sec"] = time.Since(updatedAt).Seconds()
+	}
+	if sens == 0 || !refSet || updatedAt.IsZero() || time.Since(updatedAt) > tempSensStaleAfter {
+		return notes
+	}
+
+	dT := tempC - refC
+	est := sens * dT
+	meta["temp_ref_c"] = refC
+	meta["temp_driven_mv_est"] = est
+	if math.Abs(est) >= tempSensNoteMinMV {
+		notes = append(notes, fmt.Sprintf(
+			"Temperature moved %+.1f°C since %.1f°C; at %.2f mV/°C about %+.1f mV of any ORP change may be temperature-driven rather than real (advisory, not corrected).",
+			dT, refC, sens, est))
+	}
+	return notes
+}
@@ -0,0 +1,52 @@
+package aliexpress_orp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/reef-pi/hal"
+)
+
+func TestTempSensitivityMeta(t *testing.T) {
+	d, _ := newTestORP(200)
+	d.tempSensMVPerC = -1.5
+	p := d.pins[0]
+
+	// The first injected temperature becomes the reference.
+	p.SetTemperatureC(24)
+	p.SetTemperatureC(28)
+	meta := map[string]any{}
+	notes := d.tempSensitivityMeta(meta, nil)
+	if est, _ := meta["temp_driven_mv_est"].(float64); math.Abs(est+6) > 1e-9 {
+		t.Errorf("estimate %v, want -6 (4°C at -1.5 mV/°C)", meta["temp_driven_mv_est"])
+	}
+	if meta["temp_ref_c"] != 24.0 || len(notes) != 1 {
+		t.Errorf("ref %v, notes %q; want 24 and one note", meta["temp_ref_c"], notes)
+	}
+
+	// Calibrate moves the reference to the current temperature.
+	if err := p.Calibrate([]hal.Measurement{{Expected: 210, Observed: 200}}); err != nil {
+		t.Fatal(err)
+	}
+	meta = map[string]any{}
+	notes = d.tempSensitivityMeta(meta, nil)
+	if meta["temp_ref_c"] != 28.0 || meta["temp_driven_mv_est"] != 0.0 || len(notes) != 0 {
+		t.Errorf("after Calibrate: ref %v est %v notes %q; want 28, 0, none",
+			meta["temp_ref_c"], meta["temp_driven_mv_est"], notes)
+	}
+
+	// A stale temperature gives no estimate, only its age.
+	p.SetTemperatureC(32)
+	d.mu.Lock()
+	d.tempUpdatedAt = time.Now().Add(-tempSensStaleAfter - time.Second)
+	d.mu.Unlock()
+	meta = map[string]any{}
+	notes = d.tempSensitivityMeta(meta, nil)
+	if _, ok := meta["temp_driven_mv_est"]; ok || len(notes) != 0 {
+		t.Errorf("stale temperature: est %v notes %q; want none", meta["temp_driven_mv_est"], notes)
+	}
+	if _, ok := meta["temp_age_sec"]; !ok {
+		t.Error("stale temperature: temp_age_sec missing")
+	}
+}
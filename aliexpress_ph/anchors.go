@@ -0,0 +1,56 @@
+// anchors.go
+//
+// Anchor order check.
+//
+// With the configured Polarity, the electrode mV must move one way as pH
+// rises: down for "negative" (PH4_mV > PH7_mV > PH10_mV), up for "positive".
+// Swapped PH4/PH10 entries flip the fitted slope and the driver would read a
+// confidently inverted pH, so the factory, Calibrate and ImportCalibration
+// refuse anchors that break the order. Anchors left at 0 are not configured
+// and are skipped; equal anchors carry no order and are left to the slope
+// checks.
+//
+package aliexpress_ph
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrAnchorOrder is returned when the buffer anchors are not ordered the way
+// Polarity says the electrode moves.
+var ErrAnchorOrder = errors.New("aliexpress_ph: calibration anchors out of order")
+
+// checkAnchorOrder verifies the enabled anchors against polarity.
+func checkAnchorOrder(ph4mV, ph7mV, ph10mV float64, polarity string) error {
+	// falls reports whether mV moves the expected way from the lower-pH
+	// anchor lo to the higher-pH anchor hi.
+	falls := func(lo, hi float64) bool {
+		if polarity == polarityPositive {
+			return hi >= lo
+		}
+		return hi <= lo
+	}
+	way := "lower"
+	if polarity == polarityPositive {
+		way = "higher"
+	}
+
+	ok4, ok10 := ph4mV == 0 || falls(ph4mV, ph7mV), ph10mV == 0 || falls(ph7mV, ph10mV)
+	switch {
+	case ok4 && ok10:
+		return nil
+	case !ok4 && !ok10:
+		return fmt.Errorf("%w: PH4 and PH10 anchors appear swapped (PH4=%.2f PH7=%.2f PH10=%.2f mV; Polarity=%s expects %s mV at higher pH)",
+			ErrAnchorOrder, ph4mV, ph7mV, ph10mV, polarity, way)
+	case ph4mV != 0 && ph10mV != 0:
+		return fmt.Errorf("%w: PH7 anchor is not between PH4 and PH10 (PH4=%.2f PH7=%.2f PH10=%.2f mV); check for swapped entries",
+			ErrAnchorOrder, ph4mV, ph7mV, ph10mV)
+	case !ok4:
+		return fmt.Errorf("%w: PH4 anchor %.2f mV is on the wrong side of PH7 %.2f mV for Polarity=%s (expects %s mV at higher pH); was the PH10 reading entered as PH4?",
+			ErrAnchorOrder, ph4mV, ph7mV, polarity, way)
+	default:
+		return fmt.Errorf("%w: PH10 anchor %.2f mV is on the wrong side of PH7 %.2f mV for Polarity=%s (expects %s mV at higher pH); was the PH4 reading entered as PH10?",
+			ErrAnchorOrder, ph10mV, ph7mV, polarity, way)
+	}
+}
@@ -0,0 +1,55 @@
+// diagnose.go
+//
+// Health report (see package diag).
+//
+// The liveness check is one module read (readObservedMV: a cache hit means
+// the module answered within CacheMaxAgeMs). Warm-up is reported as a warning,
+// not a failure. Code range, slope polarity and slope limit checks mirror the
+// Snapshot warnings.
+//
+package aliexpress_ph
+
+import (
+	"errors"
+	"time"
+
+	"github.com/reef-pi/drivers/diag"
+)
+
+var _ diag.Diagnoser = (*AliExpressPH)(nil)
+
+// Diagnose reads the module once and checks the calibration.
+func (d *AliExpressPH) Diagnose() diag.Diagnostics {
+	r := diag.New()
+
+	mv, _, code, err := d.readObservedMV()
+	switch {
+	case errors.Is(err, ErrWarmingUp):
+		r.Warnf("%v", err)
+	case r.Check("read module", err):
+		r.Infof("observed_mv=%.2f adc=0x%08X", mv, uint32(code))
+		if !d.codeInRange(code) {
+			r.Warnf("ADC code 0x%08X is outside CodeMin..CodeMax; saturated input, wrong Vref or a wiring fault", uint32(code))
+		}
+	}
+
+	s25 := d.slope25C(false)
+	if d.polarityMismatch(s25) {
+		r.Warnf("slope %.4f mV/pH does not match Polarity=%s; check the anchors", s25, d.polarity)
+	}
+	if fit := d.fitSlope25C(); fit != 0 && fit != s25 {
+		r.Warnf("calibrated slope %.2f mV/pH is outside SlopeLimitPct=%.1f%%; a buffer may be exhausted", fit, d.slopeLimitPct)
+	}
+
+	d.mu.Lock()
+	doTempComp, updatedAt := d.doTempComp, d.tempUpdatedAt
+	d.mu.Unlock()
+	if doTempComp {
+		if updatedAt.IsZero() {
+			r.Warnf("temperature compensation is on but no temperature was ever injected")
+		} else if age := time.Since(updatedAt); age > 2*time.Minute {
+			r.Warnf("injected temperature is stale (age %v)", age.Round(time.Second))
+		}
+	}
+	return r
+}
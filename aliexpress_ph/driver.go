@@ -9,26 +9,32 @@ import (
 	"sync"
 	"time"
 
+	"github.com/reef-pi/drivers/i2creg"
+	"github.com/reef-pi/drivers/internal/adc24"
 	"github.com/reef-pi/hal"
 	"github.com/reef-pi/rpi/i2c"
 )
 
 const (
+	driverType = "aliexpress-ph" // stable identifier, see DriverType
 	driverName = "AliExpress I2C pH (ADC→mV→pH)"
 
-	// ADC.cpp constants (offset-binary, mid-scale = 0V)
-	adcOffsetBinaryMid = 0x20000000
-	adcScale           = 536870912.0 // 2^29
-
 	// Ideal Nernst slope magnitude at 25C, mV per pH
 	idealSlope25C = 59.16
 	refTempK25C   = 298.15 // 25C in Kelvin
 
-	// Timing tuning (cheap modules often need breathing room)
-	minI2CGap       = 35 * time.Millisecond  // minimum spacing between I2C transactions
-	settleAfterRead = 2 * time.Millisecond   // small settle delay after successful read
-	cacheMaxAge     = 250 * time.Millisecond // serve Snapshot from cache to avoid double-hit
-	retryDelay      = 20 * time.Millisecond  // wait before retry on transient error
+	// Timing tuning (cheap modules often need breathing room).
+	// Defaults; MinI2CGapMs / CacheMaxAgeMs / SettleAfterReadMs / RetryDelayMs override them.
+	defaultMinI2CGap       = 35 * time.Millisecond  // minimum spacing between I2C transactions
+	defaultSettleAfterRead = 2 * time.Millisecond   // small settle delay after successful read
+	defaultCacheMaxAge     = 250 * time.Millisecond // serve Snapshot from cache to avoid double-hit
+	defaultRetryDelay      = 20 * time.Millisecond  // wait before retry on transient error
+
+	// CalibrateVref: the known input must be far enough from 0V to back-solve a gain,
+	// and the result must stay near the nominal 2.5V (a bad reference input is far off).
+	minVrefCalMV = 50.0
+	minVrefV     = 2.0
+	maxVrefV     = 3.0
 )
 
 var (
@@ -54,9 +60,18 @@ type AliExpressPH struct {
 	bus  i2c.Bus
 	meta hal.Metadata
 
+	// claim is this instance's i2creg address claim (nil when config-only).
+	claim *i2creg.Handle
+
 	// Conversion / calibration parameters
 	vrefV float64 // ADC Vref (V), Arduino sketch uses 2.5
 
+	// decoder turns the 3 reply bytes into an offset-binary code (ModuleVariant).
+	decoder adc24.Decoder
+
+	// Set by CalibrateVref; zero time means vrefV is the configured value.
+	vrefCalAt time.Time
+
 	// Calibration anchors stored in mV at buffer pH values
 	ph7mV  float64
 	ph4mV  float64
@@ -65,6 +80,18 @@ type AliExpressPH struct {
 	// Optional slope override at 25C (mV per pH, typically negative)
 	slopeOverride float64
 
+	// polarity sets the sign of the ideal fallback slope and the sign
+	// computed slopes are checked against (polarityNegative / polarityPositive).
+	polarity string
+
+	// Single-point trim: when enabled, a pH7-only calibration re-zeros ph7mV
+	// but keeps the slope that was in effect before the trim (trimSlope25C).
+	ph7TrimKeepSlope bool
+	trimSlope25C     float64
+
+	// calTempC is the temperature at the last Calibrate (see calblob.go).
+	calTempC float64
+
 	// Temperature compensation (explicit, disabled by default)
 	doTempComp    bool
 	refTempC      float64 // reference temp (typically 25C)
@@ -79,6 +106,36 @@ type AliExpressPH struct {
 	// Local instance lock (helpful if bus impl isn’t thread-safe)
 	mu sync.Mutex
 
+	// Per-driver timing (see default* constants)
+	minI2CGap       time.Duration
+	settleAfterRead time.Duration
+	cacheMaxAge     time.Duration // 0 disables the cache
+	retryDelay      time.Duration
+
+	// Optional conversion trigger written before each read (nil = free-running module)
+	readCmd         []byte
+	conversionDelay time.Duration
+
+	// slopeLimitPct bounds an anchor-fitted slope to ±pct of the ideal (0 = off).
+	slopeLimitPct float64
+
+	// clampOutput limits Value() to 0..14 pH (ClampOutput, default on).
+	clampOutput bool
+
+	// decimals is the displayed pH precision; roundValue also rounds the
+	// reported value to it (see rounding.go).
+	decimals   int
+	roundValue bool
+
+	// Plausible ADC code band (CodeMin/CodeMax); codeMax 0 = no upper bound.
+	// Codes outside it are flagged in Snapshot, not rejected.
+	codeMin int32
+	codeMax int32
+
+	// warmup discards readings for this long after createdAt (see warmup.go).
+	warmup    time.Duration
+	createdAt time.Time
+
 	// Timing + caching to prevent "read then snapshot" hammering
 	lastXferAt   time.Time
 	lastSampleAt time.Time
@@ -106,6 +163,50 @@ func (d *AliExpressPH) SetTemperatureC(tempC float64) {
 	}
 }
 
+// Allow callers to run Vref calibration via pin type-assertion.
+func (p *phPin) CalibrateVref(knownMv float64) error { return p.parent.CalibrateVref(knownMv) }
+
+// CalibrateVref back-solves the effective ADC reference from a known input voltage.
+// Apply a precision mV source to the electrode input, then call with its value.
+// The input must be at least minVrefCalMV away from 0: a shorted input reads the
+// offset, not the gain, so it cannot determine Vref.
+func (d *AliExpressPH) CalibrateVref(knownMv float64) error {
+	if math.Abs(knownMv) < minVrefCalMV {
+		return fmt.Errorf("%s: Vref calibration needs a known input of at least %.0f mV (got %.2f)", driverName, minVrefCalMV, knownMv)
+	}
+
+	// Force a fresh conversion; the ADC code does not depend on vrefV.
+	d.mu.Lock()
+	d.lastSampleAt = time.Time{}
+	d.mu.Unlock()
+
+	_, _, code, err := d.readObservedMV()
+	if err != nil {
+		return err
+	}
+	signed := d.decoder.Signed(code)
+	if signed == 0 || (signed > 0) != (knownMv > 0) {
+		return fmt.Errorf("%s: ADC code 0x%08X does not match a %.2f mV input; check wiring", driverName, uint32(code), knownMv)
+	}
+
+	vref := (knownMv / 1000.0) * float64(d.decoder.Mid()) / signed
+	if vref < minVrefV || vref > maxVrefV {
+		return fmt.Errorf("%s: computed Vref %.4f V is outside %.1f..%.1f V; check the reference input", driverName, vref, minVrefV, maxVrefV)
+	}
+
+	d.mu.Lock()
+	old := d.vrefV
+	d.vrefV = vref
+	d.vrefCalAt = time.Now()
+	// Cached mV was computed with the old Vref.
+	d.lastSampleAt = time.Time{}
+	d.mu.Unlock()
+
+	log.Printf("aliexpress_ph addr=0x%02X calibrated Vref=%.4fV (was %.4fV) from known=%.2fmV code=0x%08X",
+		d.addr, vref, old, knownMv, uint32(code))
+	return nil
+}
+
 // ---------------- Low-level ADC read ----------------
 
 func isTransientI2C(err error) bool {
@@ -143,7 +244,7 @@ func (d *AliExpressPH) readObservedMV() (mv float64, raw []byte, adcCode int32,
 	defer d.mu.Unlock()
 
 	// 1) Cache: if a fresh sample exists, return it (prevents /read + /snapshot double-hit)
-	if !d.lastSampleAt.IsZero() && time.Since(d.lastSampleAt) < cacheMaxAge {
+	if !d.lastSampleAt.IsZero() && time.Since(d.lastSampleAt) < d.cacheMaxAge {
 		if d.debug {
 			log.Printf("aliexpress_ph addr=0x%02X cache hit age=%v mv=%.2f",
 				d.addr, time.Since(d.lastSampleAt), d.lastMV)
@@ -152,13 +253,28 @@ func (d *AliExpressPH) readObservedMV() (mv float64, raw []byte, adcCode int32,
 	}
 
 	// 2) Rate-limit actual I2C transactions to this device
-	d.enforceMinGap(minI2CGap)
+	d.enforceMinGap(d.minI2CGap)
 
 	// 3) Attempt read with one retry on transient error
 	var lastErr error
 	for attempt := 1; attempt <= 2; attempt++ {
 		d.lastXferAt = time.Now()
 
+		if len(d.readCmd) > 0 {
+			if e := d.bus.WriteBytes(d.addr, d.readCmd); e != nil {
+				lastErr = fmt.Errorf("read command % X: %w", d.readCmd, e)
+				if d.debug {
+					log.Printf("aliexpress_ph addr=0x%02X read attempt=%d error=%v", d.addr, attempt, lastErr)
+				}
+				if attempt == 1 && isTransientI2C(e) {
+					time.Sleep(d.retryDelay)
+					continue
+				}
+				return 0, nil, 0, lastErr
+			}
+			time.Sleep(d.conversionDelay)
+		}
+
 		payload, e := d.bus.ReadBytes(d.addr, 3)
 		if e != nil {
 			lastErr = e
@@ -166,7 +282,7 @@ func (d *AliExpressPH) readObservedMV() (mv float64, raw []byte, adcCode int32,
 				log.Printf("aliexpress_ph addr=0x%02X read attempt=%d error=%v", d.addr, attempt, e)
 			}
 			if attempt == 1 && isTransientI2C(e) {
-				time.Sleep(retryDelay)
+				time.Sleep(d.retryDelay)
 				continue
 			}
 			return 0, nil, 0, e
@@ -197,10 +313,31 @@ func (d *AliExpressPH) readObservedMV() (mv float64, raw []byte, adcCode int32,
 			return 0, payload, 0, lastErr
 		}
 
-		code := adcI2C24ToCode(payload)
-		v := adcCodeToVolts(code, d.vrefV)
+		code := d.decoder.Code(payload)
+		v := d.decoder.Volts(code, d.vrefV)
 		mv := v * 1000.0
 
+		// Never cache or return a non-finite mV; it would reach calibration and the UI.
+		if math.IsNaN(mv) || math.IsInf(mv, 0) {
+			lastErr = fmt.Errorf("non-finite mV %v from adc=0x%08X vref=%v payload=% X", mv, uint32(code), d.vrefV, payload)
+			if d.debug {
+				log.Printf("aliexpress_ph addr=0x%02X read attempt=%d error=%v", d.addr, attempt, lastErr)
+			}
+			if attempt == 1 {
+				time.Sleep(10 * time.Millisecond)
+				continue
+			}
+			return 0, payload, code, lastErr
+		}
+
+		// WarmupMs: the module was read, but the sample is dropped.
+		if err := d.warmupError(mv); err != nil {
+			if d.debug {
+				log.Printf("aliexpress_ph addr=0x%02X %v", d.addr, err)
+			}
+			return 0, payload, code, err
+		}
+
 		// 4) Cache last good sample (Snapshot can reuse it)
 		d.lastSampleAt = time.Now()
 		d.lastMV = mv
@@ -208,7 +345,7 @@ func (d *AliExpressPH) readObservedMV() (mv float64, raw []byte, adcCode int32,
 		d.lastCode = code
 
 		// 5) Small settle delay (helps cheap boards)
-		time.Sleep(settleAfterRead)
+		time.Sleep(d.settleAfterRead)
 
 		return mv, payload, code, nil
 	}
@@ -216,30 +353,27 @@ func (d *AliExpressPH) readObservedMV() (mv float64, raw []byte, adcCode int32,
 	return 0, nil, 0, lastErr
 }
 
-// Matches ADC.cpp behaviour proven by your Python test:
-// u32 = (b0<<24)|(b1<<16)|(b2<<8); u32>>=2; u32&=0x3FFFFFFF
-func adcI2C24ToCode(b []byte) int32 {
-	u32 := uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8
-	u32 >>= 2
-	u32 &= 0x3FFFFFFF
-	return int32(u32)
+// codeMaxOrFull returns the effective upper code bound (full scale when unset).
+func (d *AliExpressPH) codeMaxOrFull() int32 {
+	if d.codeMax == 0 {
+		return d.decoder.Full()
+	}
+	return d.codeMax
 }
 
-func adcCodeToVolts(code int32, vref float64) float64 {
-	// ADC.cpp:
-	// adc_code -= 0x20000000
-	// voltage = (adc_code/2^29)*vref
-	signed := float64(int64(code) - int64(adcOffsetBinaryMid))
-	return (signed / adcScale) * vref
+// codeInRange reports whether code lies within CodeMin..CodeMax.
+func (d *AliExpressPH) codeInRange(code int32) bool {
+	return code >= d.codeMin && code <= d.codeMaxOrFull()
 }
 
 // ---------------- Calibration math ----------------
 
 // slope25C chooses the slope at 25C (mV per pH), preferring:
 // 1) slopeOverride (if non-zero)
-// 2) PH4/PH7 anchors if available
-// 3) PH10/PH7 anchors if available
-// 4) ideal fallback (-59.16 mV/pH)
+// 2) slope kept from before a pH7 single-point trim (if non-zero)
+// 3) PH4/PH7 anchors if available
+// 4) PH10/PH7 anchors if available
+// 5) ideal fallback (-59.16 mV/pH, +59.16 with positive polarity)
 func (d *AliExpressPH) slope25C(debugLog bool) float64 {
 	if d.slopeOverride != 0 {
 		if debugLog {
@@ -248,6 +382,13 @@ func (d *AliExpressPH) slope25C(debugLog bool) float64 {
 		return d.slopeOverride
 	}
 
+	if d.trimSlope25C != 0 {
+		if debugLog {
+			log.Printf("aliexpress_ph addr=0x%02X slope: kept from before PH7 trim %.4f mV/pH @25C", d.addr, d.trimSlope25C)
+		}
+		return d.trimSlope25C
+	}
+
 	if d.ph4mV != 0 {
 		// slope = (mV4 - mV7)/(4 - 7)
 		s := (d.ph4mV - d.ph7mV) / (4.0 - 7.0)
@@ -255,7 +396,7 @@ func (d *AliExpressPH) slope25C(debugLog bool) float64 {
 			log.Printf("aliexpress_ph addr=0x%02X slope: from PH4/PH7 = %.4f mV/pH (PH4=%.2f PH7=%.2f)",
 				d.addr, s, d.ph4mV, d.ph7mV)
 		}
-		return s
+		return d.limitSlope(s, debugLog)
 	}
 	if d.ph10mV != 0 {
 		// slope = (mV10 - mV7)/(10 - 7)
@@ -264,16 +405,71 @@ func (d *AliExpressPH) slope25C(debugLog bool) float64 {
 			log.Printf("aliexpress_ph addr=0x%02X slope: from PH10/PH7 = %.4f mV/pH (PH10=%.2f PH7=%.2f)",
 				d.addr, s, d.ph10mV, d.ph7mV)
 		}
-		return s
+		return d.limitSlope(s, debugLog)
 	}
 
 	// Typical electrode: higher pH => lower mV => negative slope
 	if debugLog {
-		log.Printf("aliexpress_ph addr=0x%02X slope: fallback ideal %.4f mV/pH @25C", d.addr, -idealSlope25C)
+		log.Printf("aliexpress_ph addr=0x%02X slope: fallback ideal %.4f mV/pH @25C (polarity=%s)", d.addr, d.idealSlope(), d.polarity)
+	}
+	return d.idealSlope()
+}
+
+// fitSlope25C returns the slope fitted from the anchors before SlopeLimitPct,
+// or 0 when it is not in use (override, kept trim slope, or no second anchor).
+func (d *AliExpressPH) fitSlope25C() float64 {
+	switch {
+	case d.slopeOverride != 0, d.trimSlope25C != 0:
+		return 0
+	case d.ph4mV != 0:
+		return (d.ph4mV - d.ph7mV) / (4.0 - 7.0)
+	case d.ph10mV != 0:
+		return (d.ph10mV - d.ph7mV) / (10.0 - 7.0)
+	}
+	return 0
+}
+
+// slopeLimits returns the allowed slope magnitude band (mV/pH @25C) for SlopeLimitPct.
+func (d *AliExpressPH) slopeLimits() (lo, hi float64) {
+	return idealSlope25C * (1 - d.slopeLimitPct/100), idealSlope25C * (1 + d.slopeLimitPct/100)
+}
+
+// limitSlope clamps the magnitude of an anchor-fitted slope into the
+// SlopeLimitPct band around the ideal, keeping its sign (a polarity mismatch
+// stays visible). A contaminated buffer then cannot produce a wild slope.
+func (d *AliExpressPH) limitSlope(s float64, debugLog bool) float64 {
+	if d.slopeLimitPct <= 0 || s == 0 {
+		return s
+	}
+	lo, hi := d.slopeLimits()
+	mag := math.Min(math.Max(math.Abs(s), lo), hi)
+	limited := math.Copysign(mag, s)
+	if debugLog && limited != s {
+		log.Printf("aliexpress_ph addr=0x%02X slope: fit %.4f outside %.2f..%.2f mV/pH, clamped to %.4f",
+			d.addr, s, lo, hi, limited)
+	}
+	return limited
+}
+
+// Electrode polarity (Polarity parameter): the sign of the mV/pH slope.
+const (
+	polarityNegative = "negative" // usual wiring: higher pH => lower mV
+	polarityPositive = "positive" // reversed wiring
+)
+
+// idealSlope is the ideal 25C Nernst slope signed by the configured polarity.
+func (d *AliExpressPH) idealSlope() float64 {
+	if d.polarity == polarityPositive {
+		return idealSlope25C
 	}
 	return -idealSlope25C
 }
 
+// polarityMismatch reports whether slope has the opposite sign of the configured polarity.
+func (d *AliExpressPH) polarityMismatch(slope float64) bool {
+	return slope != 0 && (slope > 0) != (d.polarity == polarityPositive)
+}
+
 // slopeAtTemp applies Nernst scaling if enabled.
 // IMPORTANT: we only compensate because we have raw physical mV and we are not double-applying hardware compensation.
 func (d *AliExpressPH) slopeAtTemp(slope25 float64) (slope float64, enabled bool, reason string) {
@@ -292,17 +488,48 @@ func (d *AliExpressPH) slopeAtTemp(slope25 float64) (slope float64, enabled bool
 
 // mvToPH converts observed electrode mV to pH using:
 // pH = 7 + (mV - mV7)/slope
-func (d *AliExpressPH) mvToPH(mv float64, debugLog bool) (ph float64, slopeUsed float64) {
+// A non-finite input mV or resulting pH is an error, never a reading.
+func (d *AliExpressPH) mvToPH(mv float64, debugLog bool) (ph float64, slopeUsed float64, err error) {
+	if math.IsNaN(mv) || math.IsInf(mv, 0) {
+		return 0, 0, fmt.Errorf("aliexpress_ph addr=0x%02X: non-finite observed mV %v", d.addr, mv)
+	}
+
 	s25 := d.slope25C(debugLog)
-	slope, _, _ := d.slopeAtTemp(s25)
 
 	// Guard
-	if slope == 0 || math.IsNaN(slope) || math.IsInf(slope, 0) {
-		slope = -idealSlope25C
+	if s25 == 0 || math.IsNaN(s25) || math.IsInf(s25, 0) {
+		s25 = d.idealSlope()
 	}
 
-	ph = 7.0 + ((mv - d.ph7mV) / slope)
-	return ph, slope
+	ph, slope := computePH(mv, d.ph7mV, s25, d.tempC, d.doTempComp)
+	if math.IsNaN(ph) || math.IsInf(ph, 0) {
+		return 0, slope, fmt.Errorf("aliexpress_ph addr=0x%02X: non-finite pH from mV=%v PH7=%v slope=%v", d.addr, mv, d.ph7mV, slope)
+	}
+	return ph, slope, nil
+}
+
+// computePH is the pure conversion, free of I/O, locks and logging (so it can
+// be benchmarked alone): slope25 is Nernst-scaled to tempC when tempComp is set,
+// then pH = 7 + (mV - mV7)/slope. Same scaling as slopeAtTemp.
+func computePH(mv, mv7, slope25, tempC float64, tempComp bool) (ph, slope float64) {
+	slope = slope25
+	if tk := tempC + 273.15; tempComp && tk > 0 {
+		slope = slope25 * (tk / refTempK25C)
+	}
+	return 7.0 + ((mv - mv7) / slope), slope
+}
+
+// observedMVRef returns mv as the electrode would read it at 25°C, the
+// temperature the anchors are taken to be at: the deviation from the pH7
+// anchor (the isopotential point) shrinks by 298.15/T. Anchors captured from
+// it match what mvToPH expects at any probe temperature. Without temp comp
+// (or a usable temperature) mv is returned as-is.
+func (d *AliExpressPH) observedMVRef(mv float64) float64 {
+	tk := d.tempC + 273.15
+	if !d.doTempComp || tk <= 0 {
+		return mv
+	}
+	return d.ph7mV + (mv-d.ph7mV)*(refTempK25C/tk)
 }
 
 // ---------------- phPin: hal.AnalogInputPin ----------------
@@ -316,21 +543,27 @@ func (p *phPin) Value() (float64, error) {
 		return 0, err
 	}
 
-	ph, slope := p.parent.mvToPH(mv, p.parent.debug)
+	ph, slope, err := p.parent.mvToPH(mv, p.parent.debug)
+	if err != nil {
+		return 0, err
+	}
 
 	if p.parent.debug {
 		log.Printf("aliexpress_ph addr=0x%02X raw=% X adc=0x%08X observed_mv=%.2f PH7=%.2f slope=%.4f tempC=%.2f -> pH=%.4f",
 			p.parent.addr, raw, uint32(code), mv, p.parent.ph7mV, slope, p.parent.tempC, ph)
 	}
 
-	// Soft clamp (optional; prevents UI spikes)
+	// Soft clamp (ClampOutput; prevents UI spikes). mvToPH already rejected NaN/Inf.
+	if !p.parent.clampOutput {
+		return p.parent.roundPH(ph), nil
+	}
 	if ph < 0 {
 		ph = 0
 	}
 	if ph > 14 {
 		ph = 14
 	}
-	return ph, nil
+	return p.parent.roundPH(ph), nil
 }
 
 func (p *phPin) Measure() (float64, error) { return p.Value() }
@@ -338,18 +571,66 @@ func (p *phPin) Measure() (float64, error) { return p.Value() }
 // Calibrate accepts measurements where:
 // - Expected = buffer pH (typically 4, 7, 10)
 // - Observed = observed electrode mV (the calibration wizard uses meta wiring keys)
-// If Observed is 0, we will read live observed mV for convenience/back-compat.
+// With DoTempComp the wizard's observed key is observed_mv_ref, the mV normalized to 25°C.
+// If Observed is 0, we will read live observed mV for convenience/back-compat,
+// normalized the same way. The pH7 point is resolved first and a live pH7
+// reading is used as-is (it is the isopotential point); live pH4/pH10 readings
+// are then normalized around the new pH7 anchor, so the order of ms does not matter.
+//
+// With PH7TrimKeepSlope enabled, a calibration that only supplies pH7 is a
+// single-point trim: ph7mV moves but the slope in effect before the trim is kept.
+// Supplying a pH4 or pH10 point is a full recalibration and drops the kept slope.
+//
+// Anchors that end up out of order for Polarity (e.g. pH4 and pH10 buffers
+// swapped) are refused and the previous calibration stays (see anchors.go).
 func (p *phPin) Calibrate(ms []hal.Measurement) error {
+	d := p.parent
+	prev7, prev4, prev10, prevTrim := d.ph7mV, d.ph4mV, d.ph10mV, d.trimSlope25C
+	restore := func() { d.ph7mV, d.ph4mV, d.ph10mV, d.trimSlope25C = prev7, prev4, prev10, prevTrim }
+
+	onlyPH7 := len(ms) > 0
+	for _, m := range ms {
+		if m.Expected != 7 {
+			onlyPH7 = false
+		}
+	}
+
+	if p.parent.ph7TrimKeepSlope && onlyPH7 {
+		if p.parent.slopeOverride == 0 && p.parent.trimSlope25C == 0 &&
+			(p.parent.ph4mV != 0 || p.parent.ph10mV != 0) {
+			p.parent.trimSlope25C = p.parent.slope25C(false)
+			log.Printf("aliexpress_ph PH7 trim: keeping slope %.4f mV/pH @25C", p.parent.trimSlope25C)
+		}
+	} else {
+		p.parent.trimSlope25C = 0
+	}
+
+	ordered := make([]hal.Measurement, 0, len(ms))
+	for _, m := range ms {
+		if m.Expected == 7 {
+			ordered = append(ordered, m)
+		}
+	}
 	for _, m := range ms {
+		if m.Expected != 7 {
+			ordered = append(ordered, m)
+		}
+	}
+
+	for _, m := range ordered {
 		exp := m.Expected
 		obs := m.Observed
 
 		if obs == 0 {
 			mv, _, _, err := p.parent.readObservedMV()
 			if err != nil {
+				restore()
 				return err
 			}
 			obs = mv
+			if exp != 7 {
+				obs = p.parent.observedMVRef(mv)
+			}
 		}
 
 		switch {
@@ -363,9 +644,30 @@ func (p *phPin) Calibrate(ms []hal.Measurement) error {
 			p.parent.ph10mV = obs
 			log.Printf("aliexpress_ph calibrated PH10_mV=%.2f", obs)
 		default:
+			restore()
 			return fmt.Errorf("%s: unsupported calibration Expected=%.3f (use 4,7,10 for pH buffers)", driverName, exp)
 		}
 	}
+
+	if err := checkAnchorOrder(d.ph4mV, d.ph7mV, d.ph10mV, d.polarity); err != nil {
+		restore()
+		log.Printf("aliexpress_ph addr=0x%02X calibration refused: %v", d.addr, err)
+		return err
+	}
+
+	p.parent.calTempC = p.parent.refTempC
+	if !p.parent.tempUpdatedAt.IsZero() {
+		p.parent.calTempC = p.parent.tempC
+	}
+
+	if s := p.parent.slope25C(false); p.parent.polarityMismatch(s) {
+		log.Printf("aliexpress_ph addr=0x%02X WARNING: calibrated slope %.4f mV/pH does not match Polarity=%s",
+			p.parent.addr, s, p.parent.polarity)
+	}
+	if fit, s := p.parent.fitSlope25C(), p.parent.slope25C(false); fit != 0 && fit != s {
+		log.Printf("aliexpress_ph addr=0x%02X WARNING: calibrated slope %.4f mV/pH is outside SlopeLimitPct=%.1f%%; using %.4f (check the buffers)",
+			p.parent.addr, fit, p.parent.slopeLimitPct, s)
+	}
 	return nil
 }
 
@@ -380,7 +682,18 @@ func (p *phPin) Snapshot() (hal.Snapshot, error) {
 	if err != nil {
 		return hal.Snapshot{}, err
 	}
-	ph, slope := p.parent.mvToPH(mv, false)
+	ph, slope, err := p.parent.mvToPH(mv, false)
+	if err != nil {
+		return hal.Snapshot{}, err
+	}
+
+	// With temp comp the wizard calibrates against mV normalized to 25°C, like
+	// the anchors the conversion assumes; otherwise against the raw mV.
+	mvRef := p.parent.observedMVRef(mv)
+	observedKey := "observed_mv"
+	if p.parent.doTempComp {
+		observedKey = "observed_mv_ref"
+	}
 
 	// temp-comp meta
 	s25 := p.parent.slope25C(false)
@@ -390,6 +703,22 @@ func (p *phPin) Snapshot() (hal.Snapshot, error) {
 	}
 
 	notes := []string{}
+	if p.parent.polarityMismatch(s25) {
+		notes = append(notes, fmt.Sprintf(
+			"WARNING: slope %.4f mV/pH does not match Polarity=%s. Check the anchors/slope override, or set Polarity to match the wiring.",
+			s25, p.parent.polarity))
+	}
+	fit := p.parent.fitSlope25C()
+	slopeClamped := fit != 0 && fit != s25
+	if slopeClamped {
+		lo, hi := p.parent.slopeLimits()
+		notes = append(notes, fmt.Sprintf(
+			"WARNING: calibrated slope %.2f mV/pH is outside %.2f..%.2f (SlopeLimitPct=%.1f%%); using %.2f. A buffer may be contaminated or exhausted.",
+			fit, lo, hi, p.parent.slopeLimitPct, s25))
+	}
+	if p.parent.slopeOverride == 0 && p.parent.trimSlope25C != 0 {
+		notes = append(notes, fmt.Sprintf("Slope %.4f mV/pH kept from before the last PH7 single-point trim.", p.parent.trimSlope25C))
+	}
 	if p.parent.doTempComp {
 		if p.parent.tempUpdatedAt.IsZero() {
 			notes = append(notes, "Temp compensation enabled but temperature has never been injected; results may be off.")
@@ -399,49 +728,93 @@ func (p *phPin) Snapshot() (hal.Snapshot, error) {
 	} else {
 		notes = append(notes, "Temp compensation disabled (explicit by configuration).")
 	}
+	if !p.parent.vrefCalAt.IsZero() {
+		notes = append(notes, fmt.Sprintf("ADC Vref %.4f V calibrated from a known input at %s; anchors captured before that are in the old mV scale.",
+			p.parent.vrefV, p.parent.vrefCalAt.Format(time.RFC3339)))
+	}
+
+	if !p.parent.clampOutput && (ph < 0 || ph > 14) {
+		notes = append(notes, fmt.Sprintf("pH %.3f is outside 0..14 and reported unclamped (ClampOutput off).", ph))
+	}
+
+	outOfRange := !p.parent.codeInRange(code)
+	if outOfRange {
+		notes = append(notes, fmt.Sprintf(
+			"WARNING: ADC code 0x%08X is outside CodeMin..CodeMax (0x%08X..0x%08X); likely a saturated input, wrong Vref, or a wiring fault. The mV reading is not meaningful.",
+			uint32(code), uint32(p.parent.codeMin), uint32(p.parent.codeMaxOrFull())))
+	}
 
 	meta := map[string]any{
 		"channel": p.ch,
 
 		// Calibration wiring
-		"calibration_observed_key": "observed_mv",
+		"calibration_observed_key": observedKey,
 		"raw_signal_key":           "observed_mv",
 		"primary_signal_key":       "value",
-		"secondary_signal_keys":    []string{"slope_used", "tempC", "ph7_mV", "ph4_mV", "ph10_mV", "adc_code"},
+		"secondary_signal_keys":    []string{"observed_mv_ref", "slope_used", "tempC", "ph7_mV", "ph4_mV", "ph10_mV", "adc_code"},
 
 		"display_roles": map[string]any{
 			"primary":  "Primary (pH)",
 			"observed": "Observed (electrode mV)",
 		},
 		"display_names": map[string]any{
-			"value":       "pH (calibrated)",
-			"observed_mv": "Electrode (mV)",
-			"slope_used":  "Slope used (mV/pH)",
-			"tempC":       "Temperature (°C)",
-			"ph7_mV":      "Anchor: pH7 (mV)",
-			"ph4_mV":      "Anchor: pH4 (mV)",
-			"ph10_mV":     "Anchor: pH10 (mV)",
-			"adc_code":    "ADC code (offset-binary)",
-			"raw_hex":     "Raw bytes (hex)",
+			"value":           "pH (calibrated)",
+			"observed_mv":     "Electrode (mV)",
+			"observed_mv_ref": "Electrode (mV @25°C)",
+			"slope_used":      "Slope used (mV/pH)",
+			"tempC":           "Temperature (°C)",
+			"ph7_mV":          "Anchor: pH7 (mV)",
+			"ph4_mV":          "Anchor: pH4 (mV)",
+			"ph10_mV":         "Anchor: pH10 (mV)",
+			"adc_code":        "ADC code (offset-binary)",
+			"raw_hex":         "Raw bytes (hex)",
 		},
 		"display_help": map[string]any{
-			"observed_mv": "Raw physical electrode millivolts from the I2C ADC module. This is what calibration anchors map against.",
-			"slope_used":  "Slope (mV per pH) computed from anchors or override; optionally temperature-scaled.",
-			"ph7_mV":      "Measured electrode mV in pH 7 buffer (required anchor).",
-			"ph4_mV":      "Measured electrode mV in pH 4 buffer (recommended).",
-			"ph10_mV":     "Measured electrode mV in pH 10 buffer (optional).",
+			"observed_mv":     "Raw physical electrode millivolts from the I2C ADC module. Calibration anchors map against this when temp compensation is off.",
+			"observed_mv_ref": "Electrode mV normalized to 25°C around the pH7 anchor. Calibration anchors map against this when temp compensation is on; equals observed_mv otherwise.",
+			"slope_used":      "Slope (mV per pH) computed from anchors or override; optionally temperature-scaled.",
+			"ph7_mV":          "Measured electrode mV in pH 7 buffer (required anchor).",
+			"ph4_mV":          "Measured electrode mV in pH 4 buffer (recommended).",
+			"ph10_mV":         "Measured electrode mV in pH 10 buffer (optional).",
 		},
 		"signal_decimals": map[string]any{
-			"value":       3,
-			"observed_mv": 2,
-			"slope_used":  4,
-			"tempC":       2,
-			"ph7_mV":      2,
-			"ph4_mV":      2,
-			"ph10_mV":     2,
-			"adc_code":    0,
+			"value":           p.parent.decimals,
+			"observed_mv":     2,
+			"observed_mv_ref": 2,
+			"slope_used":      4,
+			"tempC":           2,
+			"ph7_mV":          2,
+			"ph4_mV":          2,
+			"ph10_mV":         2,
+			"adc_code":        0,
 		},
 
+		"polarity":          p.parent.polarity,
+		"polarity_mismatch": p.parent.polarityMismatch(s25),
+
+		"slope_fit_raw":   fit,
+		"slope_clamped":   slopeClamped,
+		"slope_25c":       s25,
+		"slope_limit_pct": p.parent.slopeLimitPct,
+		"clamp_output":    p.parent.clampOutput,
+
+		"vref_v":          p.parent.vrefV,
+		"vref_calibrated": !p.parent.vrefCalAt.IsZero(),
+		"cal_temp_c":      p.parent.calTempC,
+
+		"module_variant": p.parent.decoder.Variant,
+		"decode_shift":   p.parent.decoder.Shift,
+		"decode_mask":    p.parent.decoder.MaskString(),
+
+		"code_min":          p.parent.codeMin,
+		"code_max":          p.parent.codeMaxOrFull(),
+		"code_out_of_range": outOfRange,
+
+		"warmup_ms": p.parent.warmup.Milliseconds(),
+
+		"decimals":    p.parent.decimals,
+		"round_value": p.parent.roundValue,
+
 		"temp_compensation": map[string]any{
 			"enabled": p.parent.doTempComp && enabled,
 			"reason": func() string {
@@ -461,17 +834,18 @@ func (p *phPin) Snapshot() (hal.Snapshot, error) {
 	}
 
 	return hal.Snapshot{
-		Value: ph,
+		Value: p.parent.roundPH(ph),
 		Unit:  "pH",
 		Signals: map[string]hal.Signal{
-			"observed_mv": {Now: mv, Unit: "mV"},
-			"slope_used":  {Now: slope, Unit: "mV/pH"},
-			"tempC":       {Now: p.parent.tempC, Unit: "C"},
-			"ph7_mV":      {Now: p.parent.ph7mV, Unit: "mV"},
-			"ph4_mV":      {Now: p.parent.ph4mV, Unit: "mV"},
-			"ph10_mV":     {Now: p.parent.ph10mV, Unit: "mV"},
-			"adc_code":    {Now: float64(code), Unit: ""},
-			"raw_hex":     {Now: 0, Unit: fmt.Sprintf("% X", raw)},
+			"observed_mv":     {Now: mv, Unit: "mV"},
+			"observed_mv_ref": {Now: mvRef, Unit: "mV"},
+			"slope_used":      {Now: slope, Unit: "mV/pH"},
+			"tempC":           {Now: p.parent.tempC, Unit: "C"},
+			"ph7_mV":          {Now: p.parent.ph7mV, Unit: "mV"},
+			"ph4_mV":          {Now: p.parent.ph4mV, Unit: "mV"},
+			"ph10_mV":         {Now: p.parent.ph10mV, Unit: "mV"},
+			"adc_code":        {Now: float64(code), Unit: ""},
+			"raw_hex":         {Now: 0, Unit: fmt.Sprintf("% X", raw)},
 		},
 		Meta: meta,
 		Notes: append(notes,
@@ -484,9 +858,16 @@ func (p *phPin) Snapshot() (hal.Snapshot, error) {
 // ---------------- hal.Driver plumbing ----------------
 
 func (d *AliExpressPH) Name() string           { return driverName }
-func (d *AliExpressPH) Close() error           { return nil }
 func (d *AliExpressPH) Metadata() hal.Metadata { return d.meta }
 
+func (d *AliExpressPH) Close() error {
+	d.claim.Release()
+	return nil
+}
+
+// DriverType returns the stable driver kind for host-side routing.
+func (d *AliExpressPH) DriverType() string { return driverType }
+
 func (d *AliExpressPH) AnalogInputPin(n int) (hal.AnalogInputPin, error) {
 	if n != 0 {
 		return nil, fmt.Errorf("%s supports only channel 0 (pH). Asked:%d", driverName, n)
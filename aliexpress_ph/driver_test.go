@@ -0,0 +1,352 @@
+package aliexpress_ph
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reef-pi/drivers/internal/adc24"
+	"github.com/reef-pi/hal"
+)
+
+// payloadBus is an i2c.Bus that returns the same payload on every read.
+type payloadBus struct {
+	payload []byte
+	reads   int
+}
+
+func (b *payloadBus) SetAddress(_ byte) error { return nil }
+func (b *payloadBus) ReadBytes(_ byte, _ int) ([]byte, error) {
+	b.reads++
+	return append([]byte(nil), b.payload...), nil
+}
+func (b *payloadBus) WriteBytes(_ byte, _ []byte) error     { return nil }
+func (b *payloadBus) ReadFromReg(_, _ byte, _ []byte) error { return nil }
+func (b *payloadBus) WriteToReg(_, _ byte, _ []byte) error  { return nil }
+func (b *payloadBus) Close() error                          { return nil }
+
+func newTestPH(payload []byte, vref float64) (*AliExpressPH, *payloadBus) {
+	bus := &payloadBus{payload: payload}
+	return &AliExpressPH{addr: 0x24, bus: bus, vrefV: vref, decoder: adc24.Default, polarity: polarityNegative}, bus
+}
+
+func TestReadObservedMVRejectsNonFinite(t *testing.T) {
+	cases := []struct {
+		name    string
+		payload []byte
+		vref    float64
+		want    string
+	}{
+		{"mid-scale times inf vref", []byte{0x80, 0x00, 0x00}, math.Inf(1), "non-finite mV"},
+		{"negative full scale times inf vref", []byte{0x00, 0x00, 0x00}, math.Inf(1), "non-finite mV"},
+		{"nan vref", []byte{0x12, 0x34, 0x56}, math.NaN(), "non-finite mV"},
+		{"floating bus", []byte{0xFF, 0xFF, 0xFF}, 2.5, "all 0xFF"},
+		{"short read", []byte{0x80}, 2.5, "short i2c read"},
+	}
+	for _, c := range cases {
+		d, bus := newTestPH(c.payload, c.vref)
+		mv, _, _, err := d.readObservedMV()
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s: expected error containing %q, got mv=%v err=%v", c.name, c.want, mv, err)
+		}
+		if bus.reads != 2 {
+			t.Errorf("%s: expected one retry, got %d reads", c.name, bus.reads)
+		}
+		if !d.lastSampleAt.IsZero() {
+			t.Errorf("%s: bad sample must not be cached", c.name)
+		}
+	}
+
+	d, _ := newTestPH([]byte{0x80, 0x00, 0x00}, 2.5)
+	if mv, _, _, err := d.readObservedMV(); err != nil || mv != 0 {
+		t.Errorf("mid-scale payload: expected 0 mV, got %v %v", mv, err)
+	}
+}
+
+func TestMVToPHRejectsNonFinite(t *testing.T) {
+	d, _ := newTestPH(nil, 2.5)
+	for _, mv := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if _, _, err := d.mvToPH(mv, false); err == nil {
+			t.Errorf("mv=%v: expected error", mv)
+		}
+	}
+
+	d.ph7mV = math.Inf(1)
+	if ph, _, err := d.mvToPH(0, false); err == nil {
+		t.Errorf("inf PH7 anchor: expected error, got pH=%v", ph)
+	}
+
+	d.ph7mV = 0
+	if ph, _, err := d.mvToPH(0, false); err != nil || ph != 7 {
+		t.Errorf("expected pH 7, got %v %v", ph, err)
+	}
+}
+
+func TestSlopeLimitPct(t *testing.T) {
+	d, _ := newTestPH(nil, 2.5)
+	d.ph7mV, d.ph4mV = 0, 150 // fit -50 mV/pH: a tired pH4 buffer
+
+	if s := d.slope25C(false); math.Abs(s+50) > 1e-9 {
+		t.Fatalf("limit off: expected raw fit -50, got %v", s)
+	}
+
+	d.slopeLimitPct = 5
+	lo, _ := d.slopeLimits()
+	if s := d.slope25C(false); math.Abs(s+lo) > 1e-9 {
+		t.Errorf("expected slope clamped to -%v, got %v", lo, s)
+	}
+	if fit := d.fitSlope25C(); fit != -50 {
+		t.Errorf("raw fit must stay available, got %v", fit)
+	}
+
+	d.ph4mV = 177 // -59 mV/pH, inside the band
+	if s := d.slope25C(false); s != -59 {
+		t.Errorf("good calibration must be kept, got %v", s)
+	}
+
+	d.slopeOverride = -45
+	if s := d.slope25C(false); s != -45 {
+		t.Errorf("override must not be clamped, got %v", s)
+	}
+}
+
+func TestComputePH(t *testing.T) {
+	if ph, _ := computePH(-177.48, 0, -59.16, 0, false); math.Abs(ph-10) > 1e-9 {
+		t.Errorf("expected pH 10, got %v", ph)
+	}
+	// At 35C the slope grows by (308.15/298.15), so the same mV reads closer to 7.
+	ph, slope := computePH(-177.48, 0, -59.16, 35, true)
+	if slope >= -59.16 || ph >= 10 || ph <= 7 {
+		t.Errorf("expected Nernst-scaled slope, got pH=%v slope=%v", ph, slope)
+	}
+}
+
+func BenchmarkComputePH(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		computePH(float64(i%400)-200, 3, -57.5, 25.5, true)
+	}
+}
+
+func BenchmarkMVToPH(b *testing.B) {
+	d, _ := newTestPH(nil, 2.5)
+	d.ph7mV, d.ph4mV, d.doTempComp, d.tempC = 3, 175, true, 25.5
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := d.mvToPH(float64(i%400)-200, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestClampOutput(t *testing.T) {
+	d, _ := newTestPH([]byte{0x80, 0x00, 0x00}, 2.5)
+	d.ph7mV = 1000 // reads far above pH 14
+	d.pins = []*phPin{{parent: d}}
+
+	d.clampOutput = true
+	if v, err := d.pins[0].Value(); err != nil || v != 14 {
+		t.Errorf("expected clamped 14, got %v %v", v, err)
+	}
+
+	d.clampOutput = false
+	if v, err := d.pins[0].Value(); err != nil || v <= 14 {
+		t.Errorf("expected unclamped pH above 14, got %v %v", v, err)
+	}
+}
+
+func TestCalibrationRoundTrip(t *testing.T) {
+	src, _ := newTestPH(nil, 2.5)
+	src.ph7mV, src.ph4mV, src.slopeOverride, src.vrefV, src.calTempC = 3, 175, -58.9, 2.47, 24.2
+	blob := src.ExportCalibration()
+
+	dst, _ := newTestPH(nil, 2.5)
+	dst.trimSlope25C = -57
+	if err := dst.ImportCalibration(blob); err != nil {
+		t.Fatal(err)
+	}
+	if dst.ph7mV != 3 || dst.ph4mV != 175 || dst.slopeOverride != -58.9 || dst.vrefV != 2.47 || dst.calTempC != 24.2 {
+		t.Errorf("imported calibration differs: %s", dst.ExportCalibration())
+	}
+	if dst.trimSlope25C != 0 || dst.vrefCalAt.IsZero() {
+		t.Errorf("import should take the blob's (absent) trim slope and mark Vref calibrated")
+	}
+
+	for _, bad := range []string{
+		`{"version":2,"driver":"aliexpress-ph","vref_v":2.5}`,
+		`{"version":1,"driver":"aliexpress-orp","vref_v":2.5}`,
+		`{"version":1,"driver":"aliexpress-ph","vref_v":9}`,
+		`{"version":1,"driver":"aliexpress-ph","vref_v":2.5,"extra":1}`,
+	} {
+		if err := dst.ImportCalibration([]byte(bad)); err == nil {
+			t.Errorf("accepted %s", bad)
+		}
+	}
+}
+
+func TestCalibrationRoundTripKeepsTrim(t *testing.T) {
+	payload := []byte{0x88, 0x00, 0x00}
+	src, _ := newTestPH(payload, 2.5)
+	src.ph7mV, src.ph4mV, src.ph7TrimKeepSlope = 0, 180, true
+	if err := (&phPin{parent: src}).Calibrate([]hal.Measurement{{Expected: 7, Observed: 12}}); err != nil {
+		t.Fatal(err)
+	}
+	if src.trimSlope25C != -60 {
+		t.Fatalf("trim slope %v, want -60", src.trimSlope25C)
+	}
+
+	dst, _ := newTestPH(payload, 2.5)
+	if err := dst.ImportCalibration(src.ExportCalibration()); err != nil {
+		t.Fatal(err)
+	}
+	want, err := (&phPin{parent: src}).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := (&phPin{parent: dst}).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want || dst.trimSlope25C != src.trimSlope25C {
+		t.Errorf("after round trip pH %v (trim %v), want %v (trim %v)", got, dst.trimSlope25C, want, src.trimSlope25C)
+	}
+
+	// Blobs from before trim_slope_25c import with no kept slope.
+	if err := dst.ImportCalibration([]byte(`{"version":1,"driver":"aliexpress-ph","ph7_mv":12,"ph4_mv":180,"vref_v":2.5}`)); err != nil {
+		t.Fatal(err)
+	}
+	if dst.trimSlope25C != 0 {
+		t.Errorf("old blob: trim slope %v, want 0", dst.trimSlope25C)
+	}
+}
+
+func TestObservedMVRefMatchesConversion(t *testing.T) {
+	d, _ := newTestPH(nil, 2.5)
+	d.ph7mV, d.ph4mV = 0, 177.48
+	d.doTempComp, d.tempC = true, 15
+
+	// Anchors taken from observed_mv_ref give the same pH at any temperature.
+	mv := 100.0
+	want, _, err := d.mvToPH(mv, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := d.observedMVRef(mv)
+	if got := 7 + (ref-d.ph7mV)/d.slope25C(false); math.Abs(got-want) > 1e-9 {
+		t.Errorf("pH from observed_mv_ref at 25C slope = %v, conversion gives %v", got, want)
+	}
+
+	d.doTempComp = false
+	if got := d.observedMVRef(mv); got != mv {
+		t.Errorf("without temp comp observedMVRef = %v, want %v", got, mv)
+	}
+}
+
+func TestCalibrateLiveAnchorsAroundNewPH7(t *testing.T) {
+	payload := []byte{0x88, 0x00, 0x00}
+	probe, _ := newTestPH(payload, 2.5)
+	mv, _, _, err := probe.readObservedMV()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A live pH7 reading is the isopotential point: never scaled.
+	d, _ := newTestPH(payload, 2.5)
+	d.ph7mV, d.doTempComp, d.tempC = -40, true, 35
+	if err := (&phPin{parent: d}).Calibrate([]hal.Measurement{{Expected: 7}}); err != nil {
+		t.Fatal(err)
+	}
+	if d.ph7mV != mv {
+		t.Errorf("live PH7 anchor %v, want unscaled %v", d.ph7mV, mv)
+	}
+
+	// pH4 is scaled around the new pH7 whatever the order of the points.
+	want := 12 + (mv-12)*(refTempK25C/(35+273.15))
+	for _, ms := range [][]hal.Measurement{
+		{{Expected: 4}, {Expected: 7, Observed: 12}},
+		{{Expected: 7, Observed: 12}, {Expected: 4}},
+	} {
+		d, _ := newTestPH(payload, 2.5)
+		d.ph7mV, d.doTempComp, d.tempC = -40, true, 35
+		if err := (&phPin{parent: d}).Calibrate(ms); err != nil {
+			t.Fatal(err)
+		}
+		if d.ph7mV != 12 || math.Abs(d.ph4mV-want) > 1e-9 {
+			t.Errorf("%v: PH7=%v PH4=%v, want 12 and %v", ms, d.ph7mV, d.ph4mV, want)
+		}
+	}
+}
+
+func TestWarmupDiscardsReadings(t *testing.T) {
+	d, bus := newTestPH([]byte{0x80, 0x00, 0x00}, 2.5)
+	d.cacheMaxAge = time.Second
+	d.warmup, d.createdAt = time.Hour, time.Now()
+
+	if _, _, _, err := d.readObservedMV(); !errors.Is(err, ErrWarmingUp) {
+		t.Fatalf("expected ErrWarmingUp, got %v", err)
+	}
+	if bus.reads != 1 || !d.lastSampleAt.IsZero() {
+		t.Errorf("warm-up read must reach the module once and not be cached: reads=%d", bus.reads)
+	}
+
+	d.createdAt = time.Now().Add(-2 * time.Hour)
+	if mv, _, _, err := d.readObservedMV(); err != nil || mv != 0 {
+		t.Errorf("after warm-up: mv=%v err=%v", mv, err)
+	}
+}
+
+func TestRoundValue(t *testing.T) {
+	d, _ := newTestPH(nil, 2.5)
+	d.decimals = 2
+	if got := d.roundPH(7.1234); got != 7.1234 {
+		t.Errorf("RoundValue off: got %v, want 7.1234", got)
+	}
+	d.roundValue = true
+	if got := d.roundPH(7.1264); math.Abs(got-7.13) > 1e-12 {
+		t.Errorf("Decimals=2: got %v, want 7.13", got)
+	}
+	d.decimals = 0
+	if got := d.roundPH(6.5); got != 7 {
+		t.Errorf("Decimals=0: got %v, want 7", got)
+	}
+}
+
+func TestAnchorOrder(t *testing.T) {
+	cases := []struct {
+		name           string
+		ph4, ph7, ph10 float64
+		polarity       string
+		want           string
+	}{
+		{"ordered", 177, 0, -177, polarityNegative, ""},
+		{"ph7 only", 0, 5, 0, polarityNegative, ""},
+		{"swapped", -177, 0, 177, polarityNegative, "PH4 and PH10 anchors appear swapped"},
+		{"swapped positive", 177, 0, -177, polarityPositive, "PH4 and PH10 anchors appear swapped"},
+		{"ph7 outside", 177, 200, -177, polarityNegative, "PH7 anchor is not between"},
+		{"ph10 only, wrong side", 0, 0, 177, polarityNegative, "PH10 anchor"},
+	}
+	for _, c := range cases {
+		err := checkAnchorOrder(c.ph4, c.ph7, c.ph10, c.polarity)
+		if c.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", c.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrAnchorOrder) || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s: got %v, want ErrAnchorOrder containing %q", c.name, err, c.want)
+		}
+	}
+
+	// Swapped buffers in Calibrate are refused and the old anchors stay.
+	d, _ := newTestPH([]byte{0x80, 0x00, 0x00}, 2.5)
+	d.ph4mV, d.ph10mV = 170, -170
+	p := &phPin{parent: d}
+	err := p.Calibrate([]hal.Measurement{{Expected: 4, Observed: -175}, {Expected: 10, Observed: 175}})
+	if !errors.Is(err, ErrAnchorOrder) || d.ph4mV != 170 || d.ph10mV != -170 {
+		t.Errorf("swapped Calibrate: err=%v PH4=%v PH10=%v", err, d.ph4mV, d.ph10mV)
+	}
+}
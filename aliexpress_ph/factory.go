@@ -3,14 +3,18 @@ package aliexpress_ph
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/reef-pi/drivers/i2creg"
+	"github.com/reef-pi/drivers/internal/adc24"
+	"github.com/reef-pi/drivers/internal/timing"
+	"github.com/reef-pi/drivers/nobus"
 	"github.com/reef-pi/hal"
-	"github.com/reef-pi/rpi/i2c"
 )
 
 type factory struct {
@@ -29,6 +33,54 @@ const (
 	refTempCParam      = "RefTempC"     // reference for temp comp (25)
 	doTempCompParam    = "DoTempComp"   // disabled by default
 	debugParam         = "Debug"
+
+	// PH7TrimKeepSlope: a pH7-only calibration re-zeros the offset and keeps the existing slope.
+	ph7TrimKeepSlopeParam = "PH7TrimKeepSlope"
+
+	// Polarity: "negative" (usual, higher pH => lower mV) or "positive" (reversed wiring)
+	polarityParam = "Polarity"
+
+	// Advanced I2C timing (ms), bounded; defaults match the tuned constants in driver.go
+	minI2CGapMsParam       = timing.MinI2CGapMs
+	cacheMaxAgeMsParam     = timing.CacheMaxAgeMs
+	settleAfterReadMsParam = timing.SettleAfterReadMs
+	retryDelayMsParam      = timing.RetryDelayMs
+
+	// Optional conversion trigger for modules that are not free-running:
+	// ReadCommand bytes (hex, e.g. "0x01" or "01 A0") are written before each read,
+	// then ConversionDelayMs elapses before the 3 bytes are read. Empty = read only.
+	readCommandParam       = "ReadCommand"
+	conversionDelayMsParam = "ConversionDelayMs"
+
+	// Optional plausible band for the decoded ADC code (0..0x3FFFFFFF, mid-scale
+	// 0x20000000 = 0 mV). Out-of-band codes get a Snapshot warning. CodeMax 0 = none.
+	codeMinParam = "CodeMin"
+	codeMaxParam = "CodeMax"
+
+	// Clamp an anchor-fitted slope to within ±pct of the ideal 59.16 mV/pH
+	// (e.g. 5 => 56.2..62.1). 0 = off. Override slopes are never clamped.
+	slopeLimitPctParam = "SlopeLimitPct"
+	maxSlopeLimitPct   = 50.0
+
+	// Clamp Value() to 0..14 pH (default). Off shows the computed pH as-is for
+	// bench checks of slope/anchor math; NaN/Inf are still rejected.
+	clampOutputParam = "ClampOutput"
+
+	// Reply decoding: ModuleVariant "default" (ADC.cpp), "raw24" or "custom";
+	// DecodeShift / DecodeMask only apply to "custom" (see internal/adc24)
+	moduleVariantParam = "ModuleVariant"
+	decodeShiftParam   = "DecodeShift"
+	decodeMaskParam    = "DecodeMask"
+
+	// JSON from ExportCalibration, applied after construction (see calblob.go)
+	calibrationBlobParam = "CalibrationBlob"
+
+	// Discard readings for this long after start-up (ms, 0 = off, see warmup.go)
+	warmupMsParam = "WarmupMs"
+
+	// Displayed pH decimals (0..4); RoundValue also rounds Value() to them (see rounding.go)
+	decimalsParam   = "Decimals"
+	roundValueParam = "RoundValue"
 )
 
 var f *factory
@@ -59,6 +111,35 @@ func Factory() hal.DriverFactory {
 				{Name: doTempCompParam, Type: hal.Boolean, Order: 7, Default: false},
 
 				{Name: debugParam, Type: hal.Boolean, Order: 8, Default: false},
+
+				{Name: ph7TrimKeepSlopeParam, Type: hal.Boolean, Order: 9, Default: false},
+
+				{Name: minI2CGapMsParam, Type: hal.Integer, Order: 10, Default: int(defaultMinI2CGap / time.Millisecond)},
+				{Name: cacheMaxAgeMsParam, Type: hal.Integer, Order: 11, Default: int(defaultCacheMaxAge / time.Millisecond)},
+				{Name: settleAfterReadMsParam, Type: hal.Integer, Order: 12, Default: int(defaultSettleAfterRead / time.Millisecond)},
+				{Name: retryDelayMsParam, Type: hal.Integer, Order: 13, Default: int(defaultRetryDelay / time.Millisecond)},
+
+				{Name: polarityParam, Type: hal.String, Order: 14, Default: polarityNegative},
+
+				// Advanced: conversion-start command for non free-running ADC modules
+				{Name: readCommandParam, Type: hal.String, Order: 15, Default: ""},
+				{Name: conversionDelayMsParam, Type: hal.Integer, Order: 16, Default: 0},
+
+				// Advanced: plausible ADC code band (saturation / wiring check)
+				{Name: codeMinParam, Type: hal.Integer, Order: 17, Default: 0},
+				{Name: codeMaxParam, Type: hal.Integer, Order: 18, Default: 0},
+
+				{Name: slopeLimitPctParam, Type: hal.Decimal, Order: 19, Default: 0.0},
+				{Name: clampOutputParam, Type: hal.Boolean, Order: 20, Default: true},
+
+				{Name: moduleVariantParam, Type: hal.String, Order: 21, Default: adc24.VariantDefault},
+				{Name: decodeShiftParam, Type: hal.Integer, Order: 22, Default: int(adc24.Default.Shift)},
+				{Name: decodeMaskParam, Type: hal.String, Order: 23, Default: adc24.DefaultMask},
+
+				{Name: calibrationBlobParam, Type: hal.String, Order: 24, Default: ""},
+				{Name: warmupMsParam, Type: hal.Integer, Order: 25, Default: 0},
+				{Name: decimalsParam, Type: hal.Integer, Order: 26, Default: defaultDecimals},
+				{Name: roundValueParam, Type: hal.Boolean, Order: 27, Default: false},
 			},
 		}
 	})
@@ -75,11 +156,8 @@ func (f *factory) ValidateParameters(parameters map[string]interface{}) (bool, m
 	if !ok {
 		failures[addressParam] = append(failures[addressParam], "Address parameter is required")
 	} else {
-		addr, ok := toInt(addrRaw)
-		if !ok {
-			failures[addressParam] = append(failures[addressParam], "Address must be an integer (0..127)")
-		} else if addr < 0 || addr > 127 {
-			failures[addressParam] = append(failures[addressParam], "Address must be 0..127 (7-bit)")
+		if _, err := i2creg.ParseI2CAddress(addrRaw); err != nil {
+			failures[addressParam] = append(failures[addressParam], err.Error())
 		}
 	}
 
@@ -92,6 +170,62 @@ func (f *factory) ValidateParameters(parameters map[string]interface{}) (bool, m
 	// but having PH7 anchor configured is strongly recommended.
 	_ = getFloatAny(parameters, 0, ph7mVParam, "ph7_mv")
 
+	timing.Validate(parameters, failures, timingBounds...)
+
+	if _, err := parseHexBytes(getStringAny(parameters, "", readCommandParam, "readcommand")); err != nil {
+		failures[readCommandParam] = append(failures[readCommandParam], err.Error())
+	}
+
+	if pct := getFloatAny(parameters, 0, slopeLimitPctParam, "slopelimitpct"); pct < 0 || pct > maxSlopeLimitPct {
+		failures[slopeLimitPctParam] = append(failures[slopeLimitPctParam], fmt.Sprintf("SlopeLimitPct must be 0..%g (0 = off)", maxSlopeLimitPct))
+	}
+
+	decoder, err := decoderFromParams(parameters)
+	if err != nil {
+		failures[moduleVariantParam] = append(failures[moduleVariantParam], err.Error())
+		decoder = adc24.Default
+	}
+
+	codeFull := int(decoder.Full())
+	codeMin := getIntAny(parameters, 0, codeMinParam, "codemin")
+	codeMax := getIntAny(parameters, 0, codeMaxParam, "codemax")
+	if codeMin < 0 || codeMin > codeFull {
+		failures[codeMinParam] = append(failures[codeMinParam], fmt.Sprintf("CodeMin must be 0..%d", codeFull))
+	}
+	if codeMax < 0 || codeMax > codeFull {
+		failures[codeMaxParam] = append(failures[codeMaxParam], fmt.Sprintf("CodeMax must be 0..%d (0 = no upper bound)", codeFull))
+	} else if codeMax != 0 && codeMax <= codeMin {
+		failures[codeMaxParam] = append(failures[codeMaxParam], "CodeMax must be greater than CodeMin")
+	}
+
+	polarity := getStringAny(parameters, polarityNegative, polarityParam, "polarity")
+	switch polarity {
+	case polarityNegative, polarityPositive:
+		ph4 := getFloatAny(parameters, 0, ph4mVParam, "ph4_mv")
+		ph10 := getFloatAny(parameters, 0, ph10mVParam, "ph10_mv")
+		if err := checkAnchorOrder(ph4, getFloatAny(parameters, 0, ph7mVParam, "ph7_mv"), ph10, polarity); err != nil {
+			key := ph4mVParam
+			if ph4 == 0 {
+				key = ph10mVParam
+			}
+			failures[key] = append(failures[key], err.Error())
+		}
+	default:
+		failures[polarityParam] = append(failures[polarityParam], "Polarity must be \"negative\" or \"positive\"")
+	}
+
+	if n := getIntAny(parameters, defaultDecimals, decimalsParam, "decimals"); n < 0 || n > maxDecimals {
+		failures[decimalsParam] = append(failures[decimalsParam], fmt.Sprintf("Decimals must be 0..%d", maxDecimals))
+	}
+
+	if s := calBlobParam(parameters); s != "" {
+		if c, err := parseCalBlob([]byte(s)); err != nil {
+			failures[calibrationBlobParam] = append(failures[calibrationBlobParam], err.Error())
+		} else if err := checkAnchorOrder(c.PH4mV, c.PH7mV, c.PH10mV, polarity); err != nil {
+			failures[calibrationBlobParam] = append(failures[calibrationBlobParam], err.Error())
+		}
+	}
+
 	return len(failures) == 0, failures
 }
 
@@ -109,7 +243,10 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
 		}
 	}
 
-	addrInt := getIntAny(parameters, 36, addressParam, "address")
+	addr := byte(36)
+	if v, ok := getAny(parameters, addressParam, "address"); ok {
+		addr, _ = i2creg.ParseI2CAddress(v)
+	}
 	vref := getFloatAny(parameters, 2.5, vrefParam, "vref")
 
 	ph7 := getFloatAny(parameters, 0.0, ph7mVParam, "ph7_mv")
@@ -119,15 +256,33 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
 	slopeOverride := getFloatAny(parameters, 0.0, slopeOverrideParam, "slope")
 	refTempC := getFloatAny(parameters, 25.0, refTempCParam, "reftempc")
 	doTempComp := getBoolAny(parameters, false, doTempCompParam, "dotempcomp", "dotc")
+	ph7TrimKeepSlope := getBoolAny(parameters, false, ph7TrimKeepSlopeParam, "ph7trimkeepslope")
+
+	bus, err := nobus.I2C("aliexpress_ph", hardwareResources)
+	if err != nil {
+		return nil, err
+	}
 
 	d := &AliExpressPH{
-		addr:          byte(addrInt),
-		bus:           hardwareResources.(i2c.Bus),
+		addr:          addr,
+		bus:           bus,
 		vrefV:         vref,
 		ph7mV:         ph7,
 		ph4mV:         ph4,
 		ph10mV:        ph10,
 		slopeOverride: slopeOverride,
+
+		ph7TrimKeepSlope: ph7TrimKeepSlope,
+		polarity:         getStringAny(parameters, polarityNegative, polarityParam, "polarity"),
+
+		minI2CGap:       timing.Ms(parameters, defaultMinI2CGap, minI2CGapMsParam, "mini2cgapms"),
+		cacheMaxAge:     timing.Ms(parameters, defaultCacheMaxAge, cacheMaxAgeMsParam, "cachemaxagems"),
+		settleAfterRead: timing.Ms(parameters, defaultSettleAfterRead, settleAfterReadMsParam, "settleafterreadms"),
+		retryDelay:      timing.Ms(parameters, defaultRetryDelay, retryDelayMsParam, "retrydelayms"),
+		conversionDelay: timing.Ms(parameters, 0, conversionDelayMsParam, "conversiondelayms"),
+		warmup:          timing.Ms(parameters, 0, warmupMsParam, "warmupms"),
+		createdAt:       time.Now(),
+
 		refTempC:      refTempC,
 		doTempComp:    doTempComp,
 		tempC:         refTempC, // initialize temp to ref until injected
@@ -139,21 +294,87 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
 		},
 	}
 
+	d.readCmd, _ = parseHexBytes(getStringAny(parameters, "", readCommandParam, "readcommand"))
+	d.decoder, _ = decoderFromParams(parameters) // validated above
+	d.codeMin = int32(getIntAny(parameters, 0, codeMinParam, "codemin"))
+	d.codeMax = int32(getIntAny(parameters, 0, codeMaxParam, "codemax"))
+	d.slopeLimitPct = getFloatAny(parameters, 0, slopeLimitPctParam, "slopelimitpct")
+	d.clampOutput = getBoolAny(parameters, true, clampOutputParam, "clampoutput")
+	d.decimals = getIntAny(parameters, defaultDecimals, decimalsParam, "decimals")
+	d.roundValue = getBoolAny(parameters, false, roundValueParam, "roundvalue")
+	d.calTempC = refTempC
+	if s := calBlobParam(parameters); s != "" {
+		if err := d.ImportCalibration([]byte(s)); err != nil {
+			return nil, err
+		}
+		log.Printf("aliexpress_ph addr=0x%02X calibration imported from CalibrationBlob: PH7=%.2f PH4=%.2f PH10=%.2f slope=%.4f vref=%.4f",
+			d.addr, d.ph7mV, d.ph4mV, d.ph10mV, d.slopeOverride, d.vrefV)
+	}
+	if fit, s := d.fitSlope25C(), d.slope25C(false); fit != 0 && fit != s {
+		log.Printf("aliexpress_ph addr=0x%02X WARNING: anchor slope %.4f mV/pH is outside SlopeLimitPct=%.1f%%; using %.4f",
+			d.addr, fit, d.slopeLimitPct, s)
+	}
+
 	d.pins = []*phPin{{parent: d, ch: 0}}
 
 	if debug {
 		log.Printf("aliexpress_ph init addr=%d (0x%02X) vref=%.3f PH7=%.2f PH4=%.2f PH10=%.2f slope_override=%.4f DoTC=%v RefTempC=%.2f tempC(init)=%.2f",
-			addrInt, addrInt, vref, ph7, ph4, ph10, slopeOverride, doTempComp, refTempC, d.tempC)
+			addr, addr, vref, ph7, ph4, ph10, slopeOverride, doTempComp, refTempC, d.tempC)
+		log.Printf("aliexpress_ph timing addr=0x%02X gap=%v cache=%v settle=%v retry=%v read_cmd=% X conv_delay=%v",
+			addr, d.minI2CGap, d.cacheMaxAge, d.settleAfterRead, d.retryDelay, d.readCmd, d.conversionDelay)
+		log.Printf("aliexpress_ph decode addr=0x%02X variant=%s shift=%d mask=%s",
+			addr, d.decoder.Variant, d.decoder.Shift, d.decoder.MaskString())
 	}
 
-	// Small delay is not required for this module (pure read), but keep time import used in this file.
-	_ = time.Millisecond
+	// pH and ORP modules may share an address; the per-address lock serializes them.
+	// Config-only drivers (nil bus) hold no address.
+	if !nobus.Is(bus) {
+		claim, err := i2creg.Claim(d.bus, d.addr, driverType, "aliexpress-orp")
+		if err != nil {
+			return nil, err
+		}
+		d.claim = claim
+	}
 
 	return d, nil
 }
 
 // ----------------- helpers (same style as your robotank factory) -----------------
 
+// timingBounds are the accepted ranges (ms) for the advanced timing
+// parameters: the shared I2C pacing knobs plus ConversionDelayMs and WarmupMs.
+var timingBounds = append([]timing.Bound{
+	{Name: conversionDelayMsParam, Min: 0, Max: 1000},
+	{Name: warmupMsParam, Min: 0, Max: maxWarmupMs},
+}, timing.I2CBounds...)
+
+// parseHexBytes parses a ReadCommand like "0x01", "01 A0" or "0x01,0xA0".
+// An empty string means no command.
+func parseHexBytes(s string) ([]byte, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' || r == ':' })
+	if len(fields) > 8 {
+		return nil, fmt.Errorf("ReadCommand is limited to 8 bytes, got %d", len(fields))
+	}
+	var out []byte
+	for _, fld := range fields {
+		fld = strings.TrimPrefix(strings.TrimPrefix(fld, "0x"), "0X")
+		b, err := strconv.ParseUint(fld, 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("ReadCommand must be hex bytes like 0x01 or \"01 A0\": bad byte %q", fld)
+		}
+		out = append(out, byte(b))
+	}
+	return out, nil
+}
+
+// decoderFromParams builds the reply decoder from ModuleVariant, DecodeShift and DecodeMask.
+func decoderFromParams(parameters map[string]interface{}) (adc24.Decoder, error) {
+	return adc24.New(
+		getStringAny(parameters, adc24.VariantDefault, moduleVariantParam, "modulevariant"),
+		getIntAny(parameters, int(adc24.Default.Shift), decodeShiftParam, "decodeshift"),
+		getStringAny(parameters, adc24.DefaultMask, decodeMaskParam, "decodemask"))
+}
+
 func getAny(m map[string]interface{}, keys ...string) (interface{}, bool) {
 	for _, k := range keys {
 		if v, ok := m[k]; ok {
@@ -199,6 +420,23 @@ func getBoolAny(m map[string]interface{}, def bool, keys ...string) bool {
 	return def
 }
 
+// getStringAny returns a trimmed, lower-cased string value (empty counts as missing).
+func getStringAny(m map[string]interface{}, def string, keys ...string) string {
+	v, ok := getAny(m, keys...)
+	if !ok {
+		return def
+	}
+	s, ok := v.(string)
+	if !ok {
+		return def
+	}
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return def
+	}
+	return s
+}
+
 func toInt(v interface{}) (int, bool) {
 	v = unwrapValue(v)
 	switch t := v.(type) {
@@ -0,0 +1,27 @@
+// rounding.go
+//
+// Presentation precision of the reported pH.
+//
+// Decimals sets how many decimals the UI shows for the primary value
+// (signal_decimals "value"). RoundValue additionally rounds Value() and the
+// Snapshot value to that many decimals, so a controller or graph never sees
+// the third-decimal jitter. observed_mv and the other signals used for
+// calibration are never rounded.
+//
+package aliexpress_ph
+
+import "math"
+
+const (
+	defaultDecimals = 3
+	maxDecimals     = 4
+)
+
+// roundPH rounds ph to d.decimals when RoundValue is on.
+func (d *AliExpressPH) roundPH(ph float64) float64 {
+	if !d.roundValue {
+		return ph
+	}
+	scale := math.Pow(10, float64(d.decimals))
+	return math.Round(ph*scale) / scale
+}
@@ -0,0 +1,44 @@
+// warmup.go
+//
+// WarmupMs: discard readings right after start-up.
+//
+// Some modules return garbage for the first reads after power-up or first
+// access. During the WarmupMs window after construction every read still goes
+// to the module (so it gets its first accesses), but the result is dropped:
+// nothing is cached, and Value, Snapshot and Calibrate's live read fail with
+// ErrWarmingUp so the host retries on its next poll.
+//
+package aliexpress_ph
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxWarmupMs bounds WarmupMs.
+const maxWarmupMs = 60000
+
+// ErrWarmingUp is returned (wrapped) while readings are discarded after start-up.
+var ErrWarmingUp = errors.New("aliexpress_ph: warming up")
+
+// warmupRemaining returns how much of the WarmupMs window is left.
+func (d *AliExpressPH) warmupRemaining() time.Duration {
+	if d.warmup <= 0 {
+		return 0
+	}
+	if left := d.warmup - time.Since(d.createdAt); left > 0 {
+		return left
+	}
+	return 0
+}
+
+// warmupError returns ErrWarmingUp while the window is open, else nil.
+func (d *AliExpressPH) warmupError(mv float64) error {
+	left := d.warmupRemaining()
+	if left == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: discarded %.2f mV, readings start in %v (WarmupMs=%d)",
+		ErrWarmingUp, mv, left.Round(time.Millisecond), d.warmup.Milliseconds())
+}
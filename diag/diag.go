@@ -0,0 +1,83 @@
+// diag.go
+//
+// Uniform health reports for drivers.
+//
+// Each I2C sensor and expander driver in this module (pcf8575, ads1x15,
+// ads1115tds, ezo, sht3x, ph_board, orp_board, aliexpress_ph, aliexpress_orp,
+// robotank_ph and robotank_conductivity) answers "is the hardware healthy?"
+// through Diagnose() Diagnostics. Diagnose runs one safe liveness check (a
+// read that changes nothing on the device: a port read, a config-register
+// read, a status or firmware query) and folds in what the driver already
+// tracks: error counters, stale inputs, calibration warnings. Hosts
+// type-assert a driver to Diagnoser to show it on one dashboard without
+// knowing its snapshot notes; drivers without it report no health.
+//
+// Messages are classified by prefix: "ERROR: " for problems that make OK
+// false, "WARNING: " for conditions worth a look, and plain text for
+// information.
+//
+package diag
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/reef-pi/drivers/nobus"
+)
+
+// Message class prefixes.
+const (
+	ErrorPrefix   = "ERROR: "
+	WarningPrefix = "WARNING: "
+)
+
+// Diagnostics is the health report returned by Diagnose.
+type Diagnostics struct {
+	OK        bool              `json:"ok"`
+	Messages  []string          `json:"messages"`
+	LastError string            `json:"last_error,omitempty"`
+	Counters  map[string]uint64 `json:"counters,omitempty"`
+}
+
+// Diagnoser is implemented by drivers that report their health.
+type Diagnoser interface {
+	Diagnose() Diagnostics
+}
+
+// New returns a healthy report with no messages.
+func New() Diagnostics {
+	return Diagnostics{OK: true, Counters: map[string]uint64{}}
+}
+
+// Errorf records a problem and marks the report not OK.
+func (d *Diagnostics) Errorf(format string, args ...any) {
+	d.OK = false
+	d.Messages = append(d.Messages, ErrorPrefix+fmt.Sprintf(format, args...))
+}
+
+// Warnf records a condition worth checking; OK is unchanged.
+func (d *Diagnostics) Warnf(format string, args ...any) {
+	d.Messages = append(d.Messages, WarningPrefix+fmt.Sprintf(format, args...))
+}
+
+// Infof records an informational message.
+func (d *Diagnostics) Infof(format string, args ...any) {
+	d.Messages = append(d.Messages, fmt.Sprintf(format, args...))
+}
+
+// Check records the result of the liveness check what. A failure sets
+// LastError and marks the report not OK; a config-only driver (nobus) is
+// reported as such. Check returns whether err is nil.
+func (d *Diagnostics) Check(what string, err error) bool {
+	if err == nil {
+		d.Infof("%s: ok", what)
+		return true
+	}
+	d.LastError = err.Error()
+	if errors.Is(err, nobus.ErrNoBus) {
+		d.Errorf("%s: driver is config-only (no I2C bus)", what)
+		return false
+	}
+	d.Errorf("%s: %v", what, err)
+	return false
+}
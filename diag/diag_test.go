@@ -0,0 +1,34 @@
+package diag
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/reef-pi/drivers/nobus"
+)
+
+func TestCheckClassifiesMessages(t *testing.T) {
+	d := New()
+	if !d.Check("read", nil) || !d.OK {
+		t.Fatal("a passing check must keep OK")
+	}
+	d.Warnf("probe %s", "stale")
+	if !d.OK || d.Messages[1] != WarningPrefix+"probe stale" {
+		t.Errorf("warning: ok=%v messages=%q", d.OK, d.Messages)
+	}
+
+	if d.Check("read", fmt.Errorf("read port: %w", errors.New("i2c nack"))) || d.OK {
+		t.Error("a failing check must clear OK")
+	}
+	if d.LastError != "read port: i2c nack" || !strings.HasPrefix(d.Messages[2], ErrorPrefix) {
+		t.Errorf("last_error=%q messages=%q", d.LastError, d.Messages)
+	}
+
+	d = New()
+	d.Check("read", nobus.ErrNoBus)
+	if d.OK || !strings.Contains(d.Messages[0], "config-only") {
+		t.Errorf("nobus: ok=%v messages=%q", d.OK, d.Messages)
+	}
+}
@@ -0,0 +1,42 @@
+// diagnose.go
+//
+// Health report (see package diag).
+//
+// The liveness check is the "Status" query, which changes nothing on the
+// circuit and answers the reason for its last restart and its supply voltage.
+//
+package ezo
+
+import (
+	"github.com/reef-pi/drivers/diag"
+)
+
+var _ diag.Diagnoser = (*AtlasEZO)(nil)
+
+// restartReasons maps the Status restart code to a description.
+var restartReasons = map[string]string{
+	"P": "powered off",
+	"S": "software reset",
+	"B": "brown out",
+	"W": "watchdog",
+	"U": "unknown",
+}
+
+// Diagnose queries the circuit status.
+func (a *AtlasEZO) Diagnose() diag.Diagnostics {
+	r := diag.New()
+
+	reason, vcc, err := a.Status()
+	if !r.Check("status", err) {
+		return r
+	}
+	desc, ok := restartReasons[reason]
+	if !ok {
+		desc = "unrecognized code " + reason
+	}
+	r.Infof("last restart: %s, VCC=%sV", desc, vcc)
+	if reason == "B" || reason == "W" {
+		r.Warnf("circuit restarted after a %s; check the supply", desc)
+	}
+	return r
+}
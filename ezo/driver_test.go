@@ -1,8 +1,10 @@
 package ezo
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/reef-pi/drivers/diag"
 	"github.com/reef-pi/hal"
 
 	"github.com/reef-pi/rpi/i2c"
@@ -11,7 +13,7 @@ import (
 func TestEZO(t *testing.T) {
 	factory := Factory()
 	params := map[string]interface{}{
-		"Address": 0x93,
+		"Address": 0x63,
 	}
 
 	bus := i2c.MockBus()
@@ -83,7 +85,7 @@ func TestEZOHalAdapter(t *testing.T) {
 		t.Error("EZO Driver creation should fail when configuration is invalid")
 	}
 
-	params["Address"] = 0x93
+	params["Address"] = 0x63
 
 	e, err := factory.NewDriver(params, bus)
 	if err != nil {
@@ -117,3 +119,32 @@ func TestEZOHalAdapter(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestEZODiagnose(t *testing.T) {
+	bus := i2c.MockBus()
+	driver, err := Factory().NewDriver(map[string]interface{}{"Address": 0x63}, bus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := driver.(*AtlasEZO)
+	e.delay = 0
+
+	bus.Bytes = append([]byte{1}, []byte("?Status,B,4.12")...)
+	r := e.Diagnose()
+	if !r.OK || len(r.Messages) != 3 || !strings.HasPrefix(r.Messages[2], diag.WarningPrefix) {
+		t.Errorf("brown out: %+v", r)
+	}
+
+	bus.Bytes = append([]byte{2}, []byte("?Status,P,5.03")...)
+	if r := e.Diagnose(); r.OK || r.LastError == "" {
+		t.Errorf("failed status should not be OK: %+v", r)
+	}
+
+	cfg, err := Factory().NewDriver(map[string]interface{}{"Address": 0x63}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := cfg.(*AtlasEZO).Diagnose(); r.OK {
+		t.Errorf("config-only driver should not be OK: %+v", r)
+	}
+}
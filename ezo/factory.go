@@ -6,8 +6,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/reef-pi/drivers/i2creg"
+	"github.com/reef-pi/drivers/nobus"
 	"github.com/reef-pi/hal"
-	"github.com/reef-pi/rpi/i2c"
 )
 
 type factory struct {
@@ -58,14 +59,8 @@ func (f *factory) ValidateParameters(parameters map[string]interface{}) (bool, m
 	var failures = make(map[string][]string)
 
 	if address, ok := parameters[addressParam]; ok {
-		val, ok := hal.ConvertToInt(address)
-		if !ok {
-			failure := fmt.Sprint(addressParam, " is not an integer. ", address, " was received.")
-			failures[addressParam] = append(failures[addressParam], failure)
-		}
-		if val < 0 || val > 255 {
-			failure := fmt.Sprint(addressParam, " is out of range. It should be between 0 and 255, but ", address, " was received.")
-			failures[addressParam] = append(failures[addressParam], failure)
+		if _, err := i2creg.ParseI2CAddress(address); err != nil {
+			failures[addressParam] = append(failures[addressParam], err.Error())
 		}
 	} else {
 		failure := fmt.Sprint(addressParam, " is not a required parameter, but was not found.")
@@ -80,11 +75,15 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
 		return nil, errors.New(hal.ToErrorString(failures))
 	}
 
-	address, _ := hal.ConvertToInt(parameters[addressParam])
+	address, _ := i2creg.ParseI2CAddress(parameters[addressParam])
+	bus, err := nobus.I2C("ezo", hardwareResources)
+	if err != nil {
+		return nil, err
+	}
 
 	driver := &AtlasEZO{
-		addr:  byte(address),
-		bus:   hardwareResources.(i2c.Bus),
+		addr:  address,
+		bus:   bus,
 		delay: time.Second,
 		meta: hal.Metadata{
 			Name:         _ezoName,
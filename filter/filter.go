@@ -0,0 +1,290 @@
+// filter.go
+//
+// Reusable smoothing filters for driver measurements.
+//
+// Every filter implements Filter: Add a raw sample, get the smoothed value back.
+// Drivers hold one Filter per smoothed signal and pick it from a single config
+// string via Parse (e.g. "median:5", "ema:0.2", "window:30s").
+//
+// Filters are not safe for concurrent use; drivers already serialize reads and
+// should call Add under the same lock.
+//
+package filter
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter smooths a stream of samples.
+type Filter interface {
+	// Add feeds a sample and returns the current filtered value.
+	Add(v float64) float64
+	// Reset drops all history (e.g. after a calibration).
+	Reset()
+}
+
+// None returns a pass-through filter.
+func None() Filter { return none{} }
+
+type none struct{}
+
+func (none) Add(v float64) float64 { return v }
+func (none) Reset()                {}
+
+// ring holds the last n samples.
+type ring struct {
+	buf  []float64
+	next int
+	n    int
+}
+
+func (r *ring) add(v float64) {
+	if len(r.buf) < r.n {
+		r.buf = append(r.buf, v)
+		return
+	}
+	r.buf[r.next] = v
+	r.next = (r.next + 1) % r.n
+}
+
+func (r *ring) reset() {
+	r.buf = r.buf[:0]
+	r.next = 0
+}
+
+// Median returns a filter reporting the median of the last n samples.
+// Good at rejecting single-sample spikes. n < 1 is treated as 1.
+func Median(n int) Filter {
+	if n < 1 {
+		n = 1
+	}
+	return &median{r: ring{n: n}}
+}
+
+type median struct {
+	r   ring
+	tmp []float64
+}
+
+func (m *median) Add(v float64) float64 {
+	m.r.add(v)
+	m.tmp = append(m.tmp[:0], m.r.buf...)
+	sort.Float64s(m.tmp)
+	k := len(m.tmp)
+	if k%2 == 1 {
+		return m.tmp[k/2]
+	}
+	return (m.tmp[k/2-1] + m.tmp[k/2]) / 2
+}
+
+func (m *median) Reset() { m.r.reset() }
+
+// Mean returns a filter reporting the mean of the last n samples.
+// n < 1 is treated as 1.
+func Mean(n int) Filter {
+	if n < 1 {
+		n = 1
+	}
+	return &mean{r: ring{n: n}}
+}
+
+type mean struct {
+	r ring
+}
+
+func (m *mean) Add(v float64) float64 {
+	m.r.add(v)
+	sum := 0.0
+	for _, x := range m.r.buf {
+		sum += x
+	}
+	return sum / float64(len(m.r.buf))
+}
+
+func (m *mean) Reset() { m.r.reset() }
+
+// EMA returns an exponential moving average with weight alpha (0 < alpha <= 1)
+// on the newest sample. The first sample seeds the average. alpha outside
+// (0, 1] is treated as 1 (no smoothing).
+func EMA(alpha float64) Filter {
+	if alpha <= 0 || alpha > 1 {
+		alpha = 1
+	}
+	return &ema{alpha: alpha}
+}
+
+type ema struct {
+	alpha  float64
+	value  float64
+	primed bool
+}
+
+func (e *ema) Add(v float64) float64 {
+	if !e.primed {
+		e.value, e.primed = v, true
+		return v
+	}
+	e.value += e.alpha * (v - e.value)
+	return e.value
+}
+
+func (e *ema) Reset() { e.primed = false }
+
+// Snapper is implemented by filters that can jump straight to a new sample
+// (see SnapOnJump).
+type Snapper interface {
+	// Snapped reports whether the last Add snapped to its sample.
+	Snapped() bool
+}
+
+// SnapOnJump wraps f so that a sample further than threshold from the current
+// filtered value resets f before it is added: the output snaps to the new
+// level instead of slowly tracking it. Small changes are smoothed as before,
+// so an EMA stays steady while quiet and still follows a real step (e.g. a
+// dose) at once. threshold <= 0 returns f unchanged.
+func SnapOnJump(f Filter, threshold float64) Filter {
+	if threshold <= 0 {
+		return f
+	}
+	return &snapOnJump{f: f, threshold: threshold}
+}
+
+type snapOnJump struct {
+	f         Filter
+	threshold float64
+	value     float64
+	primed    bool
+	snapped   bool
+}
+
+func (s *snapOnJump) Add(v float64) float64 {
+	s.snapped = s.primed && math.Abs(v-s.value) > s.threshold
+	if s.snapped {
+		s.f.Reset()
+	}
+	s.value, s.primed = s.f.Add(v), true
+	return s.value
+}
+
+func (s *snapOnJump) Reset() {
+	s.f.Reset()
+	s.primed, s.snapped = false, false
+}
+
+func (s *snapOnJump) Snapped() bool { return s.snapped }
+
+// TimeWindow returns a filter reporting the mean of samples added within the
+// last d. Unlike Mean, the smoothing span does not depend on the poll rate.
+func TimeWindow(d time.Duration) Filter {
+	return &timeWindow{d: d, now: time.Now}
+}
+
+type timedSample struct {
+	v  float64
+	at time.Time
+}
+
+type timeWindow struct {
+	d       time.Duration
+	now     func() time.Time
+	samples []timedSample
+}
+
+func (w *timeWindow) Add(v float64) float64 {
+	now := w.now()
+	w.samples = append(w.samples, timedSample{v: v, at: now})
+
+	// Drop samples older than the window (always keep the newest).
+	cut := 0
+	for cut < len(w.samples)-1 && now.Sub(w.samples[cut].at) > w.d {
+		cut++
+	}
+	w.samples = append(w.samples[:0], w.samples[cut:]...)
+
+	sum := 0.0
+	for _, s := range w.samples {
+		sum += s.v
+	}
+	return sum / float64(len(w.samples))
+}
+
+func (w *timeWindow) Reset() { w.samples = w.samples[:0] }
+
+// Chain returns a filter feeding each sample through fs in order, e.g.
+// Chain(Median(5), Mean(5)) drops spikes before averaging. With no filters
+// it passes samples through.
+func Chain(fs ...Filter) Filter {
+	if len(fs) == 0 {
+		return None()
+	}
+	if len(fs) == 1 {
+		return fs[0]
+	}
+	return chain(fs)
+}
+
+type chain []Filter
+
+func (c chain) Add(v float64) float64 {
+	for _, f := range c {
+		v = f.Add(v)
+	}
+	return v
+}
+
+func (c chain) Reset() {
+	for _, f := range c {
+		f.Reset()
+	}
+}
+
+// Parse builds a filter from a config string:
+//
+//	"" or "none"    pass-through
+//	"median:N"      median of last N samples
+//	"mean:N"        mean of last N samples
+//	"ema:ALPHA"     exponential moving average, 0 < ALPHA <= 1
+//	"window:DUR"    mean over a time window, DUR like "30s" or "2m"
+func Parse(spec string) (Filter, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	if spec == "" || spec == "none" {
+		return None(), nil
+	}
+
+	kind, arg, ok := strings.Cut(spec, ":")
+	if !ok {
+		return nil, fmt.Errorf("filter %q: expected kind:arg (e.g. median:5)", spec)
+	}
+	arg = strings.TrimSpace(arg)
+
+	switch strings.TrimSpace(kind) {
+	case "median", "mean":
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 || n > 1000 {
+			return nil, fmt.Errorf("filter %q: window must be 1..1000 samples", spec)
+		}
+		if kind == "median" {
+			return Median(n), nil
+		}
+		return Mean(n), nil
+	case "ema":
+		a, err := strconv.ParseFloat(arg, 64)
+		if err != nil || a <= 0 || a > 1 {
+			return nil, fmt.Errorf("filter %q: alpha must be in (0, 1]", spec)
+		}
+		return EMA(a), nil
+	case "window":
+		d, err := time.ParseDuration(arg)
+		if err != nil || d <= 0 || d > 24*time.Hour {
+			return nil, fmt.Errorf("filter %q: duration must be like 30s, up to 24h", spec)
+		}
+		return TimeWindow(d), nil
+	default:
+		return nil, fmt.Errorf("filter %q: unknown kind (use none, median, mean, ema, window)", spec)
+	}
+}
@@ -0,0 +1,106 @@
+package filter
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func feed(f Filter, vs ...float64) float64 {
+	var out float64
+	for _, v := range vs {
+		out = f.Add(v)
+	}
+	return out
+}
+
+func TestMedianRejectsSpike(t *testing.T) {
+	f := Median(3)
+	if v := feed(f, 1, 100, 2); v != 2 {
+		t.Errorf("expected 2, got %v", v)
+	}
+	if v := feed(f, 3); v != 3 {
+		t.Errorf("expected 3 after spike left the window, got %v", v)
+	}
+}
+
+func TestMean(t *testing.T) {
+	f := Mean(2)
+	if v := feed(f, 1, 2, 4); v != 3 {
+		t.Errorf("expected 3, got %v", v)
+	}
+	f.Reset()
+	if v := f.Add(10); v != 10 {
+		t.Errorf("expected reset mean 10, got %v", v)
+	}
+}
+
+func TestEMA(t *testing.T) {
+	f := EMA(0.5)
+	if v := feed(f, 10, 20); v != 15 {
+		t.Errorf("expected 15, got %v", v)
+	}
+}
+
+func TestTimeWindow(t *testing.T) {
+	now := time.Unix(0, 0)
+	f := TimeWindow(10 * time.Second).(*timeWindow)
+	f.now = func() time.Time { return now }
+
+	f.Add(1)
+	now = now.Add(5 * time.Second)
+	if v := f.Add(3); v != 2 {
+		t.Errorf("expected 2, got %v", v)
+	}
+	now = now.Add(8 * time.Second)
+	if v := f.Add(5); v != 4 {
+		t.Errorf("expected first sample to expire, got %v", v)
+	}
+}
+
+func TestChainMedianThenMean(t *testing.T) {
+	f := Chain(Median(3), Mean(2))
+	// Once the median window is full the spike is gone: medians 2, 3, mean 2.5.
+	if v := feed(f, 1, 100, 2, 3); v != 2.5 {
+		t.Errorf("expected 2.5, got %v", v)
+	}
+	f.Reset()
+	if v := f.Add(7); v != 7 {
+		t.Errorf("expected 7 after reset, got %v", v)
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, spec := range []string{"", "none", "median:5", "mean:3", "ema:0.2", "window:30s"} {
+		if _, err := Parse(spec); err != nil {
+			t.Errorf("Parse(%q): %v", spec, err)
+		}
+	}
+	for _, spec := range []string{"median", "median:0", "ema:2", "window:-1s", "kalman:1"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q): expected error", spec)
+		}
+	}
+}
+
+func TestSnapOnJump(t *testing.T) {
+	f := SnapOnJump(EMA(0.1), 5)
+	if v := feed(f, 10, 12); math.Abs(v-10.2) > 1e-9 {
+		t.Errorf("small step should be smoothed: got %v, want 10.2", v)
+	}
+	if f.(Snapper).Snapped() {
+		t.Error("small step reported as a snap")
+	}
+	if v := f.Add(30); v != 30 {
+		t.Errorf("large step should snap: got %v, want 30", v)
+	}
+	if !f.(Snapper).Snapped() {
+		t.Error("large step not reported as a snap")
+	}
+	if v := f.Add(31); math.Abs(v-30.1) > 1e-9 {
+		t.Errorf("after a snap smoothing resumes: got %v, want 30.1", v)
+	}
+	if _, ok := SnapOnJump(Mean(3), 0).(Snapper); ok {
+		t.Error("threshold 0 must return the filter unchanged")
+	}
+}
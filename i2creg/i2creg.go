@@ -0,0 +1,135 @@
+// i2creg.go
+//
+// Process-wide registry of I2C addresses claimed by drivers.
+//
+// Two different drivers configured at the same address on the same bus
+// silently corrupt each other's transactions. Drivers Claim (bus, addr,
+// driverType) in NewDriver and Release the returned Handle in Close. A Handle
+// releases only its own claim, once: a second Close never drops the claim of
+// a newer instance at the same address. A claim by a different
+// driver type is a conflict. By default a conflict is logged and allowed
+// (existing setups keep working); SetStrict(true) turns it into an error.
+//
+// Several instances of the same driver type may share an address (one per
+// channel of an ADC, for example), and driver types that are known to share a
+// module cooperatively can say so with the compatible argument.
+//
+package i2creg
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/reef-pi/rpi/i2c"
+)
+
+// ErrAddressInUse is returned (wrapped) by Claim in strict mode.
+var ErrAddressInUse = errors.New("i2c address already in use by another driver")
+
+type key struct {
+	bus  i2c.Bus
+	addr byte
+}
+
+var (
+	mu     sync.Mutex
+	claims = map[key]map[string]int{} // driver type -> live instances
+	strict bool
+)
+
+// SetStrict makes Claim return an error on conflict instead of only logging it.
+func SetStrict(on bool) {
+	mu.Lock()
+	strict = on
+	mu.Unlock()
+}
+
+// Handle is one live claim made by Claim.
+type Handle struct {
+	k          key
+	driverType string
+	once       sync.Once
+}
+
+// Claim records that a driver of driverType uses addr on bus and returns the
+// Handle that releases it. A claim already held by another driver type that is
+// not listed in compatible is a conflict: logged, and returned as
+// ErrAddressInUse in strict mode (nothing is recorded then).
+func Claim(bus i2c.Bus, addr byte, driverType string, compatible ...string) (*Handle, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	k := key{bus: bus, addr: addr}
+	held := claims[k]
+	var others []string
+	for t := range held {
+		if t != driverType && !contains(compatible, t) {
+			others = append(others, t)
+		}
+	}
+	if len(others) > 0 {
+		sort.Strings(others)
+		err := fmt.Errorf("%w: %s at 0x%02X, held by %s", ErrAddressInUse, driverType, addr, strings.Join(others, ", "))
+		if strict {
+			return nil, err
+		}
+		log.Printf("i2creg WARNING: %v; the drivers will interfere", err)
+	}
+
+	if held == nil {
+		held = map[string]int{}
+		claims[k] = held
+	}
+	held[driverType]++
+	return &Handle{k: k, driverType: driverType}, nil
+}
+
+// Release drops the claim. Only the first call has an effect, and a nil Handle
+// (config-only drivers never claim) is a no-op.
+func (h *Handle) Release() {
+	if h == nil {
+		return
+	}
+	h.once.Do(func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		held := claims[h.k]
+		if held[h.driverType] == 0 {
+			return
+		}
+		held[h.driverType]--
+		if held[h.driverType] == 0 {
+			delete(held, h.driverType)
+		}
+		if len(held) == 0 {
+			delete(claims, h.k)
+		}
+	})
+}
+
+// Holders returns the driver types currently claiming addr on bus, sorted.
+func Holders(bus i2c.Bus, addr byte) []string {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var out []string
+	for t := range claims[key{bus: bus, addr: addr}] {
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package i2creg
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// fakeBus is a distinct, comparable i2c.Bus.
+type fakeBus struct{ id int }
+
+func (b *fakeBus) SetAddress(_ byte) error                 { return nil }
+func (b *fakeBus) ReadBytes(_ byte, n int) ([]byte, error) { return make([]byte, n), nil }
+func (b *fakeBus) WriteBytes(_ byte, _ []byte) error       { return nil }
+func (b *fakeBus) ReadFromReg(_, _ byte, _ []byte) error   { return nil }
+func (b *fakeBus) WriteToReg(_, _ byte, _ []byte) error    { return nil }
+func (b *fakeBus) Close() error                            { return nil }
+
+func TestClaimConflicts(t *testing.T) {
+	SetStrict(true)
+	defer SetStrict(false)
+	bus, other := &fakeBus{1}, &fakeBus{2}
+
+	a1, err := Claim(bus, 0x48, "ads1115-tds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a2, err := Claim(bus, 0x48, "ads1115-tds")
+	if err != nil {
+		t.Errorf("same driver type must share an address: %v", err)
+	}
+	if _, err := Claim(bus, 0x48, "pcf8575"); !errors.Is(err, ErrAddressInUse) {
+		t.Errorf("expected ErrAddressInUse, got %v", err)
+	}
+	o, err := Claim(other, 0x48, "pcf8575")
+	if err != nil {
+		t.Errorf("another bus is not a conflict: %v", err)
+	}
+
+	a1.Release()
+	a1.Release() // a second Close must not drop a2's claim
+	if _, err := Claim(bus, 0x48, "pcf8575"); err == nil {
+		t.Error("one ads1115-tds claim is still live")
+	}
+	a2.Release()
+	p, err := Claim(bus, 0x48, "pcf8575")
+	if err != nil {
+		t.Errorf("released address should be free: %v", err)
+	}
+	p.Release()
+	o.Release()
+	if h := Holders(bus, 0x48); len(h) != 0 {
+		t.Errorf("expected no holders, got %v", h)
+	}
+	(*Handle)(nil).Release()
+}
+
+func TestClaimCompatibleAndWarnMode(t *testing.T) {
+	bus := &fakeBus{3}
+
+	if _, err := Claim(bus, 0x24, "aliexpress-ph", "aliexpress-orp"); err != nil {
+		t.Fatal(err)
+	}
+	SetStrict(true)
+	if _, err := Claim(bus, 0x24, "aliexpress-orp", "aliexpress-ph"); err != nil {
+		t.Errorf("compatible types must share: %v", err)
+	}
+	SetStrict(false)
+	if _, err := Claim(bus, 0x24, "robotank-ph"); err != nil {
+		t.Errorf("non-strict conflict must only warn: %v", err)
+	}
+	if h := Holders(bus, 0x24); len(h) != 3 {
+		t.Errorf("expected 3 holders, got %v", h)
+	}
+}
+
+func TestParseI2CAddress(t *testing.T) {
+	for _, v := range []interface{}{"0x48", "0X48", " 72 ", 72, float64(72), int64(72), "0b1001000", byte(0x48),
+		json.Number("72"), json.Number("72.0"), float32(72), uint16(72), uint64(72), int8(72),
+		map[string]interface{}{"value": "0x48"}, map[string]interface{}{"value": float64(72)}} {
+		a, err := ParseI2CAddress(v)
+		if err != nil || a != 0x48 {
+			t.Errorf("ParseI2CAddress(%#v) = 0x%02X, %v; want 0x48", v, a, err)
+		}
+	}
+	for _, v := range []interface{}{"", "0x", "0x80", "128", -1, 72.5, "0b2", "abc", true, nil,
+		json.Number("72.5"), json.Number("x"), uint64(1 << 63), float32(72.5), map[string]interface{}{"addr": 72}} {
+		if a, err := ParseI2CAddress(v); !errors.Is(err, ErrInvalidAddress) {
+			t.Errorf("ParseI2CAddress(%#v) = 0x%02X, %v; want ErrInvalidAddress", v, a, err)
+		}
+	}
+}
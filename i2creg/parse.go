@@ -0,0 +1,117 @@
+// parse.go
+//
+// ParseI2CAddress: one Address parser for every factory.
+//
+// Config UIs and saved configs hand the Address parameter over as a JSON
+// number, a decimal string or a hex string depending on the driver's declared
+// type and on who wrote the config. All factories accept the same forms
+// ("0x48", "72", 72, "0b1001000", {"value": 72}) and report the same errors.
+//
+package i2creg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidAddress is returned (wrapped) by ParseI2CAddress.
+var ErrInvalidAddress = errors.New("invalid I2C address")
+
+// maxAddress is the largest 7-bit I2C address.
+const maxAddress = 127
+
+// ParseI2CAddress returns the 7-bit address in v: any Go integer type, a
+// whole float (JSON numbers arrive as float64), a json.Number, or a string in
+// hex ("0x48"), decimal ("72") or binary ("0b1001000"). A {"value": ...}
+// wrapper, as some config UIs save typed parameters, is unwrapped first.
+func ParseI2CAddress(v interface{}) (byte, error) {
+	var n int64
+	switch t := v.(type) {
+	case map[string]interface{}:
+		inner, ok := t["value"]
+		if !ok {
+			return 0, fmt.Errorf("%w: object without a \"value\" key", ErrInvalidAddress)
+		}
+		return ParseI2CAddress(inner)
+	case string:
+		s := strings.ToLower(strings.TrimSpace(t))
+		base, digits := 10, s
+		switch {
+		case strings.HasPrefix(s, "0x"):
+			base, digits = 16, s[2:]
+		case strings.HasPrefix(s, "0b"):
+			base, digits = 2, s[2:]
+		}
+		i, err := strconv.ParseInt(digits, base, 64)
+		if err != nil || digits == "" {
+			return 0, fmt.Errorf("%w: %q is not a number (use hex 0x48, decimal 72 or binary 0b1001000)", ErrInvalidAddress, t)
+		}
+		n = i
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			n = i
+			break
+		}
+		f, err := t.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q is not a number", ErrInvalidAddress, t.String())
+		}
+		return parseFloatAddress(f)
+	case int:
+		n = int64(t)
+	case int8:
+		n = int64(t)
+	case int16:
+		n = int64(t)
+	case int32:
+		n = int64(t)
+	case int64:
+		n = t
+	case uint:
+		return parseUintAddress(uint64(t))
+	case uint8:
+		return parseUintAddress(uint64(t))
+	case uint16:
+		return parseUintAddress(uint64(t))
+	case uint32:
+		return parseUintAddress(uint64(t))
+	case uint64:
+		return parseUintAddress(t)
+	case float32:
+		return parseFloatAddress(float64(t))
+	case float64:
+		return parseFloatAddress(t)
+	case nil:
+		return 0, fmt.Errorf("%w: missing", ErrInvalidAddress)
+	default:
+		return 0, fmt.Errorf("%w: unsupported type %T (use a number or a string like 0x48)", ErrInvalidAddress, v)
+	}
+	if n < 0 || n > maxAddress {
+		return 0, fmt.Errorf("%w: %d (0x%X) out of range (0..%d, 7-bit)", ErrInvalidAddress, n, n, maxAddress)
+	}
+	return byte(n), nil
+}
+
+// parseUintAddress range-checks an unsigned value before narrowing it, so a
+// huge uint64 cannot wrap into range.
+func parseUintAddress(u uint64) (byte, error) {
+	if u > maxAddress {
+		return 0, fmt.Errorf("%w: %d (0x%X) out of range (0..%d, 7-bit)", ErrInvalidAddress, u, u, maxAddress)
+	}
+	return byte(u), nil
+}
+
+// parseFloatAddress accepts only whole, in-range floats.
+func parseFloatAddress(f float64) (byte, error) {
+	if f != math.Trunc(f) || math.IsInf(f, 0) || math.IsNaN(f) {
+		return 0, fmt.Errorf("%w: %v is not a whole number", ErrInvalidAddress, f)
+	}
+	if f < 0 || f > maxAddress {
+		return 0, fmt.Errorf("%w: %v out of range (0..%d, 7-bit)", ErrInvalidAddress, f, maxAddress)
+	}
+	return byte(f), nil
+}
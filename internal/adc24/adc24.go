@@ -0,0 +1,106 @@
+// adc24.go
+//
+// Decoding of the 3-byte reply of the AliExpress I2C ADC modules used by the
+// aliexpress_ph and aliexpress_orp drivers.
+//
+// The module returns a left-aligned, offset-binary code. ADC.cpp (and the
+// "default" variant here) does:
+//
+//	u32 = (b0<<24)|(b1<<16)|(b2<<8); u32 >>= 2; u32 &= 0x3FFFFFFF
+//	volts = (u32 - 0x20000000) / 2^29 * vref
+//
+// Other board revisions return a plain 24-bit code ("raw24"), and "custom"
+// takes the shift and mask from configuration. The mid-scale point is always
+// half the mask, so Volts works for any variant.
+//
+package adc24
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	VariantDefault = "default" // ADC.cpp: >>2, 30-bit code
+	VariantRaw24   = "raw24"   // plain 24-bit code, no shift
+	VariantCustom  = "custom"  // Shift / Mask from configuration
+
+	// DefaultMask is the mask of the default variant, formatted for a String parameter.
+	DefaultMask = "0x3FFFFFFF"
+)
+
+// Decoder turns the module's 3 reply bytes into an offset-binary code.
+type Decoder struct {
+	Variant string
+	Shift   uint   // right shift applied to (b0<<24)|(b1<<16)|(b2<<8)
+	Mask    uint32 // contiguous low bits kept after the shift
+}
+
+// Default matches ADC.cpp and every module the drivers were tested with.
+var Default = Decoder{Variant: VariantDefault, Shift: 2, Mask: 0x3FFFFFFF}
+
+var variants = map[string]Decoder{
+	VariantDefault: Default,
+	VariantRaw24:   {Variant: VariantRaw24, Shift: 8, Mask: 0xFFFFFF},
+}
+
+// New returns the decoder for variant. shift and mask are only used by
+// "custom"; mask accepts decimal or 0x-prefixed hex. An empty variant is the
+// default one.
+func New(variant string, shift int, mask string) (Decoder, error) {
+	variant = strings.ToLower(strings.TrimSpace(variant))
+	if variant == "" {
+		return Default, nil
+	}
+	if d, ok := variants[variant]; ok {
+		return d, nil
+	}
+	if variant != VariantCustom {
+		return Decoder{}, fmt.Errorf("module variant must be %q, %q or %q", VariantDefault, VariantRaw24, VariantCustom)
+	}
+
+	if shift < 0 || shift > 31 {
+		return Decoder{}, fmt.Errorf("decode shift must be 0..31, got %d", shift)
+	}
+	m, err := strconv.ParseUint(strings.TrimSpace(mask), 0, 32)
+	if err != nil {
+		return Decoder{}, fmt.Errorf("decode mask %q is not a number", mask)
+	}
+	// The code is returned as int32, and mid-scale is mask/2+1: the mask must
+	// be a run of low bits that fits in 31 bits.
+	if m == 0 || m > 0x7FFFFFFF || m&(m+1) != 0 {
+		return Decoder{}, fmt.Errorf("decode mask 0x%X must be contiguous low bits (e.g. 0xFFFFFF), at most 0x7FFFFFFF", m)
+	}
+	if bits := 32 - shift; m>>uint(bits) != 0 {
+		return Decoder{}, fmt.Errorf("decode mask 0x%X is wider than the %d bits left after shift %d", m, bits, shift)
+	}
+	return Decoder{Variant: VariantCustom, Shift: uint(shift), Mask: uint32(m)}, nil
+}
+
+// Code decodes the first 3 bytes of b. Caller checks len(b) >= 3.
+func (d Decoder) Code(b []byte) int32 {
+	u32 := uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8
+	u32 >>= d.Shift
+	u32 &= d.Mask
+	return int32(u32)
+}
+
+// Full is the largest code the decoder can return.
+func (d Decoder) Full() int32 { return int32(d.Mask) }
+
+// Mid is the offset-binary zero (0x20000000 for the default variant).
+func (d Decoder) Mid() int32 { return int32(d.Mask/2 + 1) }
+
+// Signed returns code relative to mid-scale.
+func (d Decoder) Signed(code int32) float64 {
+	return float64(int64(code) - int64(d.Mid()))
+}
+
+// Volts converts code to volts for reference vref.
+func (d Decoder) Volts(code int32, vref float64) float64 {
+	return d.Signed(code) / float64(d.Mid()) * vref
+}
+
+// MaskString formats the mask the way the DecodeMask parameter takes it.
+func (d Decoder) MaskString() string { return fmt.Sprintf("0x%X", d.Mask) }
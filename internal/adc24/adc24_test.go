@@ -0,0 +1,63 @@
+package adc24
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDefaultMatchesADCcpp(t *testing.T) {
+	// Mid-scale reads 0 V, top of the positive half reads ~+vref.
+	if c := Default.Code([]byte{0x80, 0x00, 0x00}); c != 0x20000000 {
+		t.Fatalf("Code(80 00 00) = 0x%08X, want 0x20000000", c)
+	}
+	if v := Default.Volts(0x20000000, 2.5); v != 0 {
+		t.Errorf("Volts(mid) = %v, want 0", v)
+	}
+	c := Default.Code([]byte{0xC0, 0x00, 0x00})
+	if v := Default.Volts(c, 2.5); math.Abs(v-1.25) > 1e-9 {
+		t.Errorf("Volts(C0 00 00) = %v, want 1.25", v)
+	}
+}
+
+func TestVariants(t *testing.T) {
+	raw, err := New(VariantRaw24, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := raw.Code([]byte{0x80, 0x00, 0x01}); c != 0x800001 {
+		t.Errorf("raw24 Code = 0x%X, want 0x800001", c)
+	}
+	if raw.Mid() != 0x800000 {
+		t.Errorf("raw24 Mid = 0x%X, want 0x800000", raw.Mid())
+	}
+
+	custom, err := New("Custom", 8, "0xFFFFFF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if custom.Shift != raw.Shift || custom.Mask != raw.Mask {
+		t.Errorf("custom 8/0xFFFFFF = %+v, want same decode as raw24", custom)
+	}
+
+	if d, err := New("", 0, ""); err != nil || d != Default {
+		t.Errorf("New(\"\") = %+v, %v; want Default", d, err)
+	}
+
+	for _, tc := range []struct {
+		variant string
+		shift   int
+		mask    string
+	}{
+		{"bogus", 2, DefaultMask},
+		{VariantCustom, 32, DefaultMask},
+		{VariantCustom, 2, "0"},
+		{VariantCustom, 2, "0x3FFFFFFE"}, // not contiguous
+		{VariantCustom, 0, "0xFFFFFFFF"}, // does not fit int32
+		{VariantCustom, 10, DefaultMask}, // wider than 22 bits
+		{VariantCustom, 2, "zz"},
+	} {
+		if _, err := New(tc.variant, tc.shift, tc.mask); err == nil {
+			t.Errorf("New(%q, %d, %q) accepted", tc.variant, tc.shift, tc.mask)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// linfit.go
+//
+// Least-squares line fit shared by the multi-point calibrations
+// (ads1115tds TdsK/TdsOffset, aliexpress_orp Scale/Offset).
+//
+// Fit returns the line; Residuals reports how well it explains the points, so
+// drivers can flag calibration standards that disagree with each other.
+//
+package linfit
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNoSpread is returned by Fit when the x values are (numerically) all equal.
+var ErrNoSpread = errors.New("x values have no spread")
+
+// Quality is the goodness of fit of a line over its points.
+type Quality struct {
+	R2          float64 // 1 when the y values have no spread
+	MaxResidual float64 // largest |y - fitted|
+}
+
+// Fit fits y = k*x + off by least squares over len(xs) points.
+func Fit(xs, ys []float64) (k, off float64, err error) {
+	mx, my := mean(xs), mean(ys)
+	var sxx, sxy float64
+	for i := range xs {
+		dx := xs[i] - mx
+		sxx += dx * dx
+		sxy += dx * (ys[i] - my)
+	}
+	if sxx < 1e-18 {
+		return 0, 0, ErrNoSpread
+	}
+	k = sxy / sxx
+	return k, my - k*mx, nil
+}
+
+// Residuals returns y - (k*x + off) for every point and the fit quality.
+func Residuals(xs, ys []float64, k, off float64) (residuals []float64, q Quality) {
+	my := mean(ys)
+	residuals = make([]float64, len(xs))
+	var ssRes, ssTot float64
+	for i := range xs {
+		r := ys[i] - (k*xs[i] + off)
+		residuals[i] = r
+		ssRes += r * r
+		ssTot += (ys[i] - my) * (ys[i] - my)
+		q.MaxResidual = math.Max(q.MaxResidual, math.Abs(r))
+	}
+	q.R2 = 1
+	if ssTot > 0 {
+		q.R2 = 1 - ssRes/ssTot
+	}
+	return residuals, q
+}
+
+func mean(v []float64) float64 {
+	var s float64
+	for _, x := range v {
+		s += x
+	}
+	return s / float64(len(v))
+}
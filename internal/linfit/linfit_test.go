@@ -0,0 +1,49 @@
+package linfit
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFitExactLine(t *testing.T) {
+	xs := []float64{0.5, 1.0, 2.0}
+	ys := []float64{260, 510, 1010}
+	k, off, err := Fit(xs, ys)
+	if err != nil || math.Abs(k-500) > 1e-9 || math.Abs(off-10) > 1e-9 {
+		t.Fatalf("Fit = %v, %v, %v; want 500, 10", k, off, err)
+	}
+	res, q := Residuals(xs, ys, k, off)
+	if math.Abs(q.R2-1) > 1e-12 || q.MaxResidual > 1e-9 || len(res) != 3 {
+		t.Errorf("exact fit: residuals=%v quality=%+v", res, q)
+	}
+}
+
+func TestResidualsFlagOutlier(t *testing.T) {
+	xs := []float64{0, 1, 2, 3}
+	ys := []float64{0, 1, 2, 4}
+	k, off, err := Fit(xs, ys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, q := Residuals(xs, ys, k, off)
+	if q.R2 >= 1 || q.MaxResidual <= 0 {
+		t.Errorf("outlier not reflected: %+v", q)
+	}
+	var max float64
+	for _, r := range res {
+		max = math.Max(max, math.Abs(r))
+	}
+	if q.MaxResidual != max {
+		t.Errorf("MaxResidual %v, want max |residual| %v (%v)", q.MaxResidual, max, res)
+	}
+}
+
+func TestFitNoSpread(t *testing.T) {
+	if _, _, err := Fit([]float64{1, 1}, []float64{100, 200}); !errors.Is(err, ErrNoSpread) {
+		t.Errorf("expected ErrNoSpread, got %v", err)
+	}
+	if _, q := Residuals([]float64{1, 2}, []float64{5, 5}, 0, 5); q.R2 != 1 {
+		t.Errorf("flat ys: R2 = %v, want 1", q.R2)
+	}
+}
@@ -0,0 +1,117 @@
+// timing.go
+//
+// Advanced timing parameters (milliseconds) of the AliExpress I2C ADC module
+// drivers, aliexpress_ph and aliexpress_orp.
+//
+// Both drivers expose the same I2C pacing knobs with the same bounds; each may
+// add its own (e.g. ConversionDelayMs). Validate reports out-of-range values in
+// the factories' failures map and Ms reads a value as a time.Duration. Values
+// may arrive as numbers, numeric strings or {"value": ...} wrappers, like every
+// other parameter of these drivers.
+//
+package timing
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parameter names of the I2C pacing knobs shared by the module drivers.
+const (
+	MinI2CGapMs       = "MinI2CGapMs"
+	CacheMaxAgeMs     = "CacheMaxAgeMs"
+	SettleAfterReadMs = "SettleAfterReadMs"
+	RetryDelayMs      = "RetryDelayMs"
+)
+
+// Bound is the accepted range (ms) of one timing parameter.
+type Bound struct {
+	Name     string
+	Min, Max int
+}
+
+// I2CBounds are the ranges of the shared I2C pacing knobs.
+var I2CBounds = []Bound{
+	{MinI2CGapMs, 5, 500},
+	{CacheMaxAgeMs, 0, 5000}, // 0 disables the cache
+	{SettleAfterReadMs, 0, 100},
+	{RetryDelayMs, 1, 1000},
+}
+
+// Validate appends a failure for every parameter in bounds that is set (under
+// its name or the lower-cased name) but is not an integer within its range.
+func Validate(parameters map[string]interface{}, failures map[string][]string, bounds ...Bound) {
+	for _, b := range bounds {
+		v, ok := lookup(parameters, b.Name, strings.ToLower(b.Name))
+		if !ok {
+			continue
+		}
+		if ms, ok := toInt(v); !ok || ms < b.Min || ms > b.Max {
+			failures[b.Name] = append(failures[b.Name], fmt.Sprintf("%s must be an integer %d..%d", b.Name, b.Min, b.Max))
+		}
+	}
+}
+
+// Ms reads an optional millisecond parameter under the first present key,
+// falling back to def when none is set or the value is not a number.
+func Ms(parameters map[string]interface{}, def time.Duration, keys ...string) time.Duration {
+	v, ok := lookup(parameters, keys...)
+	if !ok {
+		return def
+	}
+	ms, ok := toInt(v)
+	if !ok {
+		return def
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+func lookup(m map[string]interface{}, keys ...string) (interface{}, bool) {
+	for _, k := range keys {
+		if v, ok := m[k]; ok {
+			return unwrap(v), true
+		}
+	}
+	return nil, false
+}
+
+// unwrap returns the inner value of a {"value": ...} style wrapper.
+func unwrap(v interface{}) interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		for _, k := range []string{"value", "Value", "current", "Current", "val", "Val"} {
+			if vv, ok := m[k]; ok {
+				return vv
+			}
+		}
+	}
+	return v
+}
+
+// toInt accepts whole or fractional numbers (truncated) and numeric strings.
+func toInt(v interface{}) (int, bool) {
+	switch t := v.(type) {
+	case int:
+		return t, true
+	case int64:
+		return int(t), true
+	case float64:
+		return int(t), true
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return int(i), true
+		}
+		f, err := t.Float64()
+		return int(f), err == nil
+	case string:
+		s := strings.TrimSpace(t)
+		if i, err := strconv.Atoi(s); err == nil {
+			return i, true
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		return int(f), err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,46 @@
+package timing
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	failures := map[string][]string{}
+	Validate(map[string]interface{}{
+		MinI2CGapMs:     "20",
+		"cachemaxagems": 9000,
+		RetryDelayMs:    map[string]interface{}{"value": 0},
+		"WarmupMs":      json.Number("100"),
+	}, failures, append(I2CBounds, Bound{"WarmupMs", 0, 60000})...)
+
+	if _, ok := failures[MinI2CGapMs]; ok {
+		t.Errorf("MinI2CGapMs=\"20\" must be accepted: %v", failures)
+	}
+	if _, ok := failures[CacheMaxAgeMs]; !ok {
+		t.Error("lower-cased cachemaxagems=9000 must be rejected")
+	}
+	if _, ok := failures[RetryDelayMs]; !ok {
+		t.Error("wrapped RetryDelayMs=0 must be rejected (min 1)")
+	}
+	if _, ok := failures["WarmupMs"]; ok {
+		t.Errorf("driver bound WarmupMs=100 must be accepted: %v", failures)
+	}
+	if _, ok := failures[SettleAfterReadMs]; ok {
+		t.Error("unset parameters must not fail")
+	}
+}
+
+func TestMs(t *testing.T) {
+	p := map[string]interface{}{"retrydelayms": 25.0, MinI2CGapMs: "x"}
+	if d := Ms(p, time.Second, RetryDelayMs, "retrydelayms"); d != 25*time.Millisecond {
+		t.Errorf("Ms(retrydelayms) = %v, want 25ms", d)
+	}
+	if d := Ms(p, 10*time.Millisecond, MinI2CGapMs); d != 10*time.Millisecond {
+		t.Errorf("Ms(non-number) = %v, want the default", d)
+	}
+	if d := Ms(p, 0, CacheMaxAgeMs); d != 0 {
+		t.Errorf("Ms(unset) = %v, want 0", d)
+	}
+}
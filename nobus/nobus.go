@@ -0,0 +1,55 @@
+// nobus.go
+//
+// Config-only drivers: NewDriver without a live I2C bus.
+//
+// Hosts sometimes build a driver only to validate its configuration or to
+// preview its metadata and pipeline description, and pass nil as the hardware
+// resource. Factories call I2C on their hardwareResources argument: nil yields
+// a Bus whose every transfer fails with ErrNoBus, so parameter parsing,
+// metadata and description methods keep working while reads and writes return
+// a clear error instead of panicking on a type assertion.
+//
+// Config-only factories skip start-up I/O and i2creg claims (see Is).
+//
+package nobus
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/reef-pi/rpi/i2c"
+)
+
+// ErrNoBus is returned by every transfer on a config-only driver.
+var ErrNoBus = errors.New("no I2C bus: driver was created config-only (nil hardware resource)")
+
+// Bus is an i2c.Bus without hardware behind it.
+type Bus struct{}
+
+var _ i2c.Bus = Bus{}
+
+func (Bus) SetAddress(_ byte) error                 { return ErrNoBus }
+func (Bus) ReadBytes(_ byte, _ int) ([]byte, error) { return nil, ErrNoBus }
+func (Bus) WriteBytes(_ byte, _ []byte) error       { return ErrNoBus }
+func (Bus) ReadFromReg(_, _ byte, _ []byte) error   { return ErrNoBus }
+func (Bus) WriteToReg(_, _ byte, _ []byte) error    { return ErrNoBus }
+func (Bus) Close() error                            { return nil }
+
+// I2C returns hardwareResources as an i2c.Bus, or Bus{} when it is nil.
+// Any other type is an error naming driver.
+func I2C(driver string, hardwareResources interface{}) (i2c.Bus, error) {
+	if hardwareResources == nil {
+		return Bus{}, nil
+	}
+	bus, ok := hardwareResources.(i2c.Bus)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected i2c.Bus as hardware resource, got %T", driver, hardwareResources)
+	}
+	return bus, nil
+}
+
+// Is reports whether bus is a config-only Bus.
+func Is(bus i2c.Bus) bool {
+	_, ok := bus.(Bus)
+	return ok
+}
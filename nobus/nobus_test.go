@@ -0,0 +1,27 @@
+package nobus
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakeBus struct{ Bus }
+
+func TestI2C(t *testing.T) {
+	bus, err := I2C("test", nil)
+	if err != nil || !Is(bus) {
+		t.Fatalf("I2C(nil) = %T, %v; want config-only Bus", bus, err)
+	}
+	if _, err := bus.ReadBytes(0x48, 2); !errors.Is(err, ErrNoBus) {
+		t.Errorf("ReadBytes err = %v, want ErrNoBus", err)
+	}
+
+	live := &fakeBus{}
+	if bus, err := I2C("test", live); err != nil || bus != live || Is(bus) {
+		t.Errorf("I2C(live) = %T, %v; want the bus unchanged", bus, err)
+	}
+
+	if _, err := I2C("test", "not a bus"); err == nil {
+		t.Error("I2C(string) accepted")
+	}
+}
@@ -0,0 +1,55 @@
+// diagnose.go
+//
+// Health report (see package diag).
+//
+// The liveness check reads the ADS1119 configuration register (RREG), which
+// changes nothing on the converter. A register that no longer holds
+// configByte means the ADC reset (power glitch) and stopped converting. One
+// module read follows.
+//
+package orp_board
+
+import (
+	"time"
+
+	"github.com/reef-pi/drivers/diag"
+)
+
+// cmdRReg reads register 0 (configuration) of the ADS1119.
+const cmdRReg = 0x20
+
+var _ diag.Diagnoser = (*orpDriver)(nil)
+
+// readConfig reads the ADS1119 configuration register.
+func (d *orpDriver) readConfig() (byte, error) {
+	lock := lockForAddr(d.addr)
+	lock.Lock()
+	defer lock.Unlock()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.enforceMinGap(minI2CGap)
+	buf := make([]byte, 1)
+	err := d.bus.ReadFromReg(d.addr, cmdRReg, buf)
+	d.lastXferAt = time.Now()
+	return buf[0], err
+}
+
+// Diagnose reads the ADC configuration and one sample.
+func (d *orpDriver) Diagnose() diag.Diagnostics {
+	r := diag.New()
+
+	cfg, err := d.readConfig()
+	if r.Check("read config register", err) && cfg != configByte {
+		r.Warnf("config register 0x%02X, want 0x%02X; the ADC may have reset (power glitch) and stopped converting", cfg, configByte)
+	}
+	if mv, _, code, err := d.readObservedMV(); r.Check("read module", err) {
+		r.Infof("observed_mv=%.2f adc=%d", mv, code)
+	}
+
+	if d.calibrationMV == 0 {
+		r.Infof("not calibrated (Calibration_mV unset); reporting raw electrode mV")
+	}
+	return r
+}
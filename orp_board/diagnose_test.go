@@ -0,0 +1,43 @@
+package orp_board
+
+import (
+	"testing"
+	"time"
+)
+
+// regBus is an i2c.Bus answering ReadFromReg from regs by command byte.
+type regBus struct {
+	regs map[byte][]byte
+}
+
+func (b *regBus) SetAddress(_ byte) error                 { return nil }
+func (b *regBus) ReadBytes(_ byte, _ int) ([]byte, error) { return nil, nil }
+func (b *regBus) WriteBytes(_ byte, _ []byte) error       { return nil }
+func (b *regBus) ReadFromReg(_, reg byte, v []byte) error {
+	copy(v, b.regs[reg])
+	return nil
+}
+func (b *regBus) WriteToReg(_, _ byte, _ []byte) error { return nil }
+func (b *regBus) Close() error                         { return nil }
+
+func TestDiagnose(t *testing.T) {
+	bus := &regBus{regs: map[byte][]byte{cmdRReg: {configByte}, cmdRData: {0x01, 0x00}}}
+	d := &orpDriver{addr: 0x45, bus: bus, vrefV: 2.048, calibrationMV: 250}
+
+	r := d.Diagnose()
+	if !r.OK || len(r.Messages) != 3 {
+		t.Errorf("healthy module: %+v", r)
+	}
+
+	// The ADC reset: config back to its power-on 0x00.
+	bus.regs[cmdRReg] = []byte{0x00}
+	d.lastSampleAt, d.lastXferAt = time.Time{}, time.Time{}
+	if r := d.Diagnose(); !r.OK || len(r.Messages) != 4 {
+		t.Errorf("reset ADC should warn: %+v", r)
+	}
+
+	d.calibrationMV = 0
+	if r := d.Diagnose(); len(r.Messages) != 5 {
+		t.Errorf("uncalibrated module should say so: %+v", r)
+	}
+}
@@ -8,8 +8,9 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/reef-pi/drivers/i2creg"
+	"github.com/reef-pi/drivers/nobus"
 	"github.com/reef-pi/hal"
-	"github.com/reef-pi/rpi/i2c"
 )
 
 type factory struct {
@@ -74,11 +75,8 @@ func (f *factory) ValidateParameters(parameters map[string]interface{}) (bool, m
 	if !ok {
 		failures[addressParam] = append(failures[addressParam], "Address parameter is required")
 	} else {
-		addr, ok := toInt(addrRaw)
-		if !ok {
-			failures[addressParam] = append(failures[addressParam], "Address must be an integer (0..127)")
-		} else if addr < 0 || addr > 127 {
-			failures[addressParam] = append(failures[addressParam], "Address must be 0..127 (7-bit)")
+		if _, err := i2creg.ParseI2CAddress(addrRaw); err != nil {
+			failures[addressParam] = append(failures[addressParam], err.Error())
 		}
 	}
 
@@ -98,12 +96,20 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
 		}
 	}
 
-	addrInt := getIntAny(parameters, 0x45, addressParam, "address")
+	addr := byte(0x45)
+	if v, ok := getAny(parameters, addressParam, "address"); ok {
+		addr, _ = i2creg.ParseI2CAddress(v)
+	}
 	calibrationMV := getFloatAny(parameters, 0.0, calibrationParam, "calibration_mv", "orp_calibration_mv", "reference_mv")
 
+	bus, err := nobus.I2C("orp_board", hardwareResources)
+	if err != nil {
+		return nil, err
+	}
+
 	d := &orpDriver{
-		addr:          byte(addrInt),
-		bus:           hardwareResources.(i2c.Bus),
+		addr:          addr,
+		bus:           bus,
 		vrefV:         2.048, // ADS1119 internal reference
 		calibrationMV: calibrationMV,
 		debug:         debug,
@@ -118,11 +124,14 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
 
 	if debug {
 		log.Printf("orp_board_driver init addr=%d (0x%02X) vref=%.3f calibrationMV=%.2f",
-			addrInt, addrInt, d.vrefV, d.calibrationMV)
+			addr, addr, d.vrefV, d.calibrationMV)
 	}
 
-	if err := d.initADC(); err != nil {
-		return nil, err
+	// Config-only drivers (nil bus) skip the ADC setup; reads return nobus.ErrNoBus.
+	if !nobus.Is(bus) {
+		if err := d.initADC(); err != nil {
+			return nil, err
+		}
 	}
 
 	return d, nil
@@ -6,8 +6,9 @@ import (
 	"log"
 	"sync"
 
+	"github.com/reef-pi/drivers/i2creg"
+	"github.com/reef-pi/drivers/nobus"
 	"github.com/reef-pi/hal"
-	"github.com/reef-pi/rpi/i2c"
 )
 
 const addressParam = "Address"
@@ -68,14 +69,8 @@ func (f *pcaFactory) ValidateParameters(parameters map[string]interface{}) (bool
 	var ok bool
 
 	if v, ok = parameters[addressParam]; ok {
-		val, ok := hal.ConvertToInt(v)
-		if !ok {
-			failure := fmt.Sprint(addressParam, " is not a number. ", v, " was received.")
-			failures[addressParam] = append(failures[addressParam], failure)
-		}
-		if val <= 0 || val >= 256 {
-			failure := fmt.Sprint(addressParam, " is out of range (1 - 255). ", v, " was received.")
-			failures[addressParam] = append(failures[addressParam], failure)
+		if _, err := i2creg.ParseI2CAddress(v); err != nil {
+			failures[addressParam] = append(failures[addressParam], err.Error())
 		}
 	} else {
 		failure := fmt.Sprint(addressParam, " is required parameter, but was not received.")
@@ -105,15 +100,18 @@ func (f *pcaFactory) NewDriver(parameters map[string]interface{}, hardwareResour
 		return nil, errors.New(hal.ToErrorString(failures))
 	}
 
-	address, _ := hal.ConvertToInt(parameters[addressParam])
+	address, _ := i2creg.ParseI2CAddress(parameters[addressParam])
 	frequency, _ := hal.ConvertToInt(parameters[freqParam])
 
 	config := PCA9685Config{
-		Address:   address,
+		Address:   int(address),
 		Frequency: frequency,
 	}
 
-	bus := hardwareResources.(i2c.Bus)
+	bus, err := nobus.I2C("pca9685", hardwareResources)
+	if err != nil {
+		return nil, err
+	}
 
 	hwDriver := &PCA9685{
 		addr: byte(address),
@@ -141,5 +139,8 @@ func (f *pcaFactory) NewDriver(parameters map[string]interface{}, hardwareResour
 	}
 
 	// Wake the hardware
+	if nobus.Is(bus) {
+		return &pwm, nil
+	}
 	return &pwm, hwDriver.Wake()
 }
@@ -1,8 +1,10 @@
 package pca9685
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/reef-pi/drivers/nobus"
 	"github.com/reef-pi/hal"
 	"github.com/reef-pi/rpi/i2c"
 )
@@ -95,3 +97,24 @@ func TestPca9685Driver_Close(t *testing.T) {
 		t.Errorf("unexpected error closing driver %v", err)
 	}
 }
+
+func TestConfigOnlyDriver(t *testing.T) {
+	driver, err := Factory().NewDriver(params, nil)
+	if err != nil {
+		t.Fatalf("config-only driver: %v", err)
+	}
+	if driver.Metadata().Name != "pca9685" {
+		t.Errorf("unexpected metadata %+v", driver.Metadata())
+	}
+	ch, err := driver.(hal.PWMDriver).PWMChannel(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ch.Set(50); !errors.Is(err, nobus.ErrNoBus) {
+		t.Errorf("Set on config-only driver: err = %v, want ErrNoBus", err)
+	}
+
+	if _, err := Factory().NewDriver(params, "not a bus"); err == nil {
+		t.Error("non-bus hardware resource accepted")
+	}
+}
@@ -0,0 +1,50 @@
+// diagnose.go
+//
+// Health report (see package diag).
+//
+// The liveness check is a single Read16 of the port. A read never changes the
+// latch, so it is safe on a live relay board. Counters come from Stats.
+//
+package pcf8575
+
+import (
+	"github.com/reef-pi/drivers/diag"
+)
+
+var _ diag.Diagnoser = (*pcf8575Driver)(nil)
+
+// Diagnose reads the port once and reports the transaction counters.
+func (d *pcf8575Driver) Diagnose() diag.Diagnostics {
+	r := diag.New()
+
+	d.mu.Lock()
+	port, err := d.read16Locked()
+	s := d.stats
+	shadow, dirty := d.shadow, d.dirty
+	d.mu.Unlock()
+
+	if r.Check("read port", err) {
+		r.Infof("port=0x%04X shadow=0x%04X", port, shadow)
+		// A released pin reads its level; a pin latched LOW must read LOW.
+		if low := ^shadow & port; low != 0 {
+			r.Warnf("pins 0x%04X are latched LOW but read HIGH; the chip may have reset (power glitch) and released them", low)
+		}
+	}
+	if dirty {
+		r.Warnf("a batch is open: latch changes are pending until EndBatch")
+	}
+	if s.Timeouts > 0 {
+		r.Warnf("%d transactions timed out (OpTimeoutMs); a device may be holding the bus", s.Timeouts)
+	}
+	if r.LastError == "" && s.LastError != "" {
+		r.LastError = s.LastError
+	}
+
+	r.Counters["writes"] = s.Writes
+	r.Counters["reads"] = s.Reads
+	r.Counters["retries"] = s.Retries
+	r.Counters["skipped"] = s.Skipped
+	r.Counters["errors"] = s.Errors
+	r.Counters["timeouts"] = s.Timeouts
+	return r
+}
@@ -19,7 +19,8 @@
 //   - We keep a "shadow" 16-bit latch of the last value written.
 //   - All I2C transactions are serialized with a mutex so concurrent reads/writes
 //     cannot interleave (reef-pi can call pins concurrently).
-//   - A "safe default" of 0xFFFF is applied at startup (release all pins).
+//   - A "safe default" of 0xFFFF is applied at startup (release all pins), unless
+//     AdoptCurrentState keeps the outputs the chip is already driving.
 //
 package pcf8575
 
@@ -30,14 +31,43 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/reef-pi/drivers/i2creg"
+	"github.com/reef-pi/drivers/nobus"
 	"github.com/reef-pi/hal"
-	"github.com/reef-pi/rpi/i2c"
 )
 
 const (
-	paramAddress = "Address" // string, e.g. "0x20"
-	paramDebug   = "Debug"   // bool
+	paramAddress         = "Address"             // string, e.g. "0x20"
+	paramDebug           = "Debug"               // bool
+	paramReadModifyWrite = "ReadModifyWrite"     // bool
+	paramBidiPins        = "BidiPins"            // string, e.g. "0,3,8-11"
+	paramBidiReadPolicy  = "BidiReadPolicy"      // string: release|error|latched
+	paramReadDebounceMs  = "ReadDebounceMs"      // int, 0..100
+	paramSinkOnlyPins    = "SinkOnlyPins"        // string, e.g. "0-7"
+	paramSinkOnlyPolicy  = "SinkOnlyPolicy"      // string: warn|error
+	paramOutputPins      = "OutputPins"          // string, e.g. "0-7"; used by SelfTest
+	paramIdentifyPin     = "IdentifyPin"         // int, -1 (all outputs) or 0..15
+	paramSkipRedundant   = "SkipRedundantWrites" // bool
+	paramAdoptCurrent    = "AdoptCurrentState"   // bool
+	paramOpTimeoutMs     = "OpTimeoutMs"         // int, 0 (off) or 10..5000
+	paramPinRemap        = "PinRemap"            // string, e.g. "7-0"; logical -> physical bit
+	paramPollInputsMs    = "PollInputsMs"        // int, 0 (off) or 10..60000; see watch.go
+	paramWatchPins       = "WatchPins"           // string, e.g. "8-11"; inputs polled for WatchInputs
+)
+
+const maxReadDebounceMs = 100
+
+// Per-transaction timeout (see PCF8575.SetTimeout). A 2-byte transfer takes
+// well under 1ms; the default leaves room for clock stretching by slow devices
+// sharing the bus. A write that times out may still reach the chip once the
+// stuck call returns: the driver keeps its previous latch and re-asserts it
+// before the next transaction, so a late write is undone rather than kept.
+const (
+	defaultOpTimeoutMs = 250
+	minOpTimeoutMs     = 10
+	maxOpTimeoutMs     = 5000
 )
 
 type factory struct {
@@ -64,6 +94,20 @@ func Factory() hal.DriverFactory {
 			parameters: []hal.ConfigParameter{
 				{Name: paramAddress, Type: hal.String, Order: 0, Default: "0x20"},
 				{Name: paramDebug, Type: hal.Boolean, Order: 1, Default: false},
+				{Name: paramReadModifyWrite, Type: hal.Boolean, Order: 2, Default: false},
+				{Name: paramBidiPins, Type: hal.String, Order: 3, Default: ""},
+				{Name: paramBidiReadPolicy, Type: hal.String, Order: 4, Default: string(BidiReadRelease)},
+				{Name: paramReadDebounceMs, Type: hal.Integer, Order: 5, Default: 0},
+				{Name: paramSinkOnlyPins, Type: hal.String, Order: 6, Default: ""},
+				{Name: paramSinkOnlyPolicy, Type: hal.String, Order: 7, Default: sinkOnlyWarn},
+				{Name: paramOutputPins, Type: hal.String, Order: 8, Default: ""},
+				{Name: paramIdentifyPin, Type: hal.Integer, Order: 9, Default: -1},
+				{Name: paramSkipRedundant, Type: hal.Boolean, Order: 10, Default: true},
+				{Name: paramAdoptCurrent, Type: hal.Boolean, Order: 11, Default: false},
+				{Name: paramOpTimeoutMs, Type: hal.Integer, Order: 12, Default: defaultOpTimeoutMs},
+				{Name: paramPinRemap, Type: hal.String, Order: 13, Default: ""},
+				{Name: paramPollInputsMs, Type: hal.Integer, Order: 14, Default: 0},
+				{Name: paramWatchPins, Type: hal.String, Order: 15, Default: ""},
 			},
 		}
 	})
@@ -73,40 +117,96 @@ func Factory() hal.DriverFactory {
 func (f *factory) Metadata() hal.Metadata               { return f.meta }
 func (f *factory) GetParameters() []hal.ConfigParameter { return f.parameters }
 
-// parseAddr accepts "0x20" style hex or "32" style decimal.
-// Returns a 7-bit I2C address byte.
-func parseAddr(s string) (byte, error) {
-	s = strings.TrimSpace(strings.ToLower(s))
-	if s == "" {
-		return 0, fmt.Errorf("empty address")
-	}
-	if strings.HasPrefix(s, "0x") {
-		v, err := strconv.ParseUint(s[2:], 16, 8)
-		return byte(v), err
-	}
-	v, err := strconv.ParseUint(s, 10, 8)
-	return byte(v), err
-}
-
 func (f *factory) ValidateParameters(params map[string]interface{}) (bool, map[string][]string) {
 	errs := make(map[string][]string)
 
-	addrStr, _ := params[paramAddress].(string)
-	addrStr = strings.TrimSpace(addrStr)
-	if addrStr == "" {
+	if v, ok := params[paramAddress]; !ok || v == nil || v == "" {
 		errs[paramAddress] = append(errs[paramAddress], "is required (e.g. 0x20)")
-	} else {
-		addr, err := parseAddr(addrStr)
-		if err != nil {
-			errs[paramAddress] = append(errs[paramAddress], "must be a valid I2C address like 0x20..0x27")
-		} else if addr > 127 {
-			errs[paramAddress] = append(errs[paramAddress], "must be a 7-bit address (0..127)")
+	} else if _, err := i2creg.ParseI2CAddress(v); err != nil {
+		errs[paramAddress] = append(errs[paramAddress], err.Error())
+	}
+
+	for _, k := range []string{paramDebug, paramReadModifyWrite, paramSkipRedundant, paramAdoptCurrent} {
+		if v, ok := params[k]; ok {
+			if _, ok := v.(bool); !ok {
+				errs[k] = append(errs[k], "must be boolean")
+			}
 		}
 	}
 
-	if v, ok := params[paramDebug]; ok {
-		if _, ok := v.(bool); !ok {
-			errs[paramDebug] = append(errs[paramDebug], "must be boolean")
+	for _, k := range []string{paramBidiPins, paramSinkOnlyPins, paramOutputPins, paramWatchPins} {
+		if v, ok := params[k]; ok {
+			s, ok := v.(string)
+			if !ok {
+				errs[k] = append(errs[k], "must be a pin list like 0,3,8-11")
+			} else if _, err := parsePinList(s); err != nil {
+				errs[k] = append(errs[k], err.Error())
+			}
+		}
+	}
+
+	if v, ok := params[paramSinkOnlyPolicy]; ok {
+		s, _ := v.(string)
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case "", sinkOnlyWarn, sinkOnlyError:
+		default:
+			errs[paramSinkOnlyPolicy] = append(errs[paramSinkOnlyPolicy], "must be warn or error")
+		}
+	}
+
+	if v, ok := params[paramBidiReadPolicy]; ok {
+		s, _ := v.(string)
+		switch BidiReadPolicy(strings.ToLower(strings.TrimSpace(s))) {
+		case "", BidiReadRelease, BidiReadError, BidiReadLatched:
+		default:
+			errs[paramBidiReadPolicy] = append(errs[paramBidiReadPolicy], "must be release, error or latched")
+		}
+	}
+
+	if v, ok := params[paramReadDebounceMs]; ok {
+		if ms, ok := hal.ConvertToInt(v); !ok || ms < 0 || ms > maxReadDebounceMs {
+			errs[paramReadDebounceMs] = append(errs[paramReadDebounceMs], fmt.Sprintf("must be 0..%d ms", maxReadDebounceMs))
+		}
+	}
+
+	if v, ok := params[paramIdentifyPin]; ok {
+		if pin, ok := hal.ConvertToInt(v); !ok || pin < -1 || pin > 15 {
+			errs[paramIdentifyPin] = append(errs[paramIdentifyPin], "must be -1 (all outputs) or 0..15")
+		}
+	}
+
+	if v, ok := params[paramOpTimeoutMs]; ok {
+		if ms, ok := hal.ConvertToInt(v); !ok || ms != 0 && (ms < minOpTimeoutMs || ms > maxOpTimeoutMs) {
+			errs[paramOpTimeoutMs] = append(errs[paramOpTimeoutMs],
+				fmt.Sprintf("must be 0 (off) or %d..%d ms", minOpTimeoutMs, maxOpTimeoutMs))
+		}
+	}
+
+	if v, ok := params[paramPollInputsMs]; ok {
+		if ms, ok := hal.ConvertToInt(v); !ok || ms != 0 && (ms < minPollInputsMs || ms > maxPollInputsMs) {
+			errs[paramPollInputsMs] = append(errs[paramPollInputsMs],
+				fmt.Sprintf("must be 0 (off) or %d..%d ms", minPollInputsMs, maxPollInputsMs))
+		} else if ms != 0 {
+			watchStr, _ := params[paramWatchPins].(string)
+			outStr, _ := params[paramOutputPins].(string)
+			watchMask, _ := parsePinList(watchStr)
+			outMask, _ := parsePinList(outStr)
+			switch {
+			case watchMask == 0:
+				errs[paramWatchPins] = append(errs[paramWatchPins], "is required with PollInputsMs (polling releases the watched pins)")
+			case watchMask&outMask != 0:
+				errs[paramWatchPins] = append(errs[paramWatchPins],
+					fmt.Sprintf("must not overlap OutputPins (pins 0x%04X)", watchMask&outMask))
+			}
+		}
+	}
+
+	if v, ok := params[paramPinRemap]; ok {
+		s, ok := v.(string)
+		if !ok {
+			errs[paramPinRemap] = append(errs[paramPinRemap], "must be a bit list like 7-0 or 7-0,15-8")
+		} else if _, err := parsePinRemap(s); err != nil {
+			errs[paramPinRemap] = append(errs[paramPinRemap], err.Error())
 		}
 	}
 
@@ -116,21 +216,45 @@ func (f *factory) ValidateParameters(params map[string]interface{}) (bool, map[s
 	return true, nil
 }
 
+// parsePinList parses "0,3,8-11" into a 16-bit pin mask.
+func parsePinList(s string) (uint16, error) {
+	var mask uint16
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi := part, part
+		if i := strings.Index(part, "-"); i >= 0 {
+			lo, hi = part[:i], part[i+1:]
+		}
+		a, err1 := strconv.Atoi(strings.TrimSpace(lo))
+		b, err2 := strconv.Atoi(strings.TrimSpace(hi))
+		if err1 != nil || err2 != nil || a < 0 || b > 15 || a > b {
+			return 0, fmt.Errorf("invalid pin or range %q (pins are 0..15)", part)
+		}
+		for p := a; p <= b; p++ {
+			mask |= 1 << p
+		}
+	}
+	return mask, nil
+}
+
 func (f *factory) NewDriver(params map[string]interface{}, bus interface{}) (hal.Driver, error) {
 	// Defensive validation (reef-pi may call ValidateParameters separately; don't rely on it).
 	if ok, failures := f.ValidateParameters(params); !ok {
 		return nil, fmt.Errorf(hal.ToErrorString(failures))
 	}
 
-	i2cBus, ok := bus.(i2c.Bus)
-	if !ok {
-		return nil, fmt.Errorf("pcf8575: expected i2c.Bus, got %T", bus)
+	i2cBus, err := nobus.I2C("pcf8575", bus)
+	if err != nil {
+		return nil, err
 	}
+	configOnly := nobus.Is(i2cBus)
 
-	addrStr, _ := params[paramAddress].(string)
-	addr, err := parseAddr(addrStr)
+	addr, err := i2creg.ParseI2CAddress(params[paramAddress])
 	if err != nil {
-		return nil, fmt.Errorf("pcf8575: invalid Address %q: %w", addrStr, err)
+		return nil, fmt.Errorf("pcf8575: Address: %w", err)
 	}
 
 	debug := false
@@ -142,6 +266,83 @@ func (f *factory) NewDriver(params map[string]interface{}, bus interface{}) (hal
 		debug = b
 	}
 
+	rmw := false
+	if v, ok := params[paramReadModifyWrite]; ok {
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("pcf8575: %s must be boolean", paramReadModifyWrite)
+		}
+		rmw = b
+	}
+
+	skipRedundant := true
+	if v, ok := params[paramSkipRedundant]; ok {
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("pcf8575: %s must be boolean", paramSkipRedundant)
+		}
+		skipRedundant = b
+	}
+
+	adoptCurrent := false
+	if v, ok := params[paramAdoptCurrent]; ok {
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("pcf8575: %s must be boolean", paramAdoptCurrent)
+		}
+		adoptCurrent = b
+	}
+
+	// Already validated above. Pin lists are logical; convert them to bits.
+	remapStr, _ := params[paramPinRemap].(string)
+	remap, _ := parsePinRemap(remapStr)
+
+	bidiStr, _ := params[paramBidiPins].(string)
+	bidiMask, _ := parsePinList(bidiStr)
+	bidiMask = remap.physMask(bidiMask)
+	policyStr, _ := params[paramBidiReadPolicy].(string)
+	policy := BidiReadPolicy(strings.ToLower(strings.TrimSpace(policyStr)))
+	if policy == "" {
+		policy = BidiReadRelease
+	}
+	sinkStr, _ := params[paramSinkOnlyPins].(string)
+	sinkMask, _ := parsePinList(sinkStr)
+	sinkMask = remap.physMask(sinkMask)
+	sinkPolicyStr, _ := params[paramSinkOnlyPolicy].(string)
+	sinkPolicy := strings.ToLower(strings.TrimSpace(sinkPolicyStr))
+	if sinkPolicy == "" {
+		sinkPolicy = sinkOnlyWarn
+	}
+
+	outStr, _ := params[paramOutputPins].(string)
+	outMask, _ := parsePinList(outStr)
+	outMask = remap.physMask(outMask)
+
+	identifyPin := -1
+	if v, ok := params[paramIdentifyPin]; ok {
+		identifyPin, _ = hal.ConvertToInt(v)
+	}
+	if identifyPin >= 0 {
+		identifyPin = remap[identifyPin]
+	}
+
+	debounceMs := 0
+	if v, ok := params[paramReadDebounceMs]; ok {
+		debounceMs, _ = hal.ConvertToInt(v)
+	}
+
+	pollInputsMs := 0
+	if v, ok := params[paramPollInputsMs]; ok {
+		pollInputsMs, _ = hal.ConvertToInt(v)
+	}
+	watchStr, _ := params[paramWatchPins].(string)
+	watchLogical, _ := parsePinList(watchStr)
+
+	opTimeoutMs := defaultOpTimeoutMs
+	if v, ok := params[paramOpTimeoutMs]; ok {
+		opTimeoutMs, _ = hal.ConvertToInt(v)
+	}
+
 	// Optional: log config when debug is enabled (keeps journal clean by default).
 	if debug {
 		if b, err := json.MarshalIndent(params, "", "  "); err == nil {
@@ -149,30 +350,85 @@ func (f *factory) NewDriver(params map[string]interface{}, bus interface{}) (hal
 		}
 	}
 
+	// Claim before touching the chip, so a strict conflict leaves it alone.
+	var claim *i2creg.Handle
+	if !configOnly {
+		if claim, err = i2creg.Claim(i2cBus, addr, driverType); err != nil {
+			return nil, err
+		}
+	}
+
 	hw := New(addr, i2cBus)
+	hw.SetTimeout(time.Duration(opTimeoutMs) * time.Millisecond)
+
+	// PollInputsMs: watched pins are inputs from the start (see watch.go), so
+	// AdoptCurrentState never latches them LOW.
+	var watchMask uint16
+	if pollInputsMs > 0 && !configOnly {
+		watchMask = remap.physMask(watchLogical)
+	}
 
 	d := &pcf8575Driver{
 		hwDriver: hw,
 		addr:     addr,
+		claim:    claim,
 		shadow:   0xFFFF, // safe default: release all pins (HIGH/input-ish)
 		invert:   false,  // (kept for future; currently not user-configurable)
+		remap:    remap,
 		debug:    debug,
 		meta:     f.meta,
+
+		readModifyWrite: rmw,
+		inputMask:       watchMask,
+		bidiMask:        bidiMask,
+		bidiPolicy:      policy,
+		readDebounce:    time.Duration(debounceMs) * time.Millisecond,
+		sinkOnlyMask:    sinkMask,
+		sinkOnlyPolicy:  sinkPolicy,
+		outputMask:      outMask,
+		selfTestDwell:   defaultSelfTestDwell,
+		identifyPin:     identifyPin,
+		skipRedundant:   skipRedundant,
+	}
+
+	// AdoptCurrentState: start from the port as it is, so a driver reload does
+	// not glitch outputs that are already driven LOW (see adoptPortLocked).
+	adopted := false
+	if adoptCurrent && !configOnly {
+		adopted = d.adoptPortLocked()
 	}
 
-	// Initialize hardware to safe state (all released/high).
-	// This prevents accidental LOW outputs on boot.
-	if err := d.hwDriver.Write16(d.shadow); err != nil {
-		return nil, fmt.Errorf("pcf8575 addr=0x%02X init write shadow=0x%04X failed: %w", d.addr, d.shadow, err)
+	// Initialize hardware to safe state (all released/high), or rewrite the
+	// adopted state. This prevents accidental LOW outputs on boot. Never skipped:
+	// the chip's latch is unknown until this write lands. Config-only drivers
+	// have no chip.
+	if !configOnly {
+		if err := d.write16Locked(d.shadow); err != nil {
+			claim.Release()
+			return nil, fmt.Errorf("pcf8575 addr=0x%02X init write shadow=0x%04X failed: %w", d.addr, d.shadow, err)
+		}
 	}
 
-	// Create 16 pins (0..15).
+	// Create 16 pins (0..15), each on its remapped bit.
 	for i := 0; i < 16; i++ {
-		d.pins = append(d.pins, &pcf8575Pin{driver: d, pin: i})
+		d.pins = append(d.pins, &pcf8575Pin{driver: d, pin: i, bit: remap[i]})
+	}
+
+	// Config-only drivers have no chip to poll, so no watcher.
+	if pollInputsMs > 0 && !configOnly {
+		var pins []int
+		for pin := 0; pin < 16; pin++ {
+			if watchLogical&(1<<pin) != 0 {
+				pins = append(pins, pin)
+			}
+		}
+		d.watch = newInputWatcher(pins, watchMask, time.Duration(pollInputsMs)*time.Millisecond, d.readDebounce)
+		d.startWatch()
 	}
 
 	if d.debug {
-		log.Printf("pcf8575 init addr=0x%02X shadow=0x%04X (all released/high)", d.addr, d.shadow)
+		log.Printf("pcf8575 init addr=0x%02X shadow=0x%04X adopted=%v rmw=%v bidi=0x%04X policy=%s debounce=%v timeout=%v remap=%v watch=0x%04X/%dms",
+			d.addr, d.shadow, adopted, d.readModifyWrite, d.bidiMask, d.bidiPolicy, d.readDebounce, hw.timeout, remap, watchLogical, pollInputsMs)
 	}
 
 	return d, nil
@@ -11,21 +11,97 @@
 //   - We keep the lock across multi-step operations like "release -> write -> read"
 //     so concurrent outlet writes cannot interleave and break input semantics.
 //
+// Write coalescing:
+//   - BeginBatch()/EndBatch() defer latch writes so bursty pin updates cost a
+//     single Write16. EndBatch (outermost) and Close always flush pending state.
+//
+// Read-modify-write (optional, ReadModifyWrite parameter):
+//   - Default is a blind write of the shadow: one I2C transaction per pin write.
+//   - With RMW, writePin first reads the port and builds the new latch from the
+//     observed levels, so a bit changed by another master (or reset to 1 by a
+//     chip power cycle) is adopted instead of clobbered. Costs an extra Read16
+//     per write, and a read failure fails the write.
+//   - A released output that something external holds LOW reads as 0 and gets
+//     latched LOW. Pins used as inputs (see inputMask) are always kept released.
+//   - Batched writes (BeginBatch/EndBatch) still flush the shadow blindly.
+//
+// Bidirectional pins (optional, BidiPins / BidiReadPolicy parameters):
+//   - A pin is either released (latch bit=1: weak pull-up, usable as input or
+//     HIGH output) or driven (bit=0: strong LOW). Write(true) releases, Write(false)
+//     drives. The shadow is the state: no separate mode is stored.
+//   - Reading needs the pin released. For pins NOT listed in BidiPins, Read()
+//     releases the pin first (legacy behavior, always).
+//   - For pins listed in BidiPins, Read() on a driven pin follows BidiReadPolicy:
+//       "release" - release the pin, then read (the pin stops driving LOW)
+//       "error"   - return ErrPinDriven and leave the pin driven
+//       "latched" - return the driven level (LOW) without any I2C traffic
+//     Read() on a released bidi pin always reads the port.
+//   - ReadDebounceMs waits after a read released a previously driven pin, so the
+//     weak pull-up has time to raise the line before sampling. Writes never wait.
+//
+// Drive mode (optional, SinkOnlyPins / SinkOnlyPolicy parameters):
+//   - The chip can sink ~25mA per pin but only sources ~100µA through the weak
+//     pull-up, so Write(true) cannot power a load wired pin->GND. Loads must sit
+//     between Vcc and the pin and are ON while the pin is driven LOW (use the
+//     outlet's reverse option so "on" writes false).
+//   - Pins default to "quasi-bidirectional" (no checks). Pins listed in
+//     SinkOnlyPins are "sink-only": Write(true) on them is either logged once per
+//     pin ("warn", default) or rejected with ErrSinkOnly ("error", the pin keeps
+//     its state). "error" is for pins that must never be released at runtime.
+//
+// Redundant writes (SkipRedundantWrites parameter, default on):
+//   - A Write16 of the value last written successfully is skipped, so a control
+//     loop re-asserting the same outputs every cycle costs no bus traffic.
+//     Skips are counted in Stats.Skipped. A failed write forgets the last value,
+//     so the next write always goes out.
+//   - Without ReadModifyWrite the driver cannot notice a chip power cycle (latch
+//     back to 0xFFFF); turn the option off if outputs must be re-asserted blindly.
+//
 package pcf8575
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"sort"
 	"sync"
+	"time"
 
+	"github.com/reef-pi/drivers/i2creg"
 	"github.com/reef-pi/hal"
 )
 
-// pcf8575Pin represents one bit on the expander (0..15).
+// driverType is the stable identifier returned by DriverType.
+const driverType = "pcf8575"
+
+// ErrPinDriven is returned by Read() on a driven bidirectional pin when
+// BidiReadPolicy is "error".
+var ErrPinDriven = errors.New("pin is driven low")
+
+// ErrSinkOnly is returned by Write(true) on a sink-only pin when SinkOnlyPolicy is "error".
+var ErrSinkOnly = errors.New("pin is sink-only and cannot source current")
+
+// Sink-only Write(true) handling.
+const (
+	sinkOnlyWarn  = "warn"
+	sinkOnlyError = "error"
+)
+
+// BidiReadPolicy selects what Read() does on a driven bidirectional pin.
+type BidiReadPolicy string
+
+const (
+	BidiReadRelease BidiReadPolicy = "release"
+	BidiReadError   BidiReadPolicy = "error"
+	BidiReadLatched BidiReadPolicy = "latched"
+)
+
+// pcf8575Pin represents one logical pin (0..15) on the expander bit bit
+// (see remap.go).
 type pcf8575Pin struct {
 	driver *pcf8575Driver
 	pin    int
+	bit    int
 }
 
 func (p *pcf8575Pin) Name() string { return fmt.Sprintf("PCF8575:%d", p.pin) }
@@ -33,15 +109,15 @@ func (p *pcf8575Pin) Number() int  { return p.pin }
 func (p *pcf8575Pin) Close() error { return nil }
 
 func (p *pcf8575Pin) Read() (bool, error) {
-	return p.driver.readPin(p.pin)
+	return p.driver.readPin(p.bit)
 }
 
 func (p *pcf8575Pin) Write(b bool) error {
-	return p.driver.writePin(p.pin, b)
+	return p.driver.writePin(p.bit, b)
 }
 
 func (p *pcf8575Pin) LastState() bool {
-	return p.driver.lastLatched(p.pin)
+	return p.driver.lastLatched(p.bit)
 }
 
 // pcf8575Driver is the reef-pi driver instance for one chip at one I2C address.
@@ -51,6 +127,9 @@ type pcf8575Driver struct {
 	// I2C address (for better logs)
 	addr byte
 
+	// claim is this instance's i2creg address claim (nil when config-only).
+	claim *i2creg.Handle
+
 	// Serialize ALL interactions with the chip.
 	mu sync.Mutex
 
@@ -63,16 +142,104 @@ type pcf8575Driver struct {
 	// Not currently exposed in factory parameters (kept for compatibility/future).
 	invert bool
 
+	// remap maps logical pins to physical bits (PinRemap, see remap.go).
+	remap pinRemap
+
 	// debug enables verbose log messages.
 	debug bool
 
 	// meta is provided by factory (so UI name/desc stays consistent).
 	meta hal.Metadata
 
+	// batchDepth > 0 means latch writes are deferred (see BeginBatch).
+	// dirty is set when shadow changed but has not been written yet.
+	batchDepth int
+	dirty      bool
+
+	// stats counts I2C transactions (guarded by mu).
+	stats Stats
+
+	// readModifyWrite merges pin writes into the observed port value.
+	// inputMask marks pins released by readPin; RMW never drives them.
+	readModifyWrite bool
+	inputMask       uint16
+
+	// bidiMask marks pins declared bidirectional; bidiPolicy applies to them.
+	// readDebounce is the settle time after a read releases a driven pin.
+	bidiMask     uint16
+	bidiPolicy   BidiReadPolicy
+	readDebounce time.Duration
+
+	// sinkOnlyMask marks sink-only pins; sinkOnlyPolicy is sinkOnlyWarn or sinkOnlyError.
+	// sinkOnlyWarned remembers which pins were already warned about.
+	sinkOnlyMask   uint16
+	sinkOnlyPolicy string
+	sinkOnlyWarned uint16
+
+	// outputMask declares the output pins walked by SelfTest (0 = SelfTest
+	// refuses to run). selfTestDwell is how long each SelfTest step is held.
+	outputMask    uint16
+	selfTestDwell time.Duration
+
+	// identifyPin is the indicator pin blinked by Identify (-1 = OutputPins).
+	identifyPin int
+
+	// skipRedundant skips a Write16 equal to written, the last latch value
+	// written successfully (valid while writtenValid).
+	skipRedundant bool
+	written       uint16
+	writtenValid  bool
+
+	// resync is set when a write timed out: its bus call may still reach the
+	// chip after the shadow was rolled back, so the shadow is re-asserted
+	// before the next transaction (see write16Locked, read16Locked).
+	resync bool
+
+	// watch polls WatchPins for WatchInputs (PollInputsMs, nil = off, see watch.go).
+	watch *inputWatcher
+
 	pins []*pcf8575Pin
 }
 
-func (d *pcf8575Driver) Close() error { return d.hwDriver.Close() }
+// Stats holds I2C transaction counters for one expander.
+type Stats struct {
+	Writes      uint64    `json:"writes"`
+	Reads       uint64    `json:"reads"`
+	Retries     uint64    `json:"retries"`
+	Skipped     uint64    `json:"skipped"`
+	Errors      uint64    `json:"errors"`
+	Timeouts    uint64    `json:"timeouts"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at,omitempty"`
+
+	// Events and EventsDropped count WatchInputs deliveries (see watch.go).
+	Events        uint64 `json:"events,omitempty"`
+	EventsDropped uint64 `json:"events_dropped,omitempty"`
+
+	// Pins is the per-pin role map (see PinMap).
+	Pins []PinInfo `json:"pins,omitempty"`
+}
+
+// Close stops the input watcher and flushes any deferred latch update before
+// releasing the device.
+func (d *pcf8575Driver) Close() error {
+	d.stopWatch()
+
+	d.mu.Lock()
+	d.batchDepth = 0
+	err := d.flushLocked()
+	d.mu.Unlock()
+
+	if cerr := d.hwDriver.Close(); err == nil {
+		err = cerr
+	}
+	d.claim.Release()
+	return err
+}
+
+// DriverType returns the stable driver kind for host-side routing.
+func (d *pcf8575Driver) DriverType() string { return driverType }
+
 func (d *pcf8575Driver) Metadata() hal.Metadata {
 	if d.meta.Name != "" {
 		return d.meta
@@ -137,6 +304,142 @@ func (d *pcf8575Driver) Pins(cap hal.Capability) ([]hal.Pin, error) {
 	}
 }
 
+// -----------------------------------------------------------------------------
+// Observability
+// -----------------------------------------------------------------------------
+
+// Stats returns a copy of the I2C transaction counters with the current pin map.
+func (d *pcf8575Driver) Stats() Stats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s := d.stats
+	s.Pins = d.pinMapLocked()
+	return s
+}
+
+// write16Locked writes the latch and updates counters. With skipRedundant a
+// write of the value already on the chip is skipped. A timed-out write sets
+// resync. Caller holds d.mu.
+func (d *pcf8575Driver) write16Locked(v uint16) error {
+	if d.skipRedundant && d.writtenValid && d.written == v {
+		d.stats.Skipped++
+		return nil
+	}
+	d.stats.Writes++
+	err := d.hwDriver.Write16(v)
+	if err != nil {
+		d.recordErrorLocked(err)
+		d.writtenValid = false
+		if errors.Is(err, ErrTimeout) {
+			d.resync = true
+		}
+		return err
+	}
+	d.written, d.writtenValid = v, true
+	d.resync = false
+	return nil
+}
+
+// read16Locked reads the port and updates counters. After a timed-out write
+// it first re-asserts the shadow (outside a batch), so a late write cannot
+// leave the chip holding a latch the shadow does not. Caller holds d.mu.
+func (d *pcf8575Driver) read16Locked() (uint16, error) {
+	if d.resync && d.batchDepth == 0 {
+		if err := d.write16Locked(d.shadow); err != nil {
+			return 0, fmt.Errorf("pcf8575 addr=0x%02X re-assert shadow=0x%04X after timeout: %w", d.addr, d.shadow, err)
+		}
+	}
+	d.stats.Reads++
+	v, err := d.hwDriver.Read16()
+	if err != nil {
+		d.recordErrorLocked(err)
+	}
+	return v, err
+}
+
+// adoptPortLocked reads the port and takes it as the shadow, so the init write
+// keeps pins that are already driven LOW. Input, watched and bidirectional
+// pins are always released regardless of their level, and with OutputPins set
+// so is every other non-output pin: an input held LOW externally must not be
+// latched LOW. On a read error the safe 0xFFFF default stays. Caller holds
+// d.mu (or owns d exclusively during construction).
+func (d *pcf8575Driver) adoptPortLocked() bool {
+	v, err := d.read16Locked()
+	if err != nil {
+		log.Printf("pcf8575 addr=0x%02X AdoptCurrentState: read16 failed, releasing all pins: %v", d.addr, err)
+		return false
+	}
+	d.shadow = v | d.releaseMaskLocked()
+	return true
+}
+
+// releaseMaskLocked returns the pins that must never be latched LOW from a
+// port read: input, watched and bidirectional pins, plus every non-output pin
+// when OutputPins is set. Caller holds d.mu.
+func (d *pcf8575Driver) releaseMaskLocked() uint16 {
+	m := d.inputMask | d.bidiMask
+	if d.outputMask != 0 {
+		m |= ^d.outputMask
+	}
+	return m
+}
+
+func (d *pcf8575Driver) recordErrorLocked(err error) {
+	d.stats.Errors++
+	if errors.Is(err, ErrTimeout) || errors.Is(err, ErrBusy) {
+		d.stats.Timeouts++
+	}
+	d.stats.LastError = err.Error()
+	d.stats.LastErrorAt = time.Now()
+}
+
+// -----------------------------------------------------------------------------
+// Write coalescing
+// -----------------------------------------------------------------------------
+
+// BeginBatch starts deferring latch writes. Pin writes only update the shadow
+// until the matching EndBatch. Calls may be nested; only the outermost
+// EndBatch flushes.
+func (d *pcf8575Driver) BeginBatch() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.batchDepth++
+	if d.debug {
+		log.Printf("pcf8575 addr=0x%02X begin batch depth=%d", d.addr, d.batchDepth)
+	}
+}
+
+// EndBatch ends a batch started by BeginBatch. When the outermost batch ends,
+// any pending shadow change is written with a single Write16.
+func (d *pcf8575Driver) EndBatch() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.batchDepth > 0 {
+		d.batchDepth--
+	}
+	if d.debug {
+		log.Printf("pcf8575 addr=0x%02X end batch depth=%d dirty=%v", d.addr, d.batchDepth, d.dirty)
+	}
+	if d.batchDepth > 0 {
+		return nil
+	}
+	return d.flushLocked()
+}
+
+// flushLocked writes the shadow if it has pending changes. Caller holds d.mu.
+func (d *pcf8575Driver) flushLocked() error {
+	if !d.dirty && !d.resync {
+		return nil
+	}
+	if err := d.write16Locked(d.shadow); err != nil {
+		return fmt.Errorf("pcf8575 addr=0x%02X flush: write shadow=0x%04X failed: %w", d.addr, d.shadow, err)
+	}
+	d.dirty = false
+	return nil
+}
+
 // -----------------------------------------------------------------------------
 // Internal helpers
 // -----------------------------------------------------------------------------
@@ -164,43 +467,68 @@ func (d *pcf8575Driver) lastLatched(pin int) bool {
 // Atomicity:
 //   We keep the lock held across release->write->read so concurrent writes
 //   cannot change shadow or outputs mid-read.
-func (d *pcf8575Driver) readPin(pin int) (bool, error) {
-	if pin < 0 || pin > 15 {
-		return false, fmt.Errorf("pcf8575 addr=0x%02X: read invalid pin=%d", d.addr, pin)
+func (d *pcf8575Driver) readPin(bit int) (bool, error) {
+	if bit < 0 || bit > 15 {
+		return false, fmt.Errorf("pcf8575 addr=0x%02X: read invalid bit=%d", d.addr, bit)
 	}
 
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	mask := uint16(1 << pin)
+	mask := uint16(1 << bit)
+	driven := d.shadow&mask == 0
+
+	if driven && d.bidiMask&mask != 0 {
+		switch d.bidiPolicy {
+		case BidiReadError:
+			return false, fmt.Errorf("pcf8575 addr=0x%02X read %s: %w (write true to release it first)",
+				d.addr, d.pinLabel(bit), ErrPinDriven)
+		case BidiReadLatched:
+			if d.debug {
+				log.Printf("pcf8575 addr=0x%02X read %s: driven bidi pin, returning latched LOW", d.addr, d.pinLabel(bit))
+			}
+			return false, nil
+		}
+	}
 
 	// Release pin for input semantics.
-	prevShadow := d.shadow
+	prevShadow, prevInputMask := d.shadow, d.inputMask
 	d.shadow |= mask
+	d.inputMask |= mask
 
 	if d.debug {
-		log.Printf("pcf8575 addr=0x%02X read pin=%d: release bit (shadow 0x%04X -> 0x%04X)",
-			d.addr, pin, prevShadow, d.shadow)
+		log.Printf("pcf8575 addr=0x%02X read %s: release bit (shadow 0x%04X -> 0x%04X)",
+			d.addr, d.pinLabel(bit), prevShadow, d.shadow)
 	}
 
 	// Apply shadow to hardware before reading.
-	if err := d.hwDriver.Write16(d.shadow); err != nil {
-		return false, fmt.Errorf("pcf8575 addr=0x%02X read pin=%d: write shadow=0x%04X failed: %w",
-			d.addr, pin, d.shadow, err)
+	// This also flushes any pending batched writes.
+	if err := d.write16Locked(d.shadow); err != nil {
+		// The chip still holds the old latch; keep shadow in step with it.
+		failed := d.shadow
+		d.shadow = prevShadow
+		d.inputMask = prevInputMask
+		return false, fmt.Errorf("pcf8575 addr=0x%02X read %s: write shadow=0x%04X failed: %w",
+			d.addr, d.pinLabel(bit), failed, err)
+	}
+	d.dirty = false
+
+	if driven && d.readDebounce > 0 {
+		time.Sleep(d.readDebounce)
 	}
 
 	// Read current port level.
-	v, err := d.hwDriver.Read16()
+	v, err := d.read16Locked()
 	if err != nil {
-		return false, fmt.Errorf("pcf8575 addr=0x%02X read pin=%d: read16 failed: %w",
-			d.addr, pin, err)
+		return false, fmt.Errorf("pcf8575 addr=0x%02X read %s: read16 failed: %w",
+			d.addr, d.pinLabel(bit), err)
 	}
 
 	level := (v & mask) != 0
 
 	if d.debug {
-		log.Printf("pcf8575 addr=0x%02X read pin=%d: port=0x%04X level=%v (shadow=0x%04X)",
-			d.addr, pin, v, level, d.shadow)
+		log.Printf("pcf8575 addr=0x%02X read %s: port=0x%04X level=%v (shadow=0x%04X)",
+			d.addr, d.pinLabel(bit), v, level, d.shadow)
 	}
 
 	return level, nil
@@ -215,9 +543,9 @@ func (d *pcf8575Driver) readPin(pin int) (bool, error) {
 // If invert=true (active-low):
 //   - on=true  => drive low (bit=0)
 //   - on=false => release/high (bit=1)
-func (d *pcf8575Driver) writePin(pin int, on bool) error {
-	if pin < 0 || pin > 15 {
-		return fmt.Errorf("pcf8575 addr=0x%02X: write invalid pin=%d", d.addr, pin)
+func (d *pcf8575Driver) writePin(bit int, on bool) error {
+	if bit < 0 || bit > 15 {
+		return fmt.Errorf("pcf8575 addr=0x%02X: write invalid bit=%d", d.addr, bit)
 	}
 
 	released := on
@@ -226,20 +554,71 @@ func (d *pcf8575Driver) writePin(pin int, on bool) error {
 	}
 
 	if d.debug {
-		log.Printf("pcf8575 addr=0x%02X write pin=%d on=%v invert=%v => released(bit=1)=%v",
-			d.addr, pin, on, d.invert, released)
+		log.Printf("pcf8575 addr=0x%02X write %s on=%v invert=%v => released(bit=1)=%v",
+			d.addr, d.pinLabel(bit), on, d.invert, released)
 	}
 
-	return d.setBitReleased(pin, released)
+	if released {
+		if err := d.checkSinkOnly(bit); err != nil {
+			return err
+		}
+	}
+
+	return d.setBitReleased(bit, released)
+}
+
+// checkSinkOnly applies SinkOnlyPolicy to releasing a sink-only pin.
+func (d *pcf8575Driver) checkSinkOnly(bit int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	mask := uint16(1 << bit)
+	if d.sinkOnlyMask&mask == 0 {
+		return nil
+	}
+	if d.sinkOnlyPolicy == sinkOnlyError {
+		return fmt.Errorf("pcf8575 addr=0x%02X write %s high: %w (wire the load Vcc->pin and drive LOW for on)",
+			d.addr, d.pinLabel(bit), ErrSinkOnly)
+	}
+	if d.sinkOnlyWarned&mask == 0 {
+		d.sinkOnlyWarned |= mask
+		log.Printf("pcf8575 addr=0x%02X WARNING: %s is sink-only; writing high only releases it (weak ~100µA pull-up). Wire loads Vcc->pin and drive LOW for on.",
+			d.addr, d.pinLabel(bit))
+	}
+	return nil
 }
 
 // setBitReleased updates shadow and writes the full 16-bit value to the chip.
-func (d *pcf8575Driver) setBitReleased(pin int, released bool) error {
+// Inside a batch the write is deferred until EndBatch. In RMW mode the shadow
+// is first rebuilt from the observed port value; pins in releaseMaskLocked that
+// the shadow holds released stay released even when they read LOW. If the
+// read or write fails the shadow is rolled back, so it never claims a latch
+// the chip does not hold.
+func (d *pcf8575Driver) setBitReleased(bit int, released bool) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	mask := uint16(1 << pin)
-	prev := d.shadow
+	mask := uint16(1 << bit)
+	prev, prevInputMask := d.shadow, d.inputMask
+
+	if !released {
+		// Driving LOW makes this pin an output.
+		d.inputMask &^= mask
+	}
+
+	if d.readModifyWrite && d.batchDepth == 0 {
+		v, err := d.read16Locked()
+		if err != nil {
+			d.inputMask = prevInputMask
+			return fmt.Errorf("pcf8575 addr=0x%02X write %s: rmw read16 failed: %w", d.addr, d.pinLabel(bit), err)
+		}
+		merged := v | (d.releaseMaskLocked() & prev)
+		if d.debug && merged&^mask != prev&^mask {
+			log.Printf("pcf8575 addr=0x%02X rmw %s: port=0x%04X differs from shadow=0x%04X (other bits adopted)",
+				d.addr, d.pinLabel(bit), v, prev)
+		}
+		d.shadow = merged
+	}
 
 	if released {
 		d.shadow |= mask
@@ -248,13 +627,23 @@ func (d *pcf8575Driver) setBitReleased(pin int, released bool) error {
 	}
 
 	if d.debug {
-		log.Printf("pcf8575 addr=0x%02X latch pin=%d released=%v: shadow 0x%04X -> 0x%04X",
-			d.addr, pin, released, prev, d.shadow)
+		log.Printf("pcf8575 addr=0x%02X latch %s released=%v: shadow 0x%04X -> 0x%04X",
+			d.addr, d.pinLabel(bit), released, prev, d.shadow)
+	}
+
+	if d.batchDepth > 0 {
+		if d.shadow != prev {
+			d.dirty = true
+		}
+		return nil
 	}
 
-	if err := d.hwDriver.Write16(d.shadow); err != nil {
-		return fmt.Errorf("pcf8575 addr=0x%02X write pin=%d: write shadow=0x%04X failed: %w",
-			d.addr, pin, d.shadow, err)
+	if err := d.write16Locked(d.shadow); err != nil {
+		// Roll back so later operations start from what the chip actually holds.
+		failed := d.shadow
+		d.shadow, d.inputMask = prev, prevInputMask
+		return fmt.Errorf("pcf8575 addr=0x%02X write %s: write shadow=0x%04X failed (shadow kept 0x%04X): %w",
+			d.addr, d.pinLabel(bit), failed, prev, err)
 	}
 
 	return nil
@@ -0,0 +1,801 @@
+package pcf8575
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordingBus is an i2c.Bus that records every write.
+// A non-nil writeErr fails writes without recording them; readErr fails reads.
+type recordingBus struct {
+	writes   [][]byte
+	port     []byte
+	writeErr error
+	readErr  error
+}
+
+func (b *recordingBus) SetAddress(_ byte) error { return nil }
+func (b *recordingBus) ReadBytes(_ byte, n int) ([]byte, error) {
+	if b.readErr != nil {
+		return nil, b.readErr
+	}
+	if b.port == nil {
+		return make([]byte, n), nil
+	}
+	return b.port, nil
+}
+func (b *recordingBus) WriteBytes(_ byte, v []byte) error {
+	if b.writeErr != nil {
+		return b.writeErr
+	}
+	b.writes = append(b.writes, append([]byte(nil), v...))
+	return nil
+}
+func (b *recordingBus) ReadFromReg(_, _ byte, _ []byte) error { return nil }
+func (b *recordingBus) WriteToReg(_, _ byte, _ []byte) error  { return nil }
+func (b *recordingBus) Close() error                          { return nil }
+
+func newTestDriver(t *testing.T, params map[string]interface{}) (*pcf8575Driver, *recordingBus) {
+	t.Helper()
+	bus := &recordingBus{}
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	if _, ok := params[paramAddress]; !ok {
+		params[paramAddress] = "0x20"
+	}
+	d, err := Factory().NewDriver(params, bus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus.writes = nil
+	return d.(*pcf8575Driver), bus
+}
+
+func TestBatchCoalescesWrites(t *testing.T) {
+	d, bus := newTestDriver(t, nil)
+
+	d.BeginBatch()
+	for i := 0; i < 4; i++ {
+		if err := d.writePin(i, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(bus.writes) != 0 {
+		t.Errorf("expected no writes inside batch, got %d", len(bus.writes))
+	}
+	if err := d.EndBatch(); err != nil {
+		t.Fatal(err)
+	}
+	if len(bus.writes) != 1 {
+		t.Fatalf("expected 1 write after EndBatch, got %d", len(bus.writes))
+	}
+	if w := bus.writes[0]; w[0] != 0xF0 || w[1] != 0xFF {
+		t.Errorf("unexpected latch % X", w)
+	}
+}
+
+func TestCloseFlushesPendingBatch(t *testing.T) {
+	d, bus := newTestDriver(t, nil)
+
+	d.BeginBatch()
+	d.BeginBatch()
+	if err := d.writePin(15, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.EndBatch(); err != nil {
+		t.Fatal(err)
+	}
+	if len(bus.writes) != 0 {
+		t.Errorf("inner EndBatch should not flush, got %d writes", len(bus.writes))
+	}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(bus.writes) != 1 {
+		t.Errorf("expected Close to flush once, got %d writes", len(bus.writes))
+	}
+}
+
+func TestStatsCountTransactions(t *testing.T) {
+	d, _ := newTestDriver(t, nil)
+
+	if err := d.writePin(3, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.readPin(4); err != nil {
+		t.Fatal(err)
+	}
+	s := d.Stats()
+	// init write + pin write; read's release write repeats the latch and is skipped
+	if s.Writes != 2 || s.Skipped != 1 {
+		t.Errorf("expected 2 writes and 1 skipped, got %d and %d", s.Writes, s.Skipped)
+	}
+	if s.Reads != 1 {
+		t.Errorf("expected 1 read, got %d", s.Reads)
+	}
+	if s.Errors != 0 || s.LastError != "" {
+		t.Errorf("unexpected errors: %+v", s)
+	}
+}
+
+func TestReadModifyWriteAdoptsPortValue(t *testing.T) {
+	d, bus := newTestDriver(t, map[string]interface{}{paramReadModifyWrite: true})
+
+	// Pin 8 was read as an input; another master drove pin 1 LOW.
+	if _, err := d.readPin(8); err != nil {
+		t.Fatal(err)
+	}
+	bus.port = []byte{0xFD, 0xFE} // pin 1 low, pin 8 pulled low externally
+	bus.writes = nil
+
+	if err := d.writePin(3, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(bus.writes) != 1 {
+		t.Fatalf("expected 1 write, got %d", len(bus.writes))
+	}
+	// pin 1 kept low, pin 3 driven low, input pin 8 kept released
+	if w := bus.writes[0]; w[0] != 0xF5 || w[1] != 0xFF {
+		t.Errorf("unexpected latch % X", w)
+	}
+}
+
+func TestReadModifyWriteKeepsReleasedBidiPin(t *testing.T) {
+	d, bus := newTestDriver(t, map[string]interface{}{
+		paramReadModifyWrite: true,
+		paramBidiPins:        "2",
+	})
+
+	// Bidi pin 2 is released but held LOW by the other side.
+	bus.port = []byte{0xFB, 0xFF}
+	if err := d.writePin(3, false); err != nil {
+		t.Fatal(err)
+	}
+	if w := bus.writes[len(bus.writes)-1]; w[0] != 0xF7 || w[1] != 0xFF {
+		t.Errorf("latch % X, want F7 FF (pin 2 released)", w)
+	}
+
+	// A bidi pin the driver holds LOW is adopted as driven.
+	if err := d.writePin(2, false); err != nil {
+		t.Fatal(err)
+	}
+	bus.port = []byte{0xF3, 0xFF}
+	if err := d.writePin(5, false); err != nil {
+		t.Fatal(err)
+	}
+	if w := bus.writes[len(bus.writes)-1]; w[0] != 0xD3 || w[1] != 0xFF {
+		t.Errorf("latch % X, want D3 FF", w)
+	}
+}
+
+func TestReadModifyWriteReadFailureKeepsInputMask(t *testing.T) {
+	d, bus := newTestDriver(t, map[string]interface{}{paramReadModifyWrite: true})
+	if _, err := d.readPin(4); err != nil {
+		t.Fatal(err)
+	}
+
+	bus.readErr = errors.New("remote i/o error")
+	if err := d.writePin(4, false); err == nil {
+		t.Fatal("expected rmw read error")
+	}
+	if d.inputMask&(1<<4) == 0 {
+		t.Error("failed write must leave pin 4 an input")
+	}
+	if d.shadow != 0xFFFF {
+		t.Errorf("shadow 0x%04X, want 0xFFFF", d.shadow)
+	}
+}
+
+func TestAdoptCurrentState(t *testing.T) {
+	// Pins 0 and 9 are driven LOW from before the reload; pin 4 (an input)
+	// reads LOW because something outside pulls it down.
+	bus := &recordingBus{port: []byte{0xEE, 0xFD}}
+	params := map[string]interface{}{
+		paramAddress:      "0x20",
+		paramAdoptCurrent: true,
+		paramOutputPins:   "0-3,8-15",
+	}
+	d, err := Factory().NewDriver(params, bus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	if len(bus.writes) != 1 {
+		t.Fatalf("expected 1 init write, got %d", len(bus.writes))
+	}
+	// Outputs 0 and 9 stay LOW; input pin 4 is released.
+	if w := bus.writes[0]; w[0] != 0xFE || w[1] != 0xFD {
+		t.Errorf("init latch % X, want FE FD", w)
+	}
+	if s := d.(*pcf8575Driver).shadow; s != 0xFDFE {
+		t.Errorf("shadow 0x%04X, want 0xFDFE", s)
+	}
+}
+
+func TestAdoptCurrentStateReleasesInputs(t *testing.T) {
+	// No OutputPins: pin 2 is bidirectional, pin 6 watched and pin 9 an
+	// ordinary output, all reading LOW.
+	bus := &recordingBus{port: []byte{0xBB, 0xFD}}
+	params := map[string]interface{}{
+		paramAddress:      "0x20",
+		paramAdoptCurrent: true,
+		paramBidiPins:     "2",
+		paramWatchPins:    "6",
+		paramPollInputsMs: 1000,
+	}
+	d, err := Factory().NewDriver(params, bus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	// Only the output pin 9 stays LOW.
+	if s := d.(*pcf8575Driver).shadow; s != 0xFDFF {
+		t.Errorf("shadow 0x%04X, want 0xFDFF", s)
+	}
+}
+
+func TestBidiReadPolicy(t *testing.T) {
+	d, bus := newTestDriver(t, map[string]interface{}{
+		paramBidiPins:       "2,4-5",
+		paramBidiReadPolicy: "error",
+	})
+	if err := d.writePin(4, false); err != nil {
+		t.Fatal(err)
+	}
+	bus.writes = nil
+
+	if _, err := d.readPin(4); !errors.Is(err, ErrPinDriven) {
+		t.Fatalf("expected ErrPinDriven, got %v", err)
+	}
+	if len(bus.writes) != 0 || d.lastLatched(4) {
+		t.Errorf("driven pin must stay driven, writes=%d", len(bus.writes))
+	}
+
+	d.bidiPolicy = BidiReadLatched
+	if level, err := d.readPin(4); err != nil || level {
+		t.Errorf("expected latched LOW, got %v %v", level, err)
+	}
+	if len(bus.writes) != 0 {
+		t.Errorf("latched policy must not touch the bus, writes=%d", len(bus.writes))
+	}
+
+	// Pins outside BidiPins keep the release-to-read behavior.
+	if err := d.writePin(7, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.readPin(7); err != nil {
+		t.Fatal(err)
+	}
+	if !d.lastLatched(7) {
+		t.Error("non-bidi pin should be released by Read")
+	}
+}
+
+func TestWriteFailureRollsBackShadow(t *testing.T) {
+	d, bus := newTestDriver(t, nil)
+	if err := d.writePin(0, false); err != nil {
+		t.Fatal(err)
+	}
+
+	bus.writeErr = errors.New("remote i/o error")
+	if err := d.writePin(5, false); err == nil {
+		t.Fatal("expected write error")
+	}
+	if _, err := d.readPin(0); err == nil || !strings.Contains(err.Error(), "write shadow=0xFFFF failed") {
+		t.Fatalf("expected read error naming the failed latch 0xFFFF, got %v", err)
+	}
+	if d.shadow != 0xFFFE {
+		t.Errorf("shadow should match last good latch 0xFFFE, got 0x%04X", d.shadow)
+	}
+
+	bus.writeErr = nil
+	if err := d.writePin(1, false); err != nil {
+		t.Fatal(err)
+	}
+	if w := bus.writes[len(bus.writes)-1]; w[0] != 0xFC || w[1] != 0xFF {
+		t.Errorf("unexpected latch % X", w)
+	}
+	if s := d.Stats(); s.Errors != 2 {
+		t.Errorf("expected 2 errors, got %d", s.Errors)
+	}
+}
+
+func TestSinkOnlyPolicy(t *testing.T) {
+	d, bus := newTestDriver(t, map[string]interface{}{
+		paramSinkOnlyPins:   "0-3",
+		paramSinkOnlyPolicy: "error",
+	})
+	if err := d.writePin(1, false); err != nil {
+		t.Fatal(err)
+	}
+	bus.writes = nil
+
+	if err := d.writePin(1, true); !errors.Is(err, ErrSinkOnly) {
+		t.Fatalf("expected ErrSinkOnly, got %v", err)
+	}
+	if len(bus.writes) != 0 || d.lastLatched(1) {
+		t.Error("rejected write must leave the pin driven")
+	}
+	if err := d.writePin(8, true); err != nil {
+		t.Errorf("pin outside SinkOnlyPins: %v", err)
+	}
+
+	d.sinkOnlyPolicy = sinkOnlyWarn
+	if err := d.writePin(1, true); err != nil {
+		t.Errorf("warn policy should allow the write: %v", err)
+	}
+}
+
+func TestSelfTestWalksOutputsAndRestores(t *testing.T) {
+	d, bus := newTestDriver(t, map[string]interface{}{paramOutputPins: "0-2"})
+	d.selfTestDwell = 0
+	if err := d.writePin(9, false); err != nil {
+		t.Fatal(err)
+	}
+	bus.writes = nil
+
+	if err := d.SelfTest(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// pins 0..2 LOW in turn with pin 9 kept driven, then restore
+	want := [][2]byte{{0xFE, 0xFD}, {0xFD, 0xFD}, {0xFB, 0xFD}, {0xFF, 0xFD}}
+	if len(bus.writes) != len(want) {
+		t.Fatalf("expected %d writes, got %d", len(want), len(bus.writes))
+	}
+	for i, w := range want {
+		if bus.writes[i][0] != w[0] || bus.writes[i][1] != w[1] {
+			t.Errorf("write %d: got % X, want % X", i, bus.writes[i], w)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bus.writes = nil
+	if err := d.SelfTest(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	// Cancelled before the first step: the latch never changed, so the
+	// restore write is redundant and skipped.
+	if len(bus.writes) != 0 || d.written != 0xFDFF {
+		t.Errorf("cancelled self-test must leave latch 0xFDFF, writes=%d latch=0x%04X", len(bus.writes), d.written)
+	}
+}
+
+func TestSelfTestRequiresOutputPins(t *testing.T) {
+	d, bus := newTestDriver(t, nil)
+	d.selfTestDwell = 0
+	if err := d.SelfTest(context.Background()); !errors.Is(err, ErrNoOutputPins) {
+		t.Fatalf("expected ErrNoOutputPins, got %v", err)
+	}
+	if len(bus.writes) != 0 {
+		t.Errorf("self-test without OutputPins must not write, got %d writes", len(bus.writes))
+	}
+}
+
+func TestIdentifyBlinksIndicatorAndRestores(t *testing.T) {
+	d, bus := newTestDriver(t, map[string]interface{}{paramIdentifyPin: 4})
+	if err := d.writePin(0, false); err != nil {
+		t.Fatal(err)
+	}
+	bus.writes = nil
+
+	if err := d.Identify(context.Background(), time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if len(bus.writes) != 2 {
+		t.Fatalf("expected blink + restore writes, got %d", len(bus.writes))
+	}
+	// pin 4 driven (blink on) with pin 0 kept driven, then the original latch
+	if w := bus.writes[0]; w[0] != 0xEE || w[1] != 0xFF {
+		t.Errorf("unexpected blink latch % X", w)
+	}
+	if w := bus.writes[1]; w[0] != 0xFE || w[1] != 0xFF {
+		t.Errorf("unexpected restore latch % X", w)
+	}
+}
+
+func TestIdentifyWithoutOutputsOrIndicator(t *testing.T) {
+	d, bus := newTestDriver(t, nil)
+	if err := d.Identify(context.Background(), time.Millisecond); !errors.Is(err, ErrNoOutputPins) {
+		t.Fatalf("expected ErrNoOutputPins, got %v", err)
+	}
+	if len(bus.writes) != 0 {
+		t.Errorf("identify without outputs must not write, got %d writes", len(bus.writes))
+	}
+}
+
+func TestIdentifyReleasesLockAndHonoursCtx(t *testing.T) {
+	d, bus := newTestDriver(t, map[string]interface{}{paramOutputPins: "0-1"})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- d.Identify(ctx, time.Minute) }()
+
+	// An outlet write must not wait for the blink to finish.
+	wrote := make(chan error, 1)
+	go func() { wrote <- d.writePin(5, false) }()
+	select {
+	case err := <-wrote:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("writePin blocked while Identify was running")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Identify did not return after cancel")
+	}
+	// restored to the shadow, which now includes pin 5
+	if w := bus.writes[len(bus.writes)-1]; w[0] != 0xDF || w[1] != 0xFF {
+		t.Errorf("unexpected restore latch % X", w)
+	}
+}
+
+func TestSkipRedundantWrites(t *testing.T) {
+	d, bus := newTestDriver(t, nil)
+
+	for i := 0; i < 3; i++ {
+		if err := d.writePin(2, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(bus.writes) != 1 {
+		t.Fatalf("expected 1 write for a repeated state, got %d", len(bus.writes))
+	}
+	if s := d.Stats(); s.Skipped != 2 {
+		t.Errorf("expected 2 skipped writes, got %d", s.Skipped)
+	}
+
+	// A failed write forgets the chip state; the retry must go out.
+	bus.writeErr = errors.New("remote i/o error")
+	if err := d.writePin(2, true); err == nil {
+		t.Fatal("expected write error")
+	}
+	bus.writeErr = nil
+	bus.writes = nil
+	if err := d.writePin(2, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(bus.writes) != 1 {
+		t.Errorf("expected the write after a failure to go out, got %d", len(bus.writes))
+	}
+
+	d, bus = newTestDriver(t, map[string]interface{}{paramSkipRedundant: false})
+	for i := 0; i < 3; i++ {
+		if err := d.writePin(2, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(bus.writes) != 3 {
+		t.Errorf("expected blind writes with SkipRedundantWrites off, got %d", len(bus.writes))
+	}
+}
+
+func TestPinMapReportsRoles(t *testing.T) {
+	d, _ := newTestDriver(t, map[string]interface{}{
+		paramOutputPins:   "0-3",
+		paramBidiPins:     "4",
+		paramSinkOnlyPins: "1",
+	})
+	if err := d.writePin(1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	pins := d.Stats().Pins
+	if len(pins) != 16 {
+		t.Fatalf("expected 16 pins, got %d", len(pins))
+	}
+	want := map[int]string{0: pinRoleOutput, 1: pinRoleOutput, 4: pinRoleBidi, 9: pinRoleInput}
+	for pin, role := range want {
+		if pins[pin].Role != role {
+			t.Errorf("pin %d: role %q, want %q", pin, pins[pin].Role, role)
+		}
+	}
+	if p := pins[1]; !p.SinkOnly || !p.Driven || p.Name != "PCF8575:1" {
+		t.Errorf("unexpected pin 1 info %+v", p)
+	}
+	if pins[0].Driven || pins[0].SinkOnly {
+		t.Errorf("unexpected pin 0 info %+v", pins[0])
+	}
+
+	// Without OutputPins, pins become inputs once read.
+	d, _ = newTestDriver(t, nil)
+	if _, err := d.readPin(7); err != nil {
+		t.Fatal(err)
+	}
+	pins = d.PinMap()
+	if pins[7].Role != pinRoleInput || pins[6].Role != pinRoleOutput {
+		t.Errorf("unexpected roles %q/%q", pins[7].Role, pins[6].Role)
+	}
+}
+
+// stuckBus blocks reads until release is closed, like a bus held by a
+// clock-stretching device.
+type stuckBus struct {
+	recordingBus
+	release chan struct{}
+}
+
+func (b *stuckBus) ReadBytes(addr byte, n int) ([]byte, error) {
+	<-b.release
+	return b.recordingBus.ReadBytes(addr, n)
+}
+
+func TestOpTimeout(t *testing.T) {
+	bus := &stuckBus{release: make(chan struct{})}
+	p := New(0x20, bus)
+	p.SetTimeout(10 * time.Millisecond)
+
+	if _, err := p.Read16(); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("stuck read: got %v, want ErrTimeout", err)
+	}
+	if err := p.Write16(0xFFFF); !errors.Is(err, ErrBusy) {
+		t.Fatalf("write behind stuck read: got %v, want ErrBusy", err)
+	}
+
+	close(bus.release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		_, err := p.Read16()
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("read after release: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// stuckWriteBus blocks writes while hold is open.
+type stuckWriteBus struct {
+	recordingBus
+	hold chan struct{}
+}
+
+func (b *stuckWriteBus) WriteBytes(addr byte, v []byte) error {
+	if b.hold != nil {
+		<-b.hold
+	}
+	return b.recordingBus.WriteBytes(addr, v)
+}
+
+func TestTimedOutWriteReasserted(t *testing.T) {
+	bus := &stuckWriteBus{}
+	drv, err := Factory().NewDriver(map[string]interface{}{
+		paramAddress:       "0x20",
+		paramOpTimeoutMs:   10,
+		paramSkipRedundant: true,
+	}, bus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := drv.(*pcf8575Driver)
+	defer d.Close()
+
+	bus.hold = make(chan struct{})
+	if err := d.writePin(3, false); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("got %v, want ErrTimeout", err)
+	}
+	if d.shadow != 0xFFFF {
+		t.Errorf("shadow 0x%04X, want rolled back 0xFFFF", d.shadow)
+	}
+
+	// The stuck write lands late; the next transaction undoes it.
+	close(bus.hold)
+	deadline := time.Now().Add(time.Second)
+	for {
+		d.mu.Lock()
+		_, err := d.read16Locked()
+		d.mu.Unlock()
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("read after release: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	n := len(bus.writes)
+	if n < 2 || bus.writes[n-2][0] != 0xF7 || bus.writes[n-1][0] != 0xFF || bus.writes[n-1][1] != 0xFF {
+		t.Fatalf("writes % X, want the late F7 FF then FF FF", bus.writes)
+	}
+	if d.resync {
+		t.Error("resync should clear once the shadow is re-asserted")
+	}
+}
+
+func TestReadPinsSingleTransaction(t *testing.T) {
+	d, bus := newTestDriver(t, nil)
+	if err := d.writePin(0, false); err != nil {
+		t.Fatal(err)
+	}
+	bus.writes = nil
+	bus.port = []byte{0b1010_0101, 0x00}
+	before := d.Stats()
+
+	levels, err := d.ReadPins([]int{0, 1, 2, 5, 5, 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int]bool{0: true, 1: false, 2: true, 5: true, 8: false}
+	if len(levels) != len(want) {
+		t.Fatalf("levels = %v, want %v", levels, want)
+	}
+	for pin, level := range want {
+		if levels[pin] != level {
+			t.Errorf("pin %d = %v, want %v", pin, levels[pin], level)
+		}
+	}
+	if s := d.Stats(); s.Writes-before.Writes != 1 || s.Reads-before.Reads != 1 {
+		t.Errorf("expected 1 write and 1 read, got %d and %d", s.Writes-before.Writes, s.Reads-before.Reads)
+	}
+	if !d.lastLatched(0) {
+		t.Error("driven pin 0 should be released by ReadPins")
+	}
+
+	if _, err := d.ReadPins([]int{3, 16}); err == nil {
+		t.Error("expected an error for pin 16")
+	}
+
+	// A failed release write names the latch it tried, not the rolled-back one.
+	if err := d.writePin(3, false); err != nil {
+		t.Fatal(err)
+	}
+	bus.writeErr = errors.New("remote i/o error")
+	if _, err := d.ReadPins([]int{3}); err == nil || !strings.Contains(err.Error(), "write shadow=0xFFFF failed") {
+		t.Errorf("expected error naming 0xFFFF, got %v", err)
+	}
+	if d.shadow != 0xFFF7 {
+		t.Errorf("shadow 0x%04X, want rolled back 0xFFF7", d.shadow)
+	}
+}
+
+func TestPinRemap(t *testing.T) {
+	d, bus := newTestDriver(t, map[string]interface{}{
+		paramPinRemap:     "7-0",
+		paramSinkOnlyPins: "0",
+	})
+
+	// Logical pin 0 drives P7; pin 8 is outside the list and keeps its bit.
+	if err := d.pins[0].Write(false); err != nil {
+		t.Fatal(err)
+	}
+	if got := bus.writes[len(bus.writes)-1]; got[0] != 0x7F || got[1] != 0xFF {
+		t.Fatalf("logical pin 0 low: wrote % X, want 7F FF", got)
+	}
+	if d.sinkOnlyMask != 1<<7 {
+		t.Errorf("SinkOnlyPins not remapped: mask=0x%04X", d.sinkOnlyMask)
+	}
+	if p := d.PinMap()[0]; p.Bit != 7 || !p.Driven || !p.SinkOnly {
+		t.Errorf("unexpected pin 0 info %+v", p)
+	}
+	if d.pins[8].bit != 8 {
+		t.Errorf("pin 8 bit = %d, want 8", d.pins[8].bit)
+	}
+
+	bus.port = []byte{0b0000_0001, 0x00}
+	levels, err := d.ReadPins([]int{0, 7})
+	if err != nil || levels[0] || !levels[7] {
+		t.Errorf("ReadPins = %v, %v; want 0:false 7:true", levels, err)
+	}
+
+	// Messages name the logical pin the user configured, not the bit.
+	d.sinkOnlyPolicy = sinkOnlyError
+	if err := d.pins[0].Write(true); err == nil || !strings.Contains(err.Error(), "pin=0 (bit=7)") {
+		t.Errorf("sink-only error should name logical pin 0 on bit 7: %v", err)
+	}
+	if got := d.pinLabel(8); got != "pin=8" {
+		t.Errorf("pinLabel(8) = %q, want pin=8", got)
+	}
+
+	for _, bad := range []string{"0,0", "1,2", "16", "3-0,3"} {
+		if ok, _ := Factory().ValidateParameters(map[string]interface{}{paramAddress: "0x20", paramPinRemap: bad}); ok {
+			t.Errorf("PinRemap %q should fail validation", bad)
+		}
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	d, bus := newTestDriver(t, nil)
+	if err := d.writePin(3, false); err != nil {
+		t.Fatal(err)
+	}
+	bus.port = []byte{0xF7, 0xFF}
+	r := d.Diagnose()
+	if !r.OK || len(r.Messages) != 2 || r.Counters["reads"] != 1 {
+		t.Errorf("healthy chip: %+v", r)
+	}
+
+	// Pin 3 latched LOW but reading HIGH: the chip lost its latch.
+	bus.port = []byte{0xFF, 0xFF}
+	if r = d.Diagnose(); !r.OK || len(r.Messages) != 3 {
+		t.Errorf("reset chip should warn: %+v", r)
+	}
+}
+
+func TestWatchInputsDebouncedEvents(t *testing.T) {
+	if _, errs := Factory().ValidateParameters(map[string]interface{}{
+		paramAddress: "0x20", paramPollInputsMs: 100,
+	}); len(errs[paramWatchPins]) == 0 {
+		t.Error("PollInputsMs without WatchPins should fail validation")
+	}
+
+	// A long interval keeps the ticker quiet; the test drives pollInputs itself.
+	d, bus := newTestDriver(t, map[string]interface{}{
+		paramPollInputsMs:   60000,
+		paramWatchPins:      "0-1",
+		paramReadDebounceMs: 20,
+	})
+	events := d.WatchInputs()
+	t0 := time.Now()
+	poll := func(port byte, at time.Duration) {
+		bus.port = []byte{port, 0xFF}
+		d.pollInputs(t0.Add(at))
+	}
+
+	poll(0b11, 0)                   // baseline: no events
+	poll(0b10, 10*time.Millisecond) // pin 0 goes LOW...
+	poll(0b11, 20*time.Millisecond) // ...and bounces back before settling
+	poll(0b10, 30*time.Millisecond) // LOW again
+	poll(0b10, 40*time.Millisecond) // held 10ms: still pending
+	if len(events) != 0 {
+		t.Fatalf("expected no events yet, got %d", len(events))
+	}
+	poll(0b10, 55*time.Millisecond) // held 25ms: delivered
+	select {
+	case ev := <-events:
+		if ev.Pin != 0 || ev.Level || !ev.At.Equal(t0.Add(55*time.Millisecond)) {
+			t.Errorf("unexpected event %+v", ev)
+		}
+	default:
+		t.Fatal("expected an event for pin 0")
+	}
+	if s := d.Stats(); s.Events != 1 || s.EventsDropped != 0 {
+		t.Errorf("events=%d dropped=%d", s.Events, s.EventsDropped)
+	}
+
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-events; ok {
+		t.Error("Close should close the event channel")
+	}
+	if err := d.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+}
+
+func TestWatchConfigOnlyClose(t *testing.T) {
+	d, err := Factory().NewDriver(map[string]interface{}{
+		paramAddress: "0x20", paramPollInputsMs: 100, paramWatchPins: "0-1",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch := d.(*pcf8575Driver).WatchInputs(); ch != nil {
+		t.Error("config-only driver should have no watcher")
+	}
+	done := make(chan error, 1)
+	go func() { done <- d.Close() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close blocked on a watcher that never started")
+	}
+}
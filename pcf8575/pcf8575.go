@@ -9,35 +9,88 @@
 // Higher-level semantics (shadow state, pin release vs drive low, inversion, etc.)
 // are implemented in hal.go.
 //
+// An i2c.Bus call can block indefinitely when another device on the bus holds
+// the clock. With a timeout set (SetTimeout) each transaction runs in its own
+// goroutine and gives up with ErrTimeout; until the stuck call returns, further
+// transactions fail with ErrBusy rather than stacking up behind it.
+//
 package pcf8575
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/reef-pi/rpi/i2c"
 )
 
+// ErrTimeout is returned when a transaction does not finish within the timeout.
+var ErrTimeout = errors.New("i2c transaction timed out")
+
+// ErrBusy is returned while a timed-out transaction is still blocked in the bus.
+var ErrBusy = errors.New("previous i2c transaction still pending")
+
 type PCF8575 struct {
 	addr byte
 	bus  i2c.Bus
+
+	// timeout bounds each transaction (0 = call the bus directly).
+	// inflight holds a token while a transaction runs in its goroutine.
+	timeout  time.Duration
+	inflight chan struct{}
 }
 
 func New(addr byte, bus i2c.Bus) *PCF8575 {
-	return &PCF8575{addr: addr, bus: bus}
+	return &PCF8575{addr: addr, bus: bus, inflight: make(chan struct{}, 1)}
+}
+
+// SetTimeout bounds every Read16/Write16 to d; 0 disables the bound.
+func (p *PCF8575) SetTimeout(d time.Duration) { p.timeout = d }
+
+// do runs op, giving up after p.timeout. A timed-out op keeps its token until
+// the bus call returns, so the next transaction never overlaps it.
+func (p *PCF8575) do(op func() error) error {
+	if p.timeout <= 0 {
+		return op()
+	}
+	select {
+	case p.inflight <- struct{}{}:
+	default:
+		return fmt.Errorf("pcf8575 addr=0x%02X: %w", p.addr, ErrBusy)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- op()
+		<-p.inflight
+	}()
+
+	t := time.NewTimer(p.timeout)
+	defer t.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-t.C:
+		return fmt.Errorf("pcf8575 addr=0x%02X: %w after %v", p.addr, ErrTimeout, p.timeout)
+	}
 }
 
 // Write16 writes the 16-bit latch value (LSB first).
 // bit=1 => release/high (input-ish); bit=0 => drive low.
 func (p *PCF8575) Write16(v uint16) error {
 	b := []byte{byte(v & 0xFF), byte((v >> 8) & 0xFF)}
-	return p.bus.WriteBytes(p.addr, b)
+	return p.do(func() error { return p.bus.WriteBytes(p.addr, b) })
 }
 
 // Read16 reads the current pin levels (LSB first).
 func (p *PCF8575) Read16() (uint16, error) {
 	// reef-pi i2c.Bus signature:
 	//   ReadBytes(addr byte, n int) ([]byte, error)
-	b, err := p.bus.ReadBytes(p.addr, 2)
+	var b []byte
+	err := p.do(func() (err error) {
+		b, err = p.bus.ReadBytes(p.addr, 2)
+		return err
+	})
 	if err != nil {
 		return 0, err
 	}
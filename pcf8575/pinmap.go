@@ -0,0 +1,67 @@
+// pinmap.go
+//
+// Per-pin role map for diagnostics.
+//
+// The chip has no direction register, so a pin's role is derived from the
+// configuration: BidiPins are "bidi", OutputPins are "output", and the rest
+// are "input". Without OutputPins every pin not yet read as an input counts
+// as an output, matching what SelfTest walks. Pins are listed in logical
+// order with the physical bit each one drives (see remap.go).
+//
+package pcf8575
+
+// Pin roles reported by PinMap.
+const (
+	pinRoleInput  = "input"
+	pinRoleOutput = "output"
+	pinRoleBidi   = "bidi"
+)
+
+// PinInfo describes one expander pin for the UI pin map.
+type PinInfo struct {
+	Pin      int    `json:"pin"`
+	Bit      int    `json:"bit"`
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	Invert   bool   `json:"invert"`
+	SinkOnly bool   `json:"sink_only,omitempty"`
+	Driven   bool   `json:"driven"`
+}
+
+// PinMap returns the role, invert setting and name of every pin.
+func (d *pcf8575Driver) PinMap() []PinInfo {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.pinMapLocked()
+}
+
+// pinMapLocked builds the pin map. Caller holds d.mu.
+func (d *pcf8575Driver) pinMapLocked() []PinInfo {
+	out := make([]PinInfo, len(d.pins))
+	for i, p := range d.pins {
+		mask := uint16(1) << uint(p.bit)
+		out[i] = PinInfo{
+			Pin:      p.pin,
+			Bit:      p.bit,
+			Name:     p.Name(),
+			Role:     d.pinRoleLocked(mask),
+			Invert:   d.invert,
+			SinkOnly: d.sinkOnlyMask&mask != 0,
+			Driven:   d.shadow&mask == 0,
+		}
+	}
+	return out
+}
+
+func (d *pcf8575Driver) pinRoleLocked(mask uint16) string {
+	switch {
+	case d.bidiMask&mask != 0:
+		return pinRoleBidi
+	case d.outputMask != 0 && d.outputMask&mask != 0:
+		return pinRoleOutput
+	case d.outputMask == 0 && d.inputMask&mask == 0:
+		return pinRoleOutput
+	default:
+		return pinRoleInput
+	}
+}
@@ -0,0 +1,110 @@
+// readpins.go
+//
+// Multi-pin reads.
+//
+// Read() on a pin costs a release Write16 plus a Read16, so polling a bank of
+// eight float switches pin by pin costs sixteen transactions and re-releases
+// bits one at a time. ReadPins releases every requested pin with one Write16,
+// reads the port once and extracts all levels under a single lock. Per-pin
+// semantics (BidiReadPolicy, ReadDebounceMs, inputMask) match Read().
+//
+package pcf8575
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// ReadPins returns the level of each logical pin in pins (see remap.go).
+// Duplicates are read once. A driven bidirectional pin under BidiReadPolicy
+// "error" fails the whole call before any I2C traffic.
+func (d *pcf8575Driver) ReadPins(pins []int) (map[int]bool, error) {
+	var mask uint16
+	for _, pin := range pins {
+		if pin < 0 || pin > 15 {
+			return nil, fmt.Errorf("pcf8575 addr=0x%02X: read invalid pin=%d", d.addr, pin)
+		}
+		mask |= 1 << d.remap[pin]
+	}
+	levels, err := d.readBits(mask)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int]bool, len(pins))
+	for _, pin := range pins {
+		out[pin] = levels[d.remap[pin]]
+	}
+	return out, nil
+}
+
+// readBits reads the physical bits in mask, keyed by bit.
+func (d *pcf8575Driver) readBits(mask uint16) (map[int]bool, error) {
+	levels := make(map[int]bool, 16)
+	if mask == 0 {
+		return levels, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Driven bidi pins follow BidiReadPolicy; "latched" ones stay driven and
+	// read LOW without being released.
+	drivenBidi := ^d.shadow & d.bidiMask & mask
+	if drivenBidi != 0 {
+		switch d.bidiPolicy {
+		case BidiReadError:
+			return nil, fmt.Errorf("pcf8575 addr=0x%02X read pins=0x%04X: %w (write true to release them first)",
+				d.addr, drivenBidi, ErrPinDriven)
+		case BidiReadLatched:
+			for pin := 0; pin < 16; pin++ {
+				if drivenBidi&(1<<pin) != 0 {
+					levels[pin] = false
+				}
+			}
+			mask &^= drivenBidi
+			if mask == 0 {
+				return levels, nil
+			}
+		}
+	}
+
+	driven := ^d.shadow&mask != 0
+	prevShadow, prevInputMask := d.shadow, d.inputMask
+	d.shadow |= mask
+	d.inputMask |= mask
+
+	if d.debug {
+		log.Printf("pcf8575 addr=0x%02X read pins=0x%04X: release bits (shadow 0x%04X -> 0x%04X)",
+			d.addr, mask, prevShadow, d.shadow)
+	}
+
+	// One write releases every requested pin (and flushes pending batched writes).
+	if err := d.write16Locked(d.shadow); err != nil {
+		failed := d.shadow
+		d.shadow = prevShadow
+		d.inputMask = prevInputMask
+		return nil, fmt.Errorf("pcf8575 addr=0x%02X read pins=0x%04X: write shadow=0x%04X failed: %w",
+			d.addr, mask, failed, err)
+	}
+	d.dirty = false
+
+	if driven && d.readDebounce > 0 {
+		time.Sleep(d.readDebounce)
+	}
+
+	v, err := d.read16Locked()
+	if err != nil {
+		return nil, fmt.Errorf("pcf8575 addr=0x%02X read pins=0x%04X: read16 failed: %w", d.addr, mask, err)
+	}
+	for pin := 0; pin < 16; pin++ {
+		if mask&(1<<pin) != 0 {
+			levels[pin] = v&(1<<pin) != 0
+		}
+	}
+
+	if d.debug {
+		log.Printf("pcf8575 addr=0x%02X read pins=0x%04X: port=0x%04X (shadow=0x%04X)", d.addr, mask, v, d.shadow)
+	}
+	return levels, nil
+}
@@ -0,0 +1,114 @@
+// remap.go
+//
+// Logical-to-physical pin mapping (PinRemap parameter).
+//
+// Many relay boards number their channels opposite to the expander bits, so
+// relay 1 sits on P7. PinRemap lists the physical bit of each logical pin,
+// starting at logical 0; ranges may run downwards:
+//
+//	"7-0"        logical 0..7 -> P7..P0, 8..15 unchanged
+//	"7-0,15-8"   both banks reversed
+//
+// The listed entries must be a permutation of 0..n-1 (n = number of entries),
+// so the mapping is a bijection; unlisted pins keep their own bit. The map is
+// applied wherever a pin number enters the driver: the hal pins, ReadPins,
+// and the BidiPins / SinkOnlyPins / OutputPins / IdentifyPin parameters, which
+// are all given in logical numbering. Internally everything works on physical
+// bits.
+//
+package pcf8575
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pinRemap maps logical pin -> physical bit.
+type pinRemap [16]int
+
+// identityRemap maps every pin to its own bit.
+func identityRemap() pinRemap {
+	var r pinRemap
+	for i := range r {
+		r[i] = i
+	}
+	return r
+}
+
+// parsePinRemap parses a PinRemap spec. "" is the identity map.
+func parsePinRemap(s string) (pinRemap, error) {
+	r := identityRemap()
+	var bits []int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi := part, part
+		if i := strings.Index(part, "-"); i >= 0 {
+			lo, hi = part[:i], part[i+1:]
+		}
+		a, err1 := strconv.Atoi(strings.TrimSpace(lo))
+		b, err2 := strconv.Atoi(strings.TrimSpace(hi))
+		if err1 != nil || err2 != nil || a < 0 || a > 15 || b < 0 || b > 15 {
+			return r, fmt.Errorf("invalid bit or range %q (bits are 0..15)", part)
+		}
+		step := 1
+		if b < a {
+			step = -1
+		}
+		for p := a; ; p += step {
+			bits = append(bits, p)
+			if p == b {
+				break
+			}
+		}
+	}
+	if len(bits) > 16 {
+		return r, fmt.Errorf("lists %d bits, at most 16", len(bits))
+	}
+	var seen uint16
+	for logical, bit := range bits {
+		if bit >= len(bits) {
+			return r, fmt.Errorf("bit %d is outside 0..%d; the %d entries must use each of 0..%d once",
+				bit, len(bits)-1, len(bits), len(bits)-1)
+		}
+		if seen&(1<<bit) != 0 {
+			return r, fmt.Errorf("bit %d is listed twice", bit)
+		}
+		seen |= 1 << bit
+		r[logical] = bit
+	}
+	return r, nil
+}
+
+// physMask converts a mask of logical pins to physical bits.
+func (r pinRemap) physMask(logical uint16) uint16 {
+	var m uint16
+	for pin, bit := range r {
+		if logical&(1<<pin) != 0 {
+			m |= 1 << bit
+		}
+	}
+	return m
+}
+
+// logical returns the logical pin mapped to physical bit.
+func (r pinRemap) logical(bit int) int {
+	for pin, b := range r {
+		if b == bit {
+			return pin
+		}
+	}
+	return bit
+}
+
+// pinLabel names a physical bit in log and error messages by the logical pin
+// users configure, adding the bit when PinRemap moves it.
+func (d *pcf8575Driver) pinLabel(bit int) string {
+	if pin := d.remap.logical(bit); pin != bit {
+		return fmt.Sprintf("pin=%d (bit=%d)", pin, bit)
+	}
+	return fmt.Sprintf("pin=%d", bit)
+}
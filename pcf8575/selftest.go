@@ -0,0 +1,192 @@
+// selftest.go
+//
+// Wiring self-test ("chase the relays") for PCF8575.
+//
+// SelfTest drives each OutputPins pin LOW in turn for selfTestDwell, logging
+// every step, then reads the port once and logs the level of each input pin.
+// Every other bit keeps its current latch, so loads outside OutputPins are
+// never touched. LOW is the active step because it is the only state that can
+// power a load (see Drive mode in hal.go).
+//
+// The driver lock is held for the whole self-test so outlet writes cannot
+// interleave. The latch from before the test is always written back, also on
+// error or when ctx is cancelled.
+//
+// Identify ("which board is this?") blinks the OutputPins pins, or only the
+// IdentifyPin indicator when one is configured, in a double-blink pattern for
+// a given duration and then restores the latch. Unlike SelfTest it does not
+// hold the lock for its whole run.
+//
+package pcf8575
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+const defaultSelfTestDwell = 500 * time.Millisecond
+
+// ErrNoOutputPins is returned by SelfTest when OutputPins is not configured.
+var ErrNoOutputPins = errors.New("OutputPins not configured")
+
+// SelfTest walks a LOW step across the OutputPins pins, one at a time on top
+// of the current latch, and reports input levels, then restores the previous
+// latch. It returns ctx.Err() if cancelled and ErrNoOutputPins when there are
+// no declared outputs to walk.
+func (d *pcf8575Driver) SelfTest(ctx context.Context) (err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	outputs := d.outputMask
+	if outputs == 0 {
+		return fmt.Errorf("pcf8575 addr=0x%02X self-test: %w", d.addr, ErrNoOutputPins)
+	}
+	inputs := d.inputMask &^ outputs
+	prev := d.shadow
+
+	log.Printf("pcf8575 addr=0x%02X self-test start: outputs=0x%04X inputs=0x%04X dwell=%v shadow=0x%04X",
+		d.addr, outputs, inputs, d.selfTestDwell, prev)
+
+	defer func() {
+		if werr := d.write16Locked(prev); werr != nil {
+			err = errors.Join(err, fmt.Errorf("pcf8575 addr=0x%02X self-test: restore shadow=0x%04X failed: %w",
+				d.addr, prev, werr))
+			return
+		}
+		d.shadow = prev
+		d.dirty = false
+		log.Printf("pcf8575 addr=0x%02X self-test done: restored shadow=0x%04X err=%v", d.addr, prev, err)
+	}()
+
+	for pin := 0; pin < 16; pin++ {
+		mask := uint16(1 << pin)
+		if outputs&mask == 0 {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		latch := prev &^ mask
+		log.Printf("pcf8575 addr=0x%02X self-test: pin=%d LOW (latch=0x%04X)", d.addr, pin, latch)
+		if err := d.write16Locked(latch); err != nil {
+			return fmt.Errorf("pcf8575 addr=0x%02X self-test pin=%d: write latch=0x%04X failed: %w",
+				d.addr, pin, latch, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d.selfTestDwell):
+		}
+	}
+
+	if inputs == 0 {
+		return nil
+	}
+	// Input pins are always released in the shadow, so the previous latch
+	// is a safe state to read them in.
+	if err := d.write16Locked(prev); err != nil {
+		return fmt.Errorf("pcf8575 addr=0x%02X self-test: restore before read failed: %w", d.addr, err)
+	}
+	v, err := d.read16Locked()
+	if err != nil {
+		return fmt.Errorf("pcf8575 addr=0x%02X self-test: read16 failed: %w", d.addr, err)
+	}
+	for pin := 0; pin < 16; pin++ {
+		mask := uint16(1 << pin)
+		if inputs&mask != 0 {
+			log.Printf("pcf8575 addr=0x%02X self-test: input pin=%d level=%v", d.addr, pin, v&mask != 0)
+		}
+	}
+	return nil
+}
+
+// Identify timing: two short blinks, then a pause, repeated.
+const (
+	identifyBlinkOn  = 150 * time.Millisecond
+	identifyBlinkOff = 150 * time.Millisecond
+	identifyPause    = 700 * time.Millisecond
+)
+
+// Identify blinks the indicator pin (IdentifyPin) or the OutputPins pins in
+// a double-blink pattern for duration, then restores the latch. Blinking
+// drives the pins LOW, the state that lights a sink-wired LED/relay. Without
+// IdentifyPin every declared output toggles; set it on live panels. Without
+// either it returns ErrNoOutputPins. The lock is taken per toggle, so outlet
+// writes keep working while the board blinks; each toggle is applied on top of
+// the current shadow. It returns ctx.Err() if cancelled.
+func (d *pcf8575Driver) Identify(ctx context.Context, duration time.Duration) (err error) {
+	var blink uint16
+	if d.identifyPin >= 0 {
+		blink = 1 << d.identifyPin
+	} else if blink = d.outputMask; blink == 0 {
+		return fmt.Errorf("pcf8575 addr=0x%02X identify: %w", d.addr, ErrNoOutputPins)
+	}
+
+	log.Printf("pcf8575 addr=0x%02X identify: blinking pins=0x%04X for %v", d.addr, blink, duration)
+
+	defer func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.batchDepth > 0 {
+			return
+		}
+		if werr := d.write16Locked(d.shadow); werr != nil {
+			err = errors.Join(err, fmt.Errorf("pcf8575 addr=0x%02X identify: restore shadow=0x%04X failed: %w",
+				d.addr, d.shadow, werr))
+			return
+		}
+		d.dirty = false
+	}()
+
+	steps := []struct {
+		on   bool
+		wait time.Duration
+	}{
+		{true, identifyBlinkOn}, {false, identifyBlinkOff},
+		{true, identifyBlinkOn}, {false, identifyPause},
+	}
+	deadline := time.Now().Add(duration)
+	for time.Now().Before(deadline) {
+		for _, s := range steps {
+			if err := d.identifyStep(blink, s.on); err != nil {
+				return err
+			}
+			wait := s.wait
+			if rem := time.Until(deadline); rem < wait {
+				wait = rem
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+			if wait < s.wait {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// identifyStep writes one Identify toggle on top of the current shadow. While
+// a batch is open the latch belongs to the batch and the toggle is skipped.
+func (d *pcf8575Driver) identifyStep(blink uint16, on bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.batchDepth > 0 {
+		return nil
+	}
+	latch := d.shadow | blink
+	if on {
+		latch = d.shadow &^ blink
+	}
+	if err := d.write16Locked(latch); err != nil {
+		return fmt.Errorf("pcf8575 addr=0x%02X identify: write latch=0x%04X failed: %w", d.addr, latch, err)
+	}
+	return nil
+}
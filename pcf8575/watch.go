@@ -0,0 +1,168 @@
+// watch.go
+//
+// Input change notifier.
+//
+// Boards without the INT line wired can still get edge events: with
+// PollInputsMs > 0 a goroutine reads the WatchPins every PollInputsMs (one
+// ReadPins-style transaction for all of them), diffs against the last levels
+// and delivers each change on the channel returned by WatchInputs.
+//
+//   - The first successful poll sets the baseline; it produces no events.
+//   - ReadDebounceMs debounces: a change is delivered only after the new level
+//     has held for at least ReadDebounceMs on a later poll, so a float switch
+//     bouncing between two polls never fires. With 0 a change is delivered on
+//     the first poll that sees it.
+//   - The channel is buffered; when the consumer falls behind, events are
+//     dropped (counted in Stats.EventsDropped) rather than stalling the poll.
+//   - Close stops the goroutine and closes the channel.
+//
+// Every poll releases the watched pins like Read() does, so WatchPins must be
+// inputs; it is required with PollInputsMs and may not overlap OutputPins.
+//
+package pcf8575
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	minPollInputsMs = 10
+	maxPollInputsMs = 60000
+
+	// watchBuffer is the PinEvent channel capacity.
+	watchBuffer = 64
+)
+
+// PinEvent is one debounced level change on a watched input pin.
+type PinEvent struct {
+	Pin   int       `json:"pin"`   // logical pin
+	Level bool      `json:"level"` // new level (true = HIGH)
+	At    time.Time `json:"at"`    // poll that delivered the change
+}
+
+// inputWatcher is the state of the PollInputsMs goroutine. levels and pending
+// are only touched by poll.
+type inputWatcher struct {
+	pins     []int // logical pins, ascending
+	mask     uint16
+	interval time.Duration
+	debounce time.Duration
+
+	events chan PinEvent
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+
+	primed  bool
+	levels  map[int]bool
+	pending map[int]time.Time // pin -> when a changed level was first seen
+}
+
+func newInputWatcher(pins []int, mask uint16, interval, debounce time.Duration) *inputWatcher {
+	return &inputWatcher{
+		pins:     pins,
+		mask:     mask,
+		interval: interval,
+		debounce: debounce,
+		events:   make(chan PinEvent, watchBuffer),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		levels:   make(map[int]bool, len(pins)),
+		pending:  make(map[int]time.Time, len(pins)),
+	}
+}
+
+// WatchInputs returns the channel of input changes, or nil when PollInputsMs
+// is off or the driver is config-only (a nil channel never delivers).
+func (d *pcf8575Driver) WatchInputs() <-chan PinEvent {
+	if d.watch == nil {
+		return nil
+	}
+	return d.watch.events
+}
+
+// startWatch runs the poll loop until stopWatch.
+func (d *pcf8575Driver) startWatch() {
+	w := d.watch
+	go func() {
+		defer close(w.done)
+		t := time.NewTicker(w.interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-w.stop:
+				return
+			case now := <-t.C:
+				d.pollInputs(now)
+			}
+		}
+	}()
+}
+
+// stopWatch stops the poll loop and closes the event channel. Safe to call
+// more than once.
+func (d *pcf8575Driver) stopWatch() {
+	w := d.watch
+	if w == nil {
+		return
+	}
+	w.once.Do(func() {
+		close(w.stop)
+		<-w.done
+		close(w.events)
+	})
+}
+
+// pollInputs reads the watched pins once and delivers debounced changes.
+// A failed read is skipped (it is already counted in Stats).
+func (d *pcf8575Driver) pollInputs(now time.Time) {
+	w := d.watch
+	levels, err := d.readBits(w.mask)
+	if err != nil {
+		if d.debug {
+			log.Printf("pcf8575 addr=0x%02X watch: poll failed: %v", d.addr, err)
+		}
+		return
+	}
+
+	for _, pin := range w.pins {
+		level := levels[d.remap[pin]]
+		if !w.primed {
+			w.levels[pin] = level
+			continue
+		}
+		if level == w.levels[pin] {
+			delete(w.pending, pin) // bounced back before it settled
+			continue
+		}
+		since, ok := w.pending[pin]
+		if !ok {
+			since = now
+			w.pending[pin] = now
+		}
+		if w.debounce > 0 && (!ok || now.Sub(since) < w.debounce) {
+			continue
+		}
+		delete(w.pending, pin)
+		w.levels[pin] = level
+		d.deliver(PinEvent{Pin: pin, Level: level, At: now})
+	}
+	w.primed = true
+}
+
+// deliver sends ev without blocking; a full channel drops it.
+func (d *pcf8575Driver) deliver(ev PinEvent) {
+	select {
+	case d.watch.events <- ev:
+		d.mu.Lock()
+		d.stats.Events++
+		d.mu.Unlock()
+	default:
+		d.mu.Lock()
+		d.stats.EventsDropped++
+		d.mu.Unlock()
+		log.Printf("pcf8575 addr=0x%02X watch: event channel full, dropped pin %d -> %v", d.addr, ev.Pin, ev.Level)
+	}
+}
@@ -0,0 +1,63 @@
+// diagnose.go
+//
+// Health report (see package diag).
+//
+// The liveness check reads the ADS1119 configuration register (RREG), which
+// changes nothing on the converter. A register that no longer holds
+// configByte means the ADC reset (power glitch) and stopped converting. One
+// module read and the temperature checks Snapshot reports follow.
+//
+package ph_board
+
+import (
+	"time"
+
+	"github.com/reef-pi/drivers/diag"
+)
+
+// cmdRReg reads register 0 (configuration) of the ADS1119.
+const cmdRReg = 0x20
+
+var _ diag.Diagnoser = (*phDriver)(nil)
+
+// readConfig reads the ADS1119 configuration register.
+func (d *phDriver) readConfig() (byte, error) {
+	lock := lockForAddr(d.addr)
+	lock.Lock()
+	defer lock.Unlock()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.enforceMinGap(minI2CGap)
+	buf := make([]byte, 1)
+	err := d.bus.ReadFromReg(d.addr, cmdRReg, buf)
+	d.lastXferAt = time.Now()
+	return buf[0], err
+}
+
+// Diagnose reads the ADC configuration and one sample, and checks the
+// calibration anchors and injected temperature.
+func (d *phDriver) Diagnose() diag.Diagnostics {
+	r := diag.New()
+
+	cfg, err := d.readConfig()
+	if r.Check("read config register", err) && cfg != configByte {
+		r.Warnf("config register 0x%02X, want 0x%02X; the ADC may have reset (power glitch) and stopped converting", cfg, configByte)
+	}
+	if mv, _, code, err := d.readObservedMV(); r.Check("read module", err) {
+		r.Infof("observed_mv=%.2f adc=%d", mv, code)
+	}
+
+	if len(d.enabledAnchors()) == 0 {
+		r.Warnf("no calibration anchor enabled (Obs4_mV, Obs7_mV, Obs10_mV); pH uses the ideal model")
+	}
+	if d.doTempComp {
+		if d.tempUpdatedAt.IsZero() {
+			r.Warnf("temperature compensation is on but no temperature was ever injected")
+		} else if age := time.Since(d.tempUpdatedAt); age > 2*time.Minute {
+			r.Warnf("injected temperature is stale (age %v)", age.Round(time.Second))
+		}
+	}
+	return r
+}
@@ -0,0 +1,43 @@
+package ph_board
+
+import (
+	"testing"
+	"time"
+)
+
+// regBus is an i2c.Bus answering ReadFromReg from regs by command byte.
+type regBus struct {
+	regs map[byte][]byte
+}
+
+func (b *regBus) SetAddress(_ byte) error                 { return nil }
+func (b *regBus) ReadBytes(_ byte, _ int) ([]byte, error) { return nil, nil }
+func (b *regBus) WriteBytes(_ byte, _ []byte) error       { return nil }
+func (b *regBus) ReadFromReg(_, reg byte, v []byte) error {
+	copy(v, b.regs[reg])
+	return nil
+}
+func (b *regBus) WriteToReg(_, _ byte, _ []byte) error { return nil }
+func (b *regBus) Close() error                         { return nil }
+
+func TestDiagnose(t *testing.T) {
+	bus := &regBus{regs: map[byte][]byte{cmdRReg: {configByte}, cmdRData: {0x01, 0x00}}}
+	d := &phDriver{addr: 0x40, bus: bus, vrefV: fixedVrefV, obs7mV: 0, obs4mV: -1, obs10mV: -1}
+
+	r := d.Diagnose()
+	if !r.OK || len(r.Messages) != 3 {
+		t.Errorf("healthy module: %+v", r)
+	}
+
+	// The ADC reset: config back to its power-on 0x00.
+	bus.regs[cmdRReg] = []byte{0x00}
+	d.lastSampleAt, d.lastXferAt = time.Time{}, time.Time{}
+	if r := d.Diagnose(); !r.OK || len(r.Messages) != 4 {
+		t.Errorf("reset ADC should warn: %+v", r)
+	}
+
+	d.doTempComp, d.obs7mV = true, -1
+	if r := d.Diagnose(); len(r.Messages) != 6 {
+		t.Errorf("no anchors and no temperature should both warn: %+v", r)
+	}
+}
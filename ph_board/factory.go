@@ -8,8 +8,9 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/reef-pi/drivers/i2creg"
+	"github.com/reef-pi/drivers/nobus"
 	"github.com/reef-pi/hal"
-	"github.com/reef-pi/rpi/i2c"
 )
 
 type factory struct {
@@ -107,11 +108,8 @@ func (f *factory) ValidateParameters(parameters map[string]interface{}) (bool, m
 	if !ok {
 		failures[addressParam] = append(failures[addressParam], "Address parameter is required")
 	} else {
-		addr, ok := toInt(addrRaw)
-		if !ok {
-			failures[addressParam] = append(failures[addressParam], "Address must be an integer (0..127)")
-		} else if addr < 0 || addr > 127 {
-			failures[addressParam] = append(failures[addressParam], "Address must be 0..127 (7-bit)")
+		if _, err := i2creg.ParseI2CAddress(addrRaw); err != nil {
+			failures[addressParam] = append(failures[addressParam], err.Error())
 		}
 	}
 
@@ -150,7 +148,10 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
 		}
 	}
 
-	addrInt := getIntAny(parameters, 64, addressParam, "Address", "address")
+	addr := byte(64)
+	if v, ok := getAny(parameters, addressParam, "Address", "address"); ok {
+		addr, _ = i2creg.ParseI2CAddress(v)
+	}
 
 	obs7 := getFloatAny(parameters, -1.0,
 		obs7mVParam, "Obs7_mv", "obs7_mv", "ph7_mv")
@@ -168,9 +169,14 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
 	doTempComp := getBoolAny(parameters, false,
 		doTempCompParam, "Dotempcomp", "dotempcomp", "dotc")
 
+	bus, err := nobus.I2C("ph_board", hardwareResources)
+	if err != nil {
+		return nil, err
+	}
+
 	d := &phDriver{
-		addr:          byte(addrInt),
-		bus:           hardwareResources.(i2c.Bus),
+		addr:          addr,
+		bus:           bus,
 		vrefV:         fixedVrefV,
 		obs7mV:        obs7,
 		obs4mV:        obs4,
@@ -191,11 +197,14 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
 
 	if debug {
 		log.Printf("pHboard_driver init addr=%d (0x%02X) Vref=%.3f Obs7=%.2f Obs4=%.2f Obs10=%.2f slope_override=%.4f DoTC=%v RefTempC=%.2f tempC(init)=%.2f",
-			addrInt, addrInt, fixedVrefV, obs7, obs4, obs10, slopeOverride, doTempComp, refTempC, d.tempC)
+			addr, addr, fixedVrefV, obs7, obs4, obs10, slopeOverride, doTempComp, refTempC, d.tempC)
 	}
 
-	if err := d.initADC(); err != nil {
-		return nil, err
+	// Config-only drivers (nil bus) skip the ADC setup; reads return nobus.ErrNoBus.
+	if !nobus.Is(bus) {
+		if err := d.initADC(); err != nil {
+			return nil, err
+		}
 	}
 
 	return d, nil
@@ -5,8 +5,9 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/reef-pi/drivers/i2creg"
+	"github.com/reef-pi/drivers/nobus"
 	"github.com/reef-pi/hal"
-	"github.com/reef-pi/rpi/i2c"
 )
 
 const addressParam = "Address"
@@ -56,14 +57,8 @@ func (f *phFactory) ValidateParameters(parameters map[string]interface{}) (bool,
 	var failures = make(map[string][]string)
 
 	if v, ok := parameters[addressParam]; ok {
-		val, ok := hal.ConvertToInt(v)
-		if !ok {
-			failure := fmt.Sprint(addressParam, " is not a number. ", v, " was received.")
-			failures[addressParam] = append(failures[addressParam], failure)
-		}
-		if val <= 0 || val >= 256 {
-			failure := fmt.Sprint(addressParam, " is out of range (1 - 255). ", v, " was received.")
-			failures[addressParam] = append(failures[addressParam], failure)
+		if _, err := i2creg.ParseI2CAddress(v); err != nil {
+			failures[addressParam] = append(failures[addressParam], err.Error())
 		}
 	} else {
 		failure := fmt.Sprint(addressParam, " is a required parameter, but was not received.")
@@ -78,19 +73,23 @@ func (f *phFactory) NewDriver(parameters map[string]interface{}, hardwareResourc
 		return nil, errors.New(hal.ToErrorString(failures))
 	}
 
-	intAddress, _ := hal.ConvertToInt(parameters[addressParam])
-	address := byte(intAddress)
-
-	bus := hardwareResources.(i2c.Bus)
+	address, _ := i2creg.ParseI2CAddress(parameters[addressParam])
 
-	if err := bus.WriteBytes(address, []byte{0x06}); err != nil {
-		return nil, err
-	}
-	if err := bus.WriteBytes(address, []byte{0x40, 0x06}); err != nil {
+	bus, err := nobus.I2C("pico_board", hardwareResources)
+	if err != nil {
 		return nil, err
 	}
-	if err := bus.WriteBytes(address, []byte{0x08}); err != nil {
-		return nil, err
+
+	if !nobus.Is(bus) {
+		if err := bus.WriteBytes(address, []byte{0x06}); err != nil {
+			return nil, err
+		}
+		if err := bus.WriteBytes(address, []byte{0x40, 0x06}); err != nil {
+			return nil, err
+		}
+		if err := bus.WriteBytes(address, []byte{0x08}); err != nil {
+			return nil, err
+		}
 	}
 
 	ch, err := newChannel(bus, address)
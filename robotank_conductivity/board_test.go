@@ -0,0 +1,135 @@
+package robotank_conductivity
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// fakeBoard is an i2c.Bus answering each command from resp, framed like the
+// board (status byte 1, NUL-terminated). Unknown commands answer status 2.
+// writeErr fails the write of the commands it names.
+type fakeBoard struct {
+	resp     map[string]string
+	writeErr map[string]error
+	cmds     []string
+	last     string
+}
+
+func (b *fakeBoard) SetAddress(_ byte) error { return nil }
+func (b *fakeBoard) ReadBytes(_ byte, n int) ([]byte, error) {
+	out := make([]byte, n)
+	r, ok := b.resp[b.last]
+	if !ok {
+		out[0] = 2
+		return out, nil
+	}
+	out[0] = 1
+	copy(out[1:], r)
+	return out, nil
+}
+func (b *fakeBoard) WriteBytes(_ byte, v []byte) error {
+	cmd := strings.TrimRight(string(v), "\x00")
+	if err := b.writeErr[cmd]; err != nil {
+		return err
+	}
+	b.cmds = append(b.cmds, cmd)
+	b.last = cmd
+	return nil
+}
+func (b *fakeBoard) ReadFromReg(_, _ byte, _ []byte) error { return nil }
+func (b *fakeBoard) WriteToReg(_, _ byte, _ []byte) error  { return nil }
+func (b *fakeBoard) Close() error                          { return nil }
+
+// newTestDriver builds a driver on bus through the factory (Address 0x64 unless
+// params set one) with the command delays zeroed.
+func newTestDriver(t *testing.T, bus *fakeBoard, params map[string]interface{}) *RoboTankConductivity {
+	t.Helper()
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	if _, ok := params[addressParam]; !ok {
+		params[addressParam] = "0x64"
+	}
+	drv, err := Factory().NewDriver(params, bus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := drv.(*RoboTankConductivity)
+	d.delay, d.retryDelay = 0, 0
+	t.Cleanup(func() { d.Close() })
+	return d
+}
+
+func TestBoardTempC(t *testing.T) {
+	bus := &fakeBoard{resp: map[string]string{"T,?": "?T,23.4"}}
+	d := newTestDriver(t, bus, nil)
+
+	got, err := d.BoardTempC()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 23.4 {
+		t.Errorf("BoardTempC = %v, want 23.4", got)
+	}
+	if last := bus.cmds[len(bus.cmds)-1]; last != defaultBoardTempCommand {
+		t.Errorf("sent %q, want %q", last, defaultBoardTempCommand)
+	}
+	if d.boardTempC != 23.4 || d.boardTempAt.IsZero() {
+		t.Errorf("board temp not recorded: %v at %v", d.boardTempC, d.boardTempAt)
+	}
+
+	bus.resp["T,?"] = "?T,75.0"
+	if _, err := d.BoardTempC(); err == nil {
+		t.Error("expected an out-of-range board temperature to fail")
+	}
+	if d.boardTempC != 23.4 {
+		t.Errorf("rejected read replaced the board temp: %v", d.boardTempC)
+	}
+}
+
+func TestBoardTempFallback(t *testing.T) {
+	bus := &fakeBoard{resp: map[string]string{
+		"H":   "RT-COND 1.2",
+		"T,?": "?T,20.0",
+		"U":   "60.0",
+		"V":   "10.0",
+	}}
+	d := newTestDriver(t, bus, map[string]interface{}{
+		absDRODIParam:      100.0,
+		absDStdParam:       20.0,
+		readBoardTempParam: true,
+	})
+
+	// No injected temperature: the board's 20°C compensates.
+	want, _ := TempCompToRef(33125, 20, fixedRefTempC, d.alphaPerC)
+	s, err := d.pins[0].Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(s.Value-want) > 0.01 {
+		t.Errorf("value %v, want %v", s.Value, want)
+	}
+	if s.Meta["temp_source"] != tempSourceBoard || s.Meta["comp_status"] != compBoard || s.Meta["temp_valid"] != true {
+		t.Errorf("meta temp_source=%v comp_status=%v temp_valid=%v", s.Meta["temp_source"], s.Meta["comp_status"], s.Meta["temp_valid"])
+	}
+	if got := s.Signals["tempC"].Now; got != 20 {
+		t.Errorf("tempC signal %v, want 20", got)
+	}
+	if active, reason, tempC, _ := d.CompensationState(); !active || reason != compBoard || tempC != 20 {
+		t.Errorf("CompensationState: active=%v reason=%q tempC=%v", active, reason, tempC)
+	}
+
+	// A board that cannot answer leaves the reading uncompensated.
+	delete(bus.resp, "T,?")
+	s, err = d.pins[0].Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(s.Value-33125) > 0.01 {
+		t.Errorf("value %v, want uncompensated 33125", s.Value)
+	}
+	if s.Meta["temp_source"] != tempSourceNone || s.Meta["comp_status"] != compNoTemp || s.Meta["temp_valid"] != false {
+		t.Errorf("meta temp_source=%v comp_status=%v temp_valid=%v", s.Meta["temp_source"], s.Meta["comp_status"], s.Meta["temp_valid"])
+	}
+}
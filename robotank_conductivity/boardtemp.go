@@ -0,0 +1,95 @@
+// boardtemp.go
+package robotank_conductivity
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	// Default command asking the board for its temperature: the Atlas-style
+	// "T,?" query, answered "?T,25.3" (parseFirstFloat takes the number). On
+	// Atlas firmware a bare "T" is a set-command, not a query.
+	defaultBoardTempCommand = "T,?"
+
+	// Board temperatures outside this range are treated as a bad read.
+	minBoardTempC = 0.0
+	maxBoardTempC = 60.0
+)
+
+// Temperature sources reported in Snapshot meta (temp_source).
+const (
+	tempSourceInjected = "injected"
+	tempSourceBoard    = "board"
+	tempSourceNone     = "none"
+)
+
+// BoardTempC asks the board for its own temperature reading (°C) using the
+// configured BoardTempCommand. It works whether or not ReadBoardTemp is set.
+func (d *RoboTankConductivity) BoardTempC() (float64, error) {
+	t, err := d.readFloat(d.boardTempCmd)
+	if err != nil {
+		return 0, fmt.Errorf("%s: board temperature: %w", driverName, err)
+	}
+	if t < minBoardTempC || t > maxBoardTempC {
+		return 0, fmt.Errorf("%s: board temperature %.2fC outside %.0f..%.0fC", driverName, t, minBoardTempC, maxBoardTempC)
+	}
+
+	d.mu.Lock()
+	d.boardTempC = t
+	d.boardTempAt = time.Now()
+	d.mu.Unlock()
+	return t, nil
+}
+
+// boardTempComp compensates us with the board temperature when ReadBoardTemp is
+// on and no usable injected temperature exists. Otherwise us is returned as-is
+// (i.e. 25°C is assumed).
+func (d *RoboTankConductivity) boardTempComp(us float64) float64 {
+	d.mu.Lock()
+	enabled := d.readBoardTemp
+	refTempC, alpha := d.refTempC, d.alphaPerC
+	d.mu.Unlock()
+
+	if !enabled {
+		d.setTempSource(tempSourceNone)
+		return us
+	}
+
+	t, err := d.BoardTempC()
+	if err != nil {
+		log.Printf("robotank_cond addr=%d board temp unavailable, assume %.2fC: %v", d.addr, refTempC, err)
+		d.setTempSource(tempSourceNone)
+		return us
+	}
+
+	usRef, _ := TempCompToRef(us, t, refTempC, alpha)
+	if d.debug {
+		log.Printf("robotank_cond addr=%d tempComp(board): us_meas=%.2f at %.2fC -> us_ref=%.2f at %.2fC (alpha=%.6f)",
+			d.addr, us, t, usRef, refTempC, alpha)
+	}
+	d.setTempSource(tempSourceBoard)
+	return usRef
+}
+
+func (d *RoboTankConductivity) setTempSource(src string) {
+	d.mu.Lock()
+	d.tempSource = src
+	d.mu.Unlock()
+}
+
+// boardTempMeta adds temperature source meta for Snapshot.
+func (d *RoboTankConductivity) boardTempMeta(meta map[string]any) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	meta["temp_source"] = d.tempSource
+	meta["read_board_temp"] = d.readBoardTemp
+	if d.boardTempAt.IsZero() {
+		meta["board_temp_c"] = nil
+		return
+	}
+	meta["board_temp_c"] = d.boardTempC
+	meta["board_temp_age_sec"] = time.Since(d.boardTempAt).Seconds()
+}
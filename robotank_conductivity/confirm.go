@@ -0,0 +1,107 @@
+// confirm.go
+package robotank_conductivity
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+)
+
+// ConfirmReads: a single |U−V| occasionally spikes on a board glitch. With
+// ConfirmReads on, every measurement takes two absDiff reads confirmInterval
+// apart; if they differ by more than ConfirmTolPct a third read is taken and
+// the median of the three is used, otherwise the two are averaged. Costs two
+// (or three) U/V pairs per reading, so it is off by default.
+const (
+	confirmInterval = 100 * time.Millisecond
+
+	defaultConfirmTolPct = 2.0
+	maxConfirmTolPct     = 50.0
+)
+
+// absDRead is one absDiff result.
+type absDRead struct {
+	ad, u, v float64
+}
+
+// confirmAbsD combines two reads, taking a third from more when they
+// disagree by more than tolPct. devPct is the disagreement of the first two.
+func confirmAbsD(a, b absDRead, tolPct float64, more func() (absDRead, error)) (r absDRead, devPct float64, third bool, err error) {
+	mean := (a.ad + b.ad) / 2
+	switch {
+	case a.ad == b.ad:
+		devPct = 0
+	case mean > 0:
+		devPct = math.Abs(a.ad-b.ad) / mean * 100
+	default:
+		devPct = math.Inf(1)
+	}
+	if devPct <= tolPct {
+		return absDRead{ad: mean, u: (a.u + b.u) / 2, v: (a.v + b.v) / 2}, devPct, false, nil
+	}
+
+	c, err := more()
+	if err != nil {
+		return absDRead{}, devPct, true, err
+	}
+	reads := []absDRead{a, b, c}
+	sort.Slice(reads, func(i, j int) bool { return reads[i].ad < reads[j].ad })
+	return reads[1], devPct, true, nil
+}
+
+// measureAbsD is absDiff, confirmed by extra reads when ConfirmReads is on.
+func (d *RoboTankConductivity) measureAbsD() (ad, u, v float64, err error) {
+	if !d.confirmReads {
+		return d.absDiff()
+	}
+	read := func() (absDRead, error) {
+		ad, u, v, err := d.absDiff()
+		return absDRead{ad: ad, u: u, v: v}, err
+	}
+
+	a, err := read()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	time.Sleep(confirmInterval)
+	b, err := read()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	r, devPct, third, err := confirmAbsD(a, b, d.confirmTolPct, func() (absDRead, error) {
+		time.Sleep(confirmInterval)
+		return read()
+	})
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("confirm read: %w", err)
+	}
+
+	d.mu.Lock()
+	d.confirmDevPct = devPct
+	if third {
+		d.confirmThirds++
+	}
+	debug := d.debug
+	d.mu.Unlock()
+
+	if debug {
+		log.Printf("robotank_cond addr=%d confirm |d|=%.3f,%.3f dev=%.2f%% tol=%.2f%% third=%v -> |d|=%.3f",
+			d.addr, a.ad, b.ad, devPct, d.confirmTolPct, third, r.ad)
+	}
+	return r.ad, r.u, r.v, nil
+}
+
+// confirmMeta adds ConfirmReads state to snapshot meta.
+func (d *RoboTankConductivity) confirmMeta(meta map[string]any) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	meta["confirm_reads"] = d.confirmReads
+	if !d.confirmReads {
+		return
+	}
+	meta["confirm_tol_pct"] = d.confirmTolPct
+	meta["confirm_dev_pct"] = d.confirmDevPct
+	meta["confirm_thirds"] = d.confirmThirds
+}
@@ -0,0 +1,103 @@
+// conversion.go
+package robotank_conductivity
+
+import (
+	"fmt"
+	"math"
+)
+
+// Pure conversion math, free of I/O and driver state, so it can be unit-tested
+// and reused. The driver methods (usFromAbsD, tempCompToRef, pptFromUS) wrap these.
+
+// USFromAbsD maps |U−V| to µS/cm using the two calibration anchors:
+// absFresh (RO/DI, 0 µS) and absStd (standard solution, refUS).
+// absD is large in fresh water and small in salt water. The result is clamped
+// to 0..1.2*refUS to allow slight overshoot without spikes.
+func USFromAbsD(ad, absFresh, absStd, refUS float64) (float64, error) {
+	if absFresh <= 0 || absStd <= 0 {
+		return 0, fmt.Errorf("%s: %w", driverName, errUncalibrated)
+	}
+	if absFresh == absStd {
+		return 0, fmt.Errorf("%s: invalid calibration (AbsD_RODI == AbsD_Std)", driverName)
+	}
+
+	x := (absFresh - ad) / (absFresh - absStd)
+	if x < 0 {
+		x = 0
+	}
+	if x > 1.2 {
+		x = 1.2
+	}
+	return x * refUS, nil
+}
+
+// TempCompToRef converts µS/cm measured at tempC to µS/cm at refTempC:
+//
+//	uS_ref = uS_meas / (1 + alpha*(tempC-refTempC))
+//
+// The denominator is floored at 0.1 to keep extreme inputs finite.
+// clamped reports whether the floor was applied.
+func TempCompToRef(us, tempC, refTempC, alpha float64) (usRef float64, clamped bool) {
+	den := 1.0 + alpha*(tempC-refTempC)
+	if den <= 0.1 {
+		den, clamped = 0.1, true
+	}
+	return us / den, clamped
+}
+
+// PPTFromUS converts µS/cm @ reference temperature to salinity (ppt),
+// scaling linearly so refUS maps to 35 ppt.
+func PPTFromUS(usRef, refUS float64) float64 {
+	return usRef * (35.0 / refUS)
+}
+
+// ComputeConductivity runs the whole pure pipeline for one U/V pair:
+// |U−V| -> µS/cm (USFromAbsD) -> µS/cm @refTempC (TempCompToRef).
+// It allocates nothing on success, so it is the entrypoint for benchmarks.
+func ComputeConductivity(u, v, absFresh, absStd, refUS, tempC, refTempC, alpha float64) (float64, error) {
+	ad := u - v
+	if ad < 0 {
+		ad = -ad
+	}
+	us, err := USFromAbsD(ad, absFresh, absStd, refUS)
+	if err != nil {
+		return 0, err
+	}
+	usRef, _ := TempCompToRef(us, tempC, refTempC, alpha)
+	return usRef, nil
+}
+
+// PSS-78 coefficients (UNESCO practical salinity scale).
+var (
+	pss78A = [6]float64{0.0080, -0.1692, 25.3851, 14.0941, -7.0261, 2.7081}
+	pss78B = [6]float64{0.0005, -0.0056, -0.0066, -0.0375, 0.0636, -0.0144}
+)
+
+const pss78K = 0.0162
+
+// PSUFromUS converts µS/cm @ refTempC (25°C) to practical salinity (PSU) with
+// the PSS-78 polynomial. The conductivity ratio is usRef/refUS, i.e. refUS is
+// taken as the conductivity of 35 PSU seawater at the reference temperature,
+// the same convention PPTFromUS uses. The ratio is floored at 0.
+func PSUFromUS(usRef, refUS float64) float64 {
+	rt := usRef / refUS
+	if rt <= 0 {
+		return 0
+	}
+	const t = fixedRefTempC
+	sqrtRt := math.Sqrt(rt)
+	var s, ds float64
+	p := 1.0
+	for i := range pss78A {
+		s += pss78A[i] * p
+		ds += pss78B[i] * p
+		p *= sqrtRt
+	}
+	return s + ds*(t-15)/(1+pss78K*(t-15))
+}
+
+// SGFromPPT converts salinity (ppt) to specific gravity at 25°C/25°C using the
+// usual reef-keeping linear approximation: 35 ppt -> 1.0264.
+func SGFromPPT(ppt float64) float64 {
+	return 1 + ppt*(0.0264/35.0)
+}
@@ -0,0 +1,117 @@
+package robotank_conductivity
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestUSFromAbsD(t *testing.T) {
+	// Fresh absD=100, standard absD=20 (53000 uS).
+	cases := []struct {
+		ad, want float64
+	}{
+		{100, 0},
+		{20, 53000},
+		{60, 26500},
+		{150, 0},            // clamped low
+		{-100, 1.2 * 53000}, // clamped high
+	}
+	for _, c := range cases {
+		got, err := USFromAbsD(c.ad, 100, 20, 53000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(got-c.want) > 1e-6 {
+			t.Errorf("USFromAbsD(%v) = %v, want %v", c.ad, got, c.want)
+		}
+	}
+
+	if _, err := USFromAbsD(50, 0, 20, 53000); !errors.Is(err, errUncalibrated) {
+		t.Errorf("expected errUncalibrated, got %v", err)
+	}
+	if _, err := USFromAbsD(50, 20, 20, 53000); err == nil {
+		t.Error("expected error for equal anchors")
+	}
+}
+
+func TestTempCompToRef(t *testing.T) {
+	got, clamped := TempCompToRef(1020, 35, 25, 0.002)
+	if clamped || math.Abs(got-1000) > 1e-9 {
+		t.Errorf("got %v clamped=%v, want 1000", got, clamped)
+	}
+	if _, clamped := TempCompToRef(1000, -1000, 25, 0.05); !clamped {
+		t.Error("expected denominator clamp")
+	}
+}
+
+func TestPPTFromUS(t *testing.T) {
+	if got := PPTFromUS(53000, 53000); got != 35 {
+		t.Errorf("got %v, want 35", got)
+	}
+}
+
+func TestComputeConductivity(t *testing.T) {
+	// |U−V| = 60 -> 26500 µS/cm at 35C, alpha 0.02 -> /1.2
+	got, err := ComputeConductivity(130, 70, 100, 20, 53000, 35, 25, 0.02)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(got-26500/1.2) > 1e-6 {
+		t.Errorf("got %v, want %v", got, 26500/1.2)
+	}
+	if _, err := ComputeConductivity(70, 130, 0, 20, 53000, 25, 25, 0.02); !errors.Is(err, errUncalibrated) {
+		t.Errorf("expected errUncalibrated, got %v", err)
+	}
+}
+
+func BenchmarkComputeConductivity(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ComputeConductivity(float64(i%80)+40, 20, 100, 20, 53000, 26.5, 25, 0.02); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestPSUFromUS(t *testing.T) {
+	if got := PSUFromUS(53000, 53000); math.Abs(got-35) > 1e-3 {
+		t.Errorf("got %v, want 35", got)
+	}
+	half := PSUFromUS(26500, 53000)
+	if half <= 15 || half >= 18.5 {
+		t.Errorf("half-strength seawater should read ~17 PSU, got %v", half)
+	}
+	if got := PSUFromUS(-5, 53000); got != 0 {
+		t.Errorf("got %v, want 0", got)
+	}
+}
+
+func TestSGFromPPT(t *testing.T) {
+	if got := SGFromPPT(35); math.Abs(got-1.0264) > 1e-9 {
+		t.Errorf("got %v, want 1.0264", got)
+	}
+	if got := SGFromPPT(0); got != 1 {
+		t.Errorf("got %v, want 1", got)
+	}
+}
+
+func TestConfirmAbsD(t *testing.T) {
+	thirdCalls := 0
+	third := func() (absDRead, error) {
+		thirdCalls++
+		return absDRead{ad: 101}, nil
+	}
+
+	// Within tolerance: averaged, no third read.
+	r, dev, used, err := confirmAbsD(absDRead{ad: 100}, absDRead{ad: 101}, 2, third)
+	if err != nil || used || thirdCalls != 0 || r.ad != 100.5 || math.Abs(dev-0.995) > 0.001 {
+		t.Errorf("agreeing reads: ad=%v dev=%v third=%v calls=%d err=%v", r.ad, dev, used, thirdCalls, err)
+	}
+
+	// A spike: the third read breaks the tie and the median wins.
+	r, _, used, err = confirmAbsD(absDRead{ad: 100}, absDRead{ad: 160}, 2, third)
+	if err != nil || !used || thirdCalls != 1 || r.ad != 101 {
+		t.Errorf("spike: ad=%v third=%v calls=%d err=%v", r.ad, used, thirdCalls, err)
+	}
+}
@@ -0,0 +1,49 @@
+// diagnose.go
+package robotank_conductivity
+
+import (
+	"time"
+
+	"github.com/reef-pi/drivers/diag"
+)
+
+var _ diag.Diagnoser = (*RoboTankConductivity)(nil)
+
+// Diagnose reads U and V once (without feeding the drift baseline) and folds
+// in the calibration quality, probe drift and compensation state that
+// Snapshot reports as notes.
+func (d *RoboTankConductivity) Diagnose() diag.Diagnostics {
+	r := diag.New()
+
+	ad, u, v, err := d.absDiff()
+	if r.Check("read U/V", err) {
+		r.Infof("U=%.3f V=%.3f |U−V|=%.3f mV", u, v, ad)
+	}
+
+	d.mu.Lock()
+	absFresh, absStd := d.absDFresh, d.absDStd
+	d.mu.Unlock()
+	meta := map[string]any{}
+	var notes []string
+	if absFresh <= 0 || absStd <= 0 {
+		r.Warnf("not calibrated: AbsD_RODI and AbsD_Std must be set before conductivity is reported")
+	} else {
+		notes = d.calQualityMeta(meta)
+	}
+	notes = append(notes, d.driftMeta(meta)...)
+	for _, n := range notes {
+		r.Warnf("%s", n)
+	}
+
+	if active, reason, tempC, age := d.CompensationState(); active {
+		r.Infof("temperature compensation %s (%.2f°C, age %v)", reason, tempC, age.Round(time.Second))
+	} else {
+		r.Infof("temperature compensation %s", reason)
+	}
+
+	d.mu.Lock()
+	r.Counters["absd_samples"] = uint64(d.absD.samples)
+	r.Counters["confirm_thirds"] = uint64(d.confirmThirds)
+	d.mu.Unlock()
+	return r
+}
@@ -0,0 +1,82 @@
+// drift.go
+package robotank_conductivity
+
+import "fmt"
+
+const (
+	// Recent absD is averaged over this many samples before drift is reported.
+	absDRecentWindow = 10
+
+	// Baseline is a slow EMA of absD (~1/alpha samples of memory), so normal
+	// noise averages out while fouling or cable degradation shows up as drift.
+	absDBaselineAlpha = 0.002
+
+	defaultAbsDDriftWarnPct = 10.0
+)
+
+// absDTracker keeps a slow baseline and a short window of recent absD readings.
+// Guarded by RoboTankConductivity.mu.
+type absDTracker struct {
+	baseline float64
+	samples  int
+
+	recent []float64
+	next   int
+}
+
+func (t *absDTracker) add(ad float64) {
+	if t.samples == 0 {
+		t.baseline = ad
+	} else {
+		t.baseline += absDBaselineAlpha * (ad - t.baseline)
+	}
+	t.samples++
+
+	if len(t.recent) < absDRecentWindow {
+		t.recent = append(t.recent, ad)
+		return
+	}
+	t.recent[t.next] = ad
+	t.next = (t.next + 1) % absDRecentWindow
+}
+
+func (t *absDTracker) reset() { *t = absDTracker{} }
+
+// driftPct returns how far recent absD sits from the baseline, in percent.
+// ok is false until a full recent window has been collected.
+func (t *absDTracker) driftPct() (pct float64, ok bool) {
+	if len(t.recent) < absDRecentWindow || t.baseline == 0 {
+		return 0, false
+	}
+	sum := 0.0
+	for _, v := range t.recent {
+		sum += v
+	}
+	mean := sum / float64(len(t.recent))
+	return (mean - t.baseline) / t.baseline * 100.0, true
+}
+
+// driftMeta adds absD drift meta and, past the warning threshold, a note.
+func (d *RoboTankConductivity) driftMeta(meta map[string]any) (notes []string) {
+	d.mu.Lock()
+	pct, ok := d.absD.driftPct()
+	baseline := d.absD.baseline
+	samples := d.absD.samples
+	warnPct := d.absDDriftWarnPct
+	d.mu.Unlock()
+
+	meta["absd_baseline"] = baseline
+	meta["absd_samples"] = samples
+	if !ok {
+		meta["absd_drift_pct"] = nil
+		return nil
+	}
+	meta["absd_drift_pct"] = pct
+
+	if warnPct > 0 && (pct > warnPct || pct < -warnPct) {
+		notes = append(notes, fmt.Sprintf(
+			"Raw |U−V| has drifted %.1f%% from its baseline %.3f mV (threshold %.1f%%). Check the probe for fouling and the cable before trusting conductivity.",
+			pct, baseline, warnPct))
+	}
+	return notes
+}
@@ -0,0 +1,81 @@
+package robotank_conductivity
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/reef-pi/hal"
+)
+
+func TestAbsDTracker(t *testing.T) {
+	var tr absDTracker
+	tr.add(100)
+	if tr.baseline != 100 || tr.samples != 1 {
+		t.Fatalf("first sample must seed the baseline: %+v", tr)
+	}
+	for i := 1; i < absDRecentWindow-1; i++ {
+		tr.add(100)
+	}
+	if _, ok := tr.driftPct(); ok {
+		t.Fatal("drift reported before the recent window is full")
+	}
+	tr.add(100)
+	if pct, ok := tr.driftPct(); !ok || pct != 0 {
+		t.Fatalf("steady absD: drift %v ok=%v, want 0 true", pct, ok)
+	}
+
+	// A step up fills the recent window at once; the slow baseline barely moves.
+	for i := 0; i < absDRecentWindow; i++ {
+		tr.add(120)
+	}
+	wantBase := 120 - 20*math.Pow(1-absDBaselineAlpha, absDRecentWindow)
+	if math.Abs(tr.baseline-wantBase) > 1e-9 {
+		t.Errorf("baseline %v, want %v", tr.baseline, wantBase)
+	}
+	wantPct := (120 - wantBase) / wantBase * 100
+	if pct, _ := tr.driftPct(); math.Abs(pct-wantPct) > 1e-9 {
+		t.Errorf("drift %v%%, want %v%%", pct, wantPct)
+	}
+	if len(tr.recent) != absDRecentWindow {
+		t.Errorf("recent window grew to %d", len(tr.recent))
+	}
+}
+
+func TestDriftMetaNote(t *testing.T) {
+	d := &RoboTankConductivity{absDDriftWarnPct: defaultAbsDDriftWarnPct}
+	for i := 0; i < absDRecentWindow; i++ {
+		d.absD.add(100)
+	}
+	if notes := d.driftMeta(map[string]any{}); len(notes) != 0 {
+		t.Errorf("no drift must not warn: %q", notes)
+	}
+	for i := 0; i < absDRecentWindow; i++ {
+		d.absD.add(120) // ~19.5% above the baseline
+	}
+	meta := map[string]any{}
+	if notes := d.driftMeta(meta); len(notes) != 1 || !strings.Contains(notes[0], "fouling") {
+		t.Errorf("drift past %v%% must warn: %q", defaultAbsDDriftWarnPct, notes)
+	}
+	if meta["absd_samples"] != 2*absDRecentWindow {
+		t.Errorf("absd_samples %v", meta["absd_samples"])
+	}
+
+	d.absDDriftWarnPct = 0
+	if notes := d.driftMeta(map[string]any{}); len(notes) != 0 {
+		t.Errorf("threshold 0 disables the note: %q", notes)
+	}
+}
+
+func TestCalibrateResetsAbsDBaseline(t *testing.T) {
+	d := newTestDriver(t, &fakeBoard{resp: map[string]string{}}, nil)
+	for i := 0; i < absDRecentWindow; i++ {
+		d.absD.add(100)
+	}
+	if err := d.pins[0].Calibrate([]hal.Measurement{{Expected: 0, Observed: 95}}); err != nil {
+		t.Fatal(err)
+	}
+	if d.absD.samples != 0 || d.absD.baseline != 0 || len(d.absD.recent) != 0 {
+		t.Errorf("Calibrate kept the old baseline: %+v", d.absD)
+	}
+}
@@ -2,6 +2,7 @@
 package robotank_conductivity
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -11,11 +12,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/reef-pi/drivers/i2creg"
 	"github.com/reef-pi/hal"
 	"github.com/reef-pi/rpi/i2c"
 )
 
 const (
+	driverType = "robotank-conductivity" // stable identifier, see DriverType
 	driverName = "Robo-Tank Conductivity Circuit"
 
 	// Fixed constants for THIS driver
@@ -27,16 +30,43 @@ const (
 
 	// If we haven't received a temp update in this long, stop using it
 	tempStaleAfter = 2 * time.Minute
+
+	// Compensation status reported in Snapshot meta (comp_status): why the
+	// injected temperature is or isn't used. temp_source tells whether the
+	// board temperature stood in.
+	compActive  = "active"
+	compNoTemp  = "disabled: no temp"
+	compStale   = "disabled: stale"
+	compInvalid = "disabled: invalid"
+	compBoard   = "active: board temp" // board temperature stood in (temp_source=board)
+
+	// Injected temperatures outside this range (°C) are not water temperatures
+	// and disable compensation like the sentinel does. Cold is fine: 0..2°C
+	// from a chilled RODI bench compensates normally.
+	minWaterTempC = -2.0 // seawater freezes near -1.9°C
+	maxWaterTempC = 60.0
 	
 )
 
+// TempUnknownC is the SetTemperatureC sentinel for "no temperature available":
+// compensation is skipped (or falls back to the board temperature). Exactly -1
+// is still read as unknown for hosts that send the old sentinel.
+const (
+	TempUnknownC       = -999.0
+	legacyTempUnknownC = -1.0
+)
+
+// errUncalibrated is returned when AbsD_RODI/AbsD_Std have not been set.
+// We refuse to report a conductivity rather than guess from sample numbers.
+var errUncalibrated = errors.New("missing calibration (AbsD_RODI and AbsD_Std must be set)")
+
 // firstNumRe finds the first number-like token in a response string.
 // Handles things like: "U,14.322", "14.322,OK", "U=14,322", "OK 14.322"
 var firstNumRe = regexp.MustCompile(`[-+]?\d+(?:[.,]\d+)?`)
 
 // RoboTankConductivity exposes 2 analog channels:
 // 0 = conductivity (uS/cm) compensated to 25C when temperature is available
-// 1 = salinity (ppt) derived from channel 0
+// 1 = salinity derived from channel 0, in ppt, PSU or SG (Ch1Unit, see salinity.go)
 //
 // Calibration assumptions:
 // - Reference temperature is fixed at 25°C
@@ -47,6 +77,12 @@ type RoboTankConductivity struct {
 	delay time.Duration
 	meta  hal.Metadata
 
+	// claim is this instance's i2creg address claim (nil when config-only).
+	claim *i2creg.Handle
+
+	// retryDelay spaces re-reads of a bad/empty response.
+	retryDelay time.Duration
+
 	// Serialize *all* I2C command/response sequences and guard shared state.
 	mu sync.Mutex
 
@@ -62,10 +98,45 @@ type RoboTankConductivity struct {
 	refTempC float64 // fixed at 25C
 
 	// temperature (injected by reef-pi temp subsystem)
-	// If temp is -1, it is ignored and treated as 25C.
+	// TempUnknownC (or an implausible value) is ignored and treated as 25C.
 	tempC         float64
 	tempUpdatedAt time.Time
 	tempValid     bool
+	compStatus    string
+
+	// Optional board temperature (see boardtemp.go): used when no valid injected
+	// temperature exists. tempSource records what the last compensation used.
+	readBoardTemp bool
+	boardTempCmd  string
+	boardTempC    float64
+	boardTempAt   time.Time
+	tempSource    string
+
+	// waterType is the last water type sent to the board (see watertype.go),
+	// waterTypeUnset until SetWaterTypeLive succeeds.
+	waterType   int
+	waterTypeAt time.Time
+
+	// absD tracks a slow baseline of |U−V| to flag probe fouling (see drift.go).
+	// absDDriftWarnPct adds a snapshot note past this drift (0 disables).
+	absD             absDTracker
+	absDDriftWarnPct float64
+
+	// ch1Unit selects what channel 1 reports (ch1Unit* consts).
+	ch1Unit string
+
+	// confirmReads takes extra absDiff reads to reject a one-off spike
+	// (see confirm.go). confirmDevPct is the last disagreement between the
+	// first two reads; confirmThirds counts third reads. Guarded by mu.
+	confirmReads  bool
+	confirmTolPct float64
+	confirmDevPct float64
+	confirmThirds int
+
+	// firmware caches the board's "H" answer; firmwareAt is the last query
+	// attempt (see firmware.go). Guarded by mu.
+	firmware   string
+	firmwareAt time.Time
 
 	debug bool
 
@@ -76,12 +147,18 @@ type RoboTankConductivity struct {
 // rtPin is a lightweight wrapper that exposes channel 0/1
 type rtPin struct {
 	parent *RoboTankConductivity
-	ch     int // 0=uS/cm, 1=ppt
+	ch     int // 0=uS/cm, 1=salinity (Ch1Unit)
 }
 
 // Implement TemperatureSetter on the pin, forwarding to the parent driver.
 func (p *rtPin) SetTemperatureC(tempC float64) { p.parent.SetTemperatureC(tempC) }
 
+// CompensationState forwards to the parent driver, so hosts holding a pin can
+// type-assert for it too.
+func (p *rtPin) CompensationState() (active bool, reason string, tempC float64, age time.Duration) {
+	return p.parent.CompensationState()
+}
+
 // ---------------- I2C helpers ----------------
 
 func (d *RoboTankConductivity) drain() {
@@ -163,7 +240,7 @@ func (d *RoboTankConductivity) readFloat(cmd string) (float64, error) {
 		resp, err := d.read()
 		if err != nil {
 			lastErr = err
-			time.Sleep(50 * time.Millisecond)
+			time.Sleep(d.retryDelay)
 			continue
 		}
 
@@ -177,7 +254,7 @@ func (d *RoboTankConductivity) readFloat(cmd string) (float64, error) {
 		}
 
 		lastErr = err
-		time.Sleep(50 * time.Millisecond)
+		time.Sleep(d.retryDelay)
 	}
 
 	return 0, fmt.Errorf("cmd=%q: %v", cmd, lastErr)
@@ -198,12 +275,8 @@ func (d *RoboTankConductivity) Firmware() (string, error) {
 	return d.read()
 }
 
-func (d *RoboTankConductivity) SetWaterType(wt int) error {
-	d.mu.Lock()
-	defer d.mu.Unlock()
-
-	return d.command(fmt.Sprintf("W,%d", wt))
-}
+// SetWaterType is kept for existing callers; see SetWaterTypeLive.
+func (d *RoboTankConductivity) SetWaterType(wt int) error { return d.SetWaterTypeLive(wt) }
 
 // ---------------- Temperature hook ----------------
 
@@ -213,10 +286,11 @@ func (d *RoboTankConductivity) SetTemperatureC(tempC float64) {
 
 	d.tempUpdatedAt = time.Now()
 
-	// Sentinel: -1 means "unknown", assume ref temp (25C) and don't compensate.
-	if tempC < 0 {
+	// Sentinel or implausible value: assume ref temp (25C) and don't compensate.
+	if !validWaterTempC(tempC) {
 		d.tempValid = false
 		d.tempC = d.refTempC
+		d.compStatus = compInvalid
 		if d.debug {
 			log.Printf("robotank_cond addr=%d SetTemperatureC: invalid/sentinel %.2f -> assuming %.2fC (no temp comp)",
 				d.addr, tempC, d.refTempC)
@@ -234,6 +308,49 @@ func (d *RoboTankConductivity) SetTemperatureC(tempC float64) {
 	}
 }
 
+// CompensationState reports whether readings are temperature compensated right
+// now, for host logic that should not trust uncompensated conductivity (e.g. to
+// widen alarm bands). reason is one of the comp_status values, or
+// "active: board temp" when ReadBoardTemp stands in for a missing injected
+// temperature. tempC and age describe the temperature in use, or the last one
+// injected when compensation is off (age 0 if none ever was).
+//
+// Staleness is judged against the current time, so a temperature that went
+// stale since the last read already reports inactive.
+func (d *RoboTankConductivity) CompensationState() (active bool, reason string, tempC float64, age time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.compensationStateLocked()
+}
+
+// compensationStateLocked backs CompensationState and the Snapshot meta, so
+// both report the same state. Caller holds d.mu.
+func (d *RoboTankConductivity) compensationStateLocked() (active bool, reason string, tempC float64, age time.Duration) {
+	if !d.tempUpdatedAt.IsZero() {
+		age = time.Since(d.tempUpdatedAt)
+	}
+	switch {
+	case d.tempValid && !d.tempUpdatedAt.IsZero() && age <= tempStaleAfter:
+		return true, compActive, d.tempC, age
+	case d.tempSource == tempSourceBoard && !d.boardTempAt.IsZero():
+		return true, compBoard, d.boardTempC, time.Since(d.boardTempAt)
+	case d.tempValid && !d.tempUpdatedAt.IsZero():
+		return false, compStale, d.tempC, age
+	case d.compStatus == "":
+		return false, compNoTemp, d.tempC, age
+	}
+	return false, d.compStatus, d.tempC, age
+}
+
+// validWaterTempC reports whether tempC is a usable injected temperature, i.e.
+// neither a sentinel (TempUnknownC, legacy -1) nor outside minWaterTempC..maxWaterTempC.
+func validWaterTempC(tempC float64) bool {
+	if tempC == TempUnknownC || tempC == legacyTempUnknownC || math.IsNaN(tempC) {
+		return false
+	}
+	return tempC >= minWaterTempC && tempC <= maxWaterTempC
+}
+
 // ---------------- Math / conversion ----------------
 
 func (d *RoboTankConductivity) absDiff() (ad, u, v float64, err error) {
@@ -249,26 +366,12 @@ func (d *RoboTankConductivity) absDiff() (ad, u, v float64, err error) {
 	return ad, u, v, nil
 }
 
+// usFromAbsD applies the driver's calibration anchors (see USFromAbsD).
 func (d *RoboTankConductivity) usFromAbsD(ad float64) (float64, error) {
-	if d.absDFresh <= 0 || d.absDStd <= 0 {
-		return 0, fmt.Errorf("%s: missing calibration (AbsD_RODI and AbsD_Std must be set)", driverName)
-	}
-	if d.absDFresh == d.absDStd {
-		return 0, fmt.Errorf("%s: invalid calibration (AbsD_RODI == AbsD_Std)", driverName)
-	}
-
-	// From your observations: absD is BIG in fresh and SMALL in salt.
-	x := (d.absDFresh - ad) / (d.absDFresh - d.absDStd)
-
-	// Clamp: allow slight overshoot but prevent spikes
-	if x < 0 {
-		x = 0
-	}
-	if x > 1.2 {
-		x = 1.2
-	}
-
-	return x * d.refUS, nil
+	d.mu.Lock()
+	absFresh, absStd, refUS := d.absDFresh, d.absDStd, d.refUS
+	d.mu.Unlock()
+	return USFromAbsD(ad, absFresh, absStd, refUS)
 }
 
 // Convert measured uS at current temp to uS at refTempC using linear coefficient
@@ -290,7 +393,7 @@ func (d *RoboTankConductivity) tempCompToRef(us float64) float64 {
 			log.Printf("robotank_cond addr=%d tempComp: no valid temp -> assume %.2fC (returning us_meas=%.2f)",
 				addr, refTempC, us)
 		}
-		return us
+		return d.boardTempComp(us)
 	}
 
 	// If chemistry stops injecting temp (e.g. temp_sensor_id=-1) but we never got a sentinel,
@@ -302,8 +405,9 @@ func (d *RoboTankConductivity) tempCompToRef(us float64) float64 {
 		d.mu.Lock()
 		d.tempValid = false
 		d.tempC = d.refTempC
+		d.compStatus = compNoTemp
 		d.mu.Unlock()
-		return us
+		return d.boardTempComp(us)
 	}
 
 	age := time.Since(updatedAt)
@@ -313,25 +417,25 @@ func (d *RoboTankConductivity) tempCompToRef(us float64) float64 {
 		d.mu.Lock()
 		d.tempValid = false
 		d.tempC = d.refTempC
+		d.compStatus = compStale
 		d.mu.Unlock()
-		return us
+		return d.boardTempComp(us)
 	} else if debug {
 		log.Printf("robotank_cond addr=%d temp age=%v (tempC=%.2f)", addr, age, tempC)
 	}
 
-	den := 1.0 + alpha*(tempC-refTempC)
-	if den <= 0.1 {
-		if debug {
-			log.Printf("robotank_cond addr=%d tempComp: den clamped (den=%.5f)", addr, den)
-		}
-		den = 0.1
-	}
-
-	usRef := us / den
+	usRef, clamped := TempCompToRef(us, tempC, refTempC, alpha)
+	d.setTempSource(tempSourceInjected)
+	d.mu.Lock()
+	d.compStatus = compActive
+	d.mu.Unlock()
 
 	if debug {
-		log.Printf("robotank_cond addr=%d tempComp: us_meas=%.2f at %.2fC -> us_ref=%.2f at %.2fC (den=%.5f alpha=%.6f)",
-			addr, us, tempC, usRef, refTempC, den, alpha)
+		if clamped {
+			log.Printf("robotank_cond addr=%d tempComp: den clamped (den=%.5f)", addr, 1.0+alpha*(tempC-refTempC))
+		}
+		log.Printf("robotank_cond addr=%d tempComp: us_meas=%.2f at %.2fC -> us_ref=%.2f at %.2fC (alpha=%.6f)",
+			addr, us, tempC, usRef, refTempC, alpha)
 	}
 	return usRef
 }
@@ -340,17 +444,18 @@ func (d *RoboTankConductivity) tempCompToRef(us float64) float64 {
 // 53000 uS/cm -> 35.0 ppt
 // ppt = usRef * (35 / 53000)
 func (d *RoboTankConductivity) pptFromUS(usRef float64) float64 {
-	return usRef * (35.0 / d.refUS)
+	return PPTFromUS(usRef, d.refUS)
 }
 
 func (d *RoboTankConductivity) compute() (usRef, u, v, ad float64, err error) {
-	ad, u, v, err = d.absDiff()
+	ad, u, v, err = d.measureAbsD()
 	if err != nil {
 		return 0, 0, 0, 0, err
 	}
 
 	// Read shared state for logging under lock (avoid races)
 	d.mu.Lock()
+	d.absD.add(ad)
 	absFresh := d.absDFresh
 	absStd := d.absDStd
 	refUS := d.refUS
@@ -413,7 +518,7 @@ func (p *rtPin) Value() (float64, error) {
 	if p.ch == 0 {
 		return usRef, nil
 	}
-	return ppt, nil
+	return p.parent.salinity(usRef), nil
 }
 
 func (p *rtPin) Measure() (float64, error) { return p.Value() }
@@ -430,9 +535,9 @@ func (p *rtPin) Calibrate(ms []hal.Measurement) error {
 		exp := m.Expected
 		obs := m.Observed
 
-		// If Observed is zero, fallback to live absDiff.
+		// If Observed is zero, fallback to live absDiff (confirmed with ConfirmReads).
 		if obs == 0 {
-			ad, _, _, err := p.parent.absDiff()
+			ad, _, _, err := p.parent.measureAbsD()
 			if err != nil {
 				return err
 			}
@@ -458,6 +563,18 @@ func (p *rtPin) Calibrate(ms []hal.Measurement) error {
 		}
 	}
 
+	// A recalibration usually follows cleaning; start a fresh absD baseline.
+	p.parent.mu.Lock()
+	p.parent.absD.reset()
+	absFresh, absStd := p.parent.absDFresh, p.parent.absDStd
+	p.parent.mu.Unlock()
+
+	if absFresh > 0 && absStd > 0 {
+		score, grade := CalibrationQuality(absFresh, absStd)
+		log.Printf("robotank_cond calibration quality=%.0f (%s) AbsD_RODI=%.6f AbsD_Std=%.6f",
+			score, grade, absFresh, absStd)
+	}
+
 	return nil
 }
 
@@ -465,7 +582,7 @@ func (p *rtPin) Name() string {
 	if p.ch == 0 {
 		return driverName + " (uS/cm)"
 	}
-	return driverName + " (ppt)"
+	return driverName + " (" + p.parent.ch1Info().unit + ")"
 }
 
 func (p *rtPin) Number() int { return p.ch }
@@ -479,19 +596,29 @@ func (p *rtPin) Metadata() hal.Metadata { return p.parent.meta }
 // Snapshot Function
 func (p *rtPin) Snapshot() (hal.Snapshot, error) {
 	usRef, u, v, ad, err := p.parent.compute()
+	if errors.Is(err, errUncalibrated) {
+		return p.uncalibratedSnapshot(u, v, ad), nil
+	}
 	if err != nil {
 		return hal.Snapshot{}, err
 	}
 	ppt := p.parent.pptFromUS(usRef)
+	ch1 := p.parent.ch1Info()
+
+	p.parent.mu.Lock()
+	compOn, compReason, tempC, _ := p.parent.compensationStateLocked()
+	p.parent.mu.Unlock()
 
 	var primary float64
 	var unit string
+	decimals := 3
 	if p.ch == 0 {
 		primary = usRef
 		unit = "uS/cm"
 	} else {
-		primary = ppt
-		unit = "ppt"
+		primary = p.parent.salinity(usRef)
+		unit = ch1.unit
+		decimals = ch1.decimals
 	}
 
 	secondary := func() []string {
@@ -516,7 +643,7 @@ func (p *rtPin) Snapshot() (hal.Snapshot, error) {
 			if p.ch == 0 {
 				return "Conductivity (uS/cm @ 25°C)"
 			}
-			return "Salinity (ppt)"
+			return ch1.name
 		}(),
 		"abs_d":  "|U−V| (mV)",
 		"U":      "U (mV)",
@@ -527,20 +654,30 @@ func (p *rtPin) Snapshot() (hal.Snapshot, error) {
 	}
 
 	help := map[string]any{
+		"value": func() string {
+			if p.ch == 0 {
+				return "Conductivity compensated to 25°C."
+			}
+			return ch1.help
+		}(),
 		"abs_d":  "Raw differential used for calibration/conversion (absolute difference of U and V).",
 		"us_ref": "Conductivity compensated to 25°C when a valid temperature is available. If temp updates stop for >2 minutes, compensation is disabled.",
 		"ppt":    "Salinity derived from conductivity using 35 ppt @ 53,000 µS/cm.",
-		"tempC":  "Last injected water temperature. If unknown or stale, driver assumes 25°C and disables compensation.",
+		"tempC":  "Temperature used for compensation (injected, or the board's own with ReadBoardTemp). If unknown or stale, driver assumes 25°C and disables compensation.",
 	}
 
 	meta := map[string]any{
-		"channel": p.ch,
+		"channel":  p.ch,
+		"ch1_unit": p.parent.ch1Unit,
 
 		"raw_signal_key":       "abs_d",
 		"primary_signal_key":   "value",
 		"secondary_signal_keys": secondary,
 
-		"temp_valid": p.parent.tempValid,
+		"temp_valid":  compOn,
+		"comp_status": compReason,
+
+		"firmware": p.parent.cachedFirmware(),
 
 		"ui_note": fmt.Sprintf(
 			"Assumes %.2f°C reference temperature. Standard calibration solution is %.0f µS/cm. Temp compensation uses AlphaPerC=%.6f and is applied only when temp is available and recent.",
@@ -548,7 +685,7 @@ func (p *rtPin) Snapshot() (hal.Snapshot, error) {
 		),
 
 		"signal_decimals": map[string]any{
-			"value":  3,
+			"value":  decimals,
 			"abs_d":  3,
 			"U":      3,
 			"V":      3,
@@ -561,6 +698,11 @@ func (p *rtPin) Snapshot() (hal.Snapshot, error) {
 		"display_names": names,
 		"display_help":  help,
 	}
+	p.parent.boardTempMeta(meta)
+	p.parent.waterTypeMeta(meta)
+	p.parent.confirmMeta(meta)
+	notes := p.parent.driftMeta(meta)
+	notes = append(notes, p.parent.calQualityMeta(meta)...)
 
 	s := hal.Snapshot{
 		Value: primary,
@@ -571,23 +713,71 @@ func (p *rtPin) Snapshot() (hal.Snapshot, error) {
 			"abs_d":  {Now: ad, Unit: "mV"},
 			"us_ref": {Now: usRef, Unit: "uS/cm"},
 			"ppt":    {Now: ppt, Unit: "ppt"},
-			"tempC":  {Now: p.parent.tempC, Unit: "C"},
+			"tempC":  {Now: tempC, Unit: "C"},
 		},
-		Meta: meta,
+		Meta:  meta,
+		Notes: notes,
 	}
 
 	return s, nil
 }
 
+// uncalibratedSnapshot still exposes the raw |U−V| so the calibration wizard
+// can capture the RODI/standard points, but reports no conductivity.
+func (p *rtPin) uncalibratedSnapshot(u, v, ad float64) hal.Snapshot {
+	unit := "uS/cm"
+	if p.ch != 0 {
+		unit = p.parent.ch1Info().unit
+	}
+
+	meta := map[string]any{
+		"channel":               p.ch,
+		"calibrated":            false,
+		"raw_signal_key":        "abs_d",
+		"primary_signal_key":    "value",
+		"secondary_signal_keys": []string{"U", "V"},
+		"display_names": map[string]any{
+			"value": "Not calibrated",
+			"abs_d": "|U−V| (mV)",
+			"U":     "U (mV)",
+			"V":     "V (mV)",
+		},
+	}
+	p.parent.waterTypeMeta(meta)
+	driftNotes := p.parent.driftMeta(meta)
+
+	return hal.Snapshot{
+		Value: 0,
+		Unit:  unit,
+		Signals: map[string]hal.Signal{
+			"U":     {Now: u, Unit: "mV"},
+			"V":     {Now: v, Unit: "mV"},
+			"abs_d": {Now: ad, Unit: "mV"},
+		},
+		Meta: meta,
+		Notes: append([]string{
+			"Uncalibrated: AbsD_RODI and AbsD_Std are not set. Value is not a conductivity reading.",
+			"Calibrate with Expected=0 in RO/DI water and Expected=53000 in the standard solution, or enter both AbsD values in the driver config.",
+		}, driftNotes...),
+	}
+}
+
 // ---------------- hal.Driver / plumbing ----------------
 
 func (d *RoboTankConductivity) Name() string           { return driverName }
-func (d *RoboTankConductivity) Close() error           { return nil }
 func (d *RoboTankConductivity) Metadata() hal.Metadata { return d.meta }
 
+func (d *RoboTankConductivity) Close() error {
+	d.claim.Release()
+	return nil
+}
+
+// DriverType returns the stable driver kind for host-side routing.
+func (d *RoboTankConductivity) DriverType() string { return driverType }
+
 func (d *RoboTankConductivity) AnalogInputPin(n int) (hal.AnalogInputPin, error) {
 	if n < 0 || n > 1 {
-		return nil, fmt.Errorf("%s supports channels 0(uS/cm) and 1(ppt). Asked:%d", driverName, n)
+		return nil, fmt.Errorf("%s supports channels 0(uS/cm) and 1(%s). Asked:%d", driverName, d.ch1Info().unit, n)
 	}
 	return d.pins[n], nil
 }
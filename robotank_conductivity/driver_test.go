@@ -0,0 +1,81 @@
+package robotank_conductivity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetTemperatureCSentinel(t *testing.T) {
+	cases := []struct {
+		tempC float64
+		valid bool
+	}{
+		{TempUnknownC, false},
+		{-1, false}, // legacy sentinel
+		{-40, false},
+		{120, false},
+		{0, true},
+		{2, true},
+		{-1.5, true},
+		{25.5, true},
+	}
+	for _, c := range cases {
+		d := &RoboTankConductivity{refTempC: fixedRefTempC}
+		d.SetTemperatureC(c.tempC)
+		if d.tempValid != c.valid {
+			t.Errorf("SetTemperatureC(%v): tempValid=%v, want %v", c.tempC, d.tempValid, c.valid)
+		}
+		if !c.valid && d.tempC != fixedRefTempC {
+			t.Errorf("SetTemperatureC(%v): tempC=%v, want ref %v", c.tempC, d.tempC, fixedRefTempC)
+		}
+	}
+}
+
+func TestCompStatus(t *testing.T) {
+	d := &RoboTankConductivity{refTempC: fixedRefTempC, alphaPerC: fixedAlphaPerC, compStatus: compNoTemp}
+	d.tempCompToRef(1000)
+	if d.compStatus != compNoTemp {
+		t.Errorf("no injection: comp_status=%q, want %q", d.compStatus, compNoTemp)
+	}
+
+	d.SetTemperatureC(26)
+	d.tempCompToRef(1000)
+	if d.compStatus != compActive {
+		t.Errorf("fresh temp: comp_status=%q, want %q", d.compStatus, compActive)
+	}
+
+	d.tempUpdatedAt = time.Now().Add(-2 * tempStaleAfter)
+	d.tempCompToRef(1000)
+	if d.compStatus != compStale {
+		t.Errorf("old temp: comp_status=%q, want %q", d.compStatus, compStale)
+	}
+
+	d.SetTemperatureC(TempUnknownC)
+	d.tempCompToRef(1000)
+	if d.compStatus != compInvalid {
+		t.Errorf("sentinel: comp_status=%q, want %q", d.compStatus, compInvalid)
+	}
+}
+
+func TestCompensationState(t *testing.T) {
+	d := &RoboTankConductivity{refTempC: fixedRefTempC, alphaPerC: fixedAlphaPerC, compStatus: compNoTemp}
+	if active, reason, _, age := d.CompensationState(); active || reason != compNoTemp || age != 0 {
+		t.Errorf("no injection: active=%v reason=%q age=%v", active, reason, age)
+	}
+
+	d.SetTemperatureC(26)
+	if active, reason, tempC, _ := d.CompensationState(); !active || reason != compActive || tempC != 26 {
+		t.Errorf("fresh temp: active=%v reason=%q tempC=%v", active, reason, tempC)
+	}
+
+	// Stale without any read in between.
+	d.tempUpdatedAt = time.Now().Add(-2 * tempStaleAfter)
+	if active, reason, _, age := d.CompensationState(); active || reason != compStale || age < tempStaleAfter {
+		t.Errorf("old temp: active=%v reason=%q age=%v", active, reason, age)
+	}
+
+	d.SetTemperatureC(TempUnknownC)
+	if active, reason, _, _ := d.CompensationState(); active || reason != compInvalid {
+		t.Errorf("sentinel: active=%v reason=%q", active, reason)
+	}
+}
@@ -10,8 +10,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/reef-pi/drivers/i2creg"
+	"github.com/reef-pi/drivers/nobus"
 	"github.com/reef-pi/hal"
-	"github.com/reef-pi/rpi/i2c"
 )
 
 type factory struct {
@@ -25,10 +26,34 @@ const (
 	absDStdParam   = "AbsD_Std"
 	alphaPerCParam = "AlphaPerC"
 	debugParam     = "Debug"
+
+	// Advanced I2C timing knobs (bounded; defaults match the tuned values)
+	readDelayMsParam  = "ReadDelayMs"
+	retryDelayMsParam = "RetryDelayMs"
+
+	absDDriftWarnPctParam = "AbsDDriftWarnPct"
+
+	// Board temperature fallback when reef-pi injects none
+	readBoardTempParam    = "ReadBoardTemp"
+	boardTempCommandParam = "BoardTempCommand"
+
+	// What channel 1 reports: ppt (default), psu or sg
+	ch1UnitParam = "Ch1Unit"
+
+	// Confirm each |U−V| with a second read, a third on disagreement (see confirm.go)
+	confirmReadsParam  = "ConfirmReads"
+	confirmTolPctParam = "ConfirmTolPct"
 )
 
-// fixed, non-configurable read delay
-const fixedDelayMs = 200
+// Default command->response delay and retry spacing, with the allowed ranges.
+const (
+	defaultDelayMs      = 200
+	minDelayMs          = 50
+	maxDelayMs          = 2000
+	defaultRetryDelayMs = 50
+	minRetryDelayMs     = 10
+	maxRetryDelayMs     = 1000
+)
 
 var f *factory
 var once sync.Once
@@ -38,7 +63,7 @@ func Factory() hal.DriverFactory {
 		f = &factory{
 			meta: hal.Metadata{
 				Name:        driverName,
-				Description: "Robo-Tank conductivity circuit (µS/cm + ppt). Assumes 25°C reference and 53,000 µS/cm standard calibration solution.",
+				Description: "Robo-Tank conductivity circuit (µS/cm + salinity in ppt, PSU or SG). Assumes 25°C reference and 53,000 µS/cm standard calibration solution.",
 				Capabilities: []hal.Capability{
 					hal.AnalogInput,
 				},
@@ -55,15 +80,15 @@ func Factory() hal.DriverFactory {
 					Name:        absDRODIParam,
 					Type:        hal.Decimal,
 					Order:       1,
-					Default:     1010,
-					Description: "Absolute |U−V| reading (mV) measured in RO/DI water.",
+					Default:     0.0,
+					Description: "Absolute |U−V| reading (mV) measured in RO/DI water. 0 = not calibrated yet (no conductivity is reported until set).",
 				},
 				{
 					Name:        absDStdParam,
 					Type:        hal.Decimal,
 					Order:       2,
-					Default:     24.328,
-					Description: "Absolute |U−V| reading (mV) measured in 53,000 µS/cm calibration solution at 25°C.",
+					Default:     0.0,
+					Description: "Absolute |U−V| reading (mV) measured in 53,000 µS/cm calibration solution at 25°C. 0 = not calibrated yet.",
 				},
 				{
 					Name:        alphaPerCParam,
@@ -79,6 +104,62 @@ func Factory() hal.DriverFactory {
 					Default:     false,
 					Description: "Enable verbose logging of raw readings, temperature compensation, and scaling calculations.",
 				},
+				{
+					Name:        readDelayMsParam,
+					Type:        hal.Integer,
+					Order:       5,
+					Default:     defaultDelayMs,
+					Description: "Advanced: wait (ms) between command write and response read. 50..2000. Only raise on long/busy buses.",
+				},
+				{
+					Name:        retryDelayMsParam,
+					Type:        hal.Integer,
+					Order:       6,
+					Default:     defaultRetryDelayMs,
+					Description: "Advanced: wait (ms) before re-reading a bad/empty response. 10..1000.",
+				},
+				{
+					Name:        absDDriftWarnPctParam,
+					Type:        hal.Decimal,
+					Order:       7,
+					Default:     defaultAbsDDriftWarnPct,
+					Description: "Warn in the snapshot when raw |U−V| drifts more than this percent from its long-term baseline (early sign of probe fouling). 0 disables. 0..100.",
+				},
+				{
+					Name:        readBoardTempParam,
+					Type:        hal.Boolean,
+					Order:       8,
+					Default:     false,
+					Description: "Read temperature from the board (BoardTempCommand) when reef-pi injects none or it is stale. Falls back to 25°C if the board cannot answer.",
+				},
+				{
+					Name:        boardTempCommandParam,
+					Type:        hal.String,
+					Order:       9,
+					Default:     defaultBoardTempCommand,
+					Description: "Command that returns the board temperature in °C (Atlas-style T,? by default). Only used with ReadBoardTemp.",
+				},
+				{
+					Name:        ch1UnitParam,
+					Type:        hal.String,
+					Order:       10,
+					Default:     ch1UnitPPT,
+					Description: "What channel 1 reports: ppt (salinity, parts per thousand), psu (practical salinity, PSS-78) or sg (specific gravity @ 25°C).",
+				},
+				{
+					Name:        confirmReadsParam,
+					Type:        hal.Boolean,
+					Order:       11,
+					Default:     false,
+					Description: "Take a second |U−V| read to confirm each measurement; if they differ by more than ConfirmTolPct a third is read and the median used, else the two are averaged. Rejects one-off spikes at 2-3x the I²C traffic.",
+				},
+				{
+					Name:        confirmTolPctParam,
+					Type:        hal.Decimal,
+					Order:       12,
+					Default:     defaultConfirmTolPct,
+					Description: "Largest difference (percent) between the two confirmation reads that is averaged without a third read. >0..50. Only used with ConfirmReads.",
+				},
 			},
 		}
 	})
@@ -99,21 +180,20 @@ func (f *factory) ValidateParameters(parameters map[string]interface{}) (bool, m
     return false, failures
   }
 
-  val, ok := toInt(address)
-  if !ok {
-    failures[addressParam] = append(failures[addressParam], "Address must be an integer")
-  } else if val < 0 || val > 127 {
-    failures[addressParam] = append(failures[addressParam], "Address must be 0..127 (7-bit)")
+  if _, err := i2creg.ParseI2CAddress(address); err != nil {
+    failures[addressParam] = append(failures[addressParam], err.Error())
   }
 
   absRODI := getFloatAny(parameters, f.defaultFloatParam(absDRODIParam, 0), absDRODIParam)
   absSTD  := getFloatAny(parameters, f.defaultFloatParam(absDStdParam, 0),  absDStdParam)
 
-  if absRODI <= 0 {
-    failures[absDRODIParam] = append(failures[absDRODIParam], "AbsD_RODI must be > 0")
+  // 0 means "not calibrated yet": the driver loads so the calibration wizard can
+  // capture both points, but it reports an error instead of a conductivity.
+  if absRODI < 0 {
+    failures[absDRODIParam] = append(failures[absDRODIParam], "AbsD_RODI must be > 0 (or 0 if not calibrated yet)")
   }
-  if absSTD <= 0 {
-    failures[absDStdParam] = append(failures[absDStdParam], "AbsD_Std must be > 0")
+  if absSTD < 0 {
+    failures[absDStdParam] = append(failures[absDStdParam], "AbsD_Std must be > 0 (or 0 if not calibrated yet)")
   }
   if absRODI > 0 && absSTD > 0 && absRODI == absSTD {
     failures[absDStdParam] = append(failures[absDStdParam], "AbsD_RODI and AbsD_Std must be different")
@@ -126,6 +206,40 @@ func (f *factory) ValidateParameters(parameters map[string]interface{}) (bool, m
     failures[alphaPerCParam] = append(failures[alphaPerCParam], "AlphaPerC is unusually high (expected ~0.0 to 0.05 per °C)")
   }
 
+  if v, ok := getAny(parameters, readDelayMsParam); ok {
+    if ms, ok := toInt(v); !ok || ms < minDelayMs || ms > maxDelayMs {
+      failures[readDelayMsParam] = append(failures[readDelayMsParam], "ReadDelayMs must be an integer 50..2000")
+    }
+  }
+  if v, ok := getAny(parameters, retryDelayMsParam); ok {
+    if ms, ok := toInt(v); !ok || ms < minRetryDelayMs || ms > maxRetryDelayMs {
+      failures[retryDelayMsParam] = append(failures[retryDelayMsParam], "RetryDelayMs must be an integer 10..1000")
+    }
+  }
+
+  drift := getFloatAny(parameters, f.defaultFloatParam(absDDriftWarnPctParam, defaultAbsDDriftWarnPct), absDDriftWarnPctParam)
+  if drift < 0 || drift > 100 {
+    failures[absDDriftWarnPctParam] = append(failures[absDDriftWarnPctParam], "AbsDDriftWarnPct must be 0..100 (0 disables)")
+  }
+
+  if v, ok := getAny(parameters, boardTempCommandParam); ok {
+    if s, _ := v.(string); strings.TrimSpace(s) == "" || len(s) > 31 {
+      failures[boardTempCommandParam] = append(failures[boardTempCommandParam], "BoardTempCommand must be 1..31 characters")
+    }
+  }
+
+  if v, ok := getAny(parameters, ch1UnitParam); ok {
+    s, _ := v.(string)
+    if _, known := ch1Units[strings.ToLower(strings.TrimSpace(s))]; !known && strings.TrimSpace(s) != "" {
+      failures[ch1UnitParam] = append(failures[ch1UnitParam], "Ch1Unit must be ppt, psu or sg")
+    }
+  }
+
+  tol := getFloatAny(parameters, defaultConfirmTolPct, confirmTolPctParam)
+  if tol <= 0 || tol > maxConfirmTolPct {
+    failures[confirmTolPctParam] = append(failures[confirmTolPctParam], "ConfirmTolPct must be >0 and <=50")
+  }
+
   return len(failures) == 0, failures
 }
 
@@ -139,13 +253,14 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
     log.Printf("robotank_cond NewDriver parameters:\n%s", string(b))
   }
 
-  bus, ok := hardwareResources.(i2c.Bus)
-  if !ok {
-    return nil, errors.New("robotank_cond: expected i2c.Bus hardware resource")
+  // A nil hardware resource gives a config-only driver (see nobus).
+  bus, err := nobus.I2C("robotank_cond", hardwareResources)
+  if err != nil {
+    return nil, err
   }
 
   addrRaw, _ := getAny(parameters, addressParam)
-  addrInt, _ := toInt(addrRaw)
+  addr, _ := i2creg.ParseI2CAddress(addrRaw)
 
   absRODI := getFloatAny(parameters, f.defaultFloatParam(absDRODIParam, 0), absDRODIParam)
   absSTD  := getFloatAny(parameters, f.defaultFloatParam(absDStdParam, 0),  absDStdParam)
@@ -154,14 +269,33 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
 
   debug := getBoolAny(parameters, f.defaultBoolParam(debugParam, false), debugParam)
 
+  delayMs := getIntAny(parameters, defaultDelayMs, readDelayMsParam)
+  retryDelayMs := getIntAny(parameters, defaultRetryDelayMs, retryDelayMsParam)
+
+  driftWarnPct := getFloatAny(parameters, f.defaultFloatParam(absDDriftWarnPctParam, defaultAbsDDriftWarnPct), absDDriftWarnPctParam)
+
+  readBoardTemp := getBoolAny(parameters, false, readBoardTempParam)
+  boardTempCmd := defaultBoardTempCommand
+  if v, ok := getAny(parameters, boardTempCommandParam); ok {
+    if s, ok := v.(string); ok && strings.TrimSpace(s) != "" {
+      boardTempCmd = strings.TrimSpace(s)
+    }
+  }
+
+  ch1Unit := ch1UnitPPT
+  if v, ok := getAny(parameters, ch1UnitParam); ok {
+    if s, ok := v.(string); ok && strings.TrimSpace(s) != "" {
+      ch1Unit = strings.ToLower(strings.TrimSpace(s))
+    }
+  }
 
   refUS := fixedRefUS
   refTempC := fixedRefTempC
 
   d := &RoboTankConductivity{
-    addr:      byte(addrInt),
+    addr:      addr,
     bus:       bus,
-    delay:     time.Duration(fixedDelayMs) * time.Millisecond,
+    delay:     time.Duration(delayMs) * time.Millisecond,
     absDFresh: absRODI,
     absDStd:   absSTD,
 
@@ -170,7 +304,23 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
     alphaPerC: alphaPerC,
 
     tempC:     refTempC,
-    tempValid: false,
+    tempValid:  false,
+    compStatus: compNoTemp,
+
+    retryDelay: time.Duration(retryDelayMs) * time.Millisecond,
+
+    absDDriftWarnPct: driftWarnPct,
+
+    readBoardTemp: readBoardTemp,
+    boardTempCmd:  boardTempCmd,
+    tempSource:    tempSourceNone,
+
+    waterType: waterTypeUnset,
+
+    ch1Unit: ch1Unit,
+
+    confirmReads:  getBoolAny(parameters, false, confirmReadsParam),
+    confirmTolPct: getFloatAny(parameters, defaultConfirmTolPct, confirmTolPctParam),
 
     debug: debug,
     meta:  f.meta,
@@ -181,11 +331,31 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
     {parent: d, ch: 1},
   }
 
+  if !nobus.Is(bus) {
+    claim, err := i2creg.Claim(bus, d.addr, driverType)
+    if err != nil {
+      return nil, err
+    }
+    d.claim = claim
+    // Board identification for support; a failed query never fails construction.
+    if fw := d.queryFirmware(); fw != "" {
+      log.Printf("robotank_cond addr=%d firmware=%q", d.addr, fw)
+    }
+  }
+
   log.Printf(
-    "robotank_cond init addr=%d AbsD_RODI=%.3f AbsD_Std=%.3f RefUS=%.1f(fixed) RefTempC=%.2f(fixed) Alpha=%.6f(config) TempValid=%v TempC=%.2f(init) Delay=%v Debug=%v",
-    d.addr, d.absDFresh, d.absDStd, d.refUS, d.refTempC, d.alphaPerC, d.tempValid, d.tempC, d.delay, d.debug,
+    "robotank_cond init addr=%d AbsD_RODI=%.3f AbsD_Std=%.3f RefUS=%.1f(fixed) RefTempC=%.2f(fixed) Alpha=%.6f(config) TempValid=%v TempC=%.2f(init) Delay=%v RetryDelay=%v Debug=%v",
+    d.addr, d.absDFresh, d.absDStd, d.refUS, d.refTempC, d.alphaPerC, d.tempValid, d.tempC, d.delay, d.retryDelay, d.debug,
   )
 
+  if d.readBoardTemp {
+    log.Printf("robotank_cond addr=%d board temperature fallback enabled (cmd=%q)", d.addr, d.boardTempCmd)
+  }
+
+  if d.absDFresh <= 0 || d.absDStd <= 0 {
+    log.Printf("robotank_cond addr=%d WARNING: not calibrated (AbsD_RODI/AbsD_Std unset); readings will error until calibrated", d.addr)
+  }
+
   return d, nil
 }
 
@@ -0,0 +1,47 @@
+// firmware.go
+package robotank_conductivity
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/reef-pi/drivers/nobus"
+)
+
+// A failed or empty firmware query is retried from Snapshot at most this often,
+// so boards that don't answer "H" don't pay an extra transaction per snapshot.
+const firmwareRetryAfter = time.Minute
+
+// queryFirmware asks the board for its firmware string and caches it.
+// Errors are logged, never returned: identification is informational.
+func (d *RoboTankConductivity) queryFirmware() string {
+	d.mu.Lock()
+	d.firmwareAt = time.Now()
+	d.mu.Unlock()
+
+	fw, err := d.Firmware()
+	if err != nil {
+		log.Printf("robotank_cond addr=%d firmware query (H) failed: %v", d.addr, err)
+		return ""
+	}
+	fw = strings.TrimSpace(fw)
+
+	d.mu.Lock()
+	d.firmware = fw
+	d.mu.Unlock()
+	return fw
+}
+
+// cachedFirmware returns the cached firmware string, re-querying the board
+// when it is still unknown and the last attempt is older than firmwareRetryAfter.
+func (d *RoboTankConductivity) cachedFirmware() string {
+	d.mu.Lock()
+	fw, at := d.firmware, d.firmwareAt
+	d.mu.Unlock()
+
+	if fw != "" || nobus.Is(d.bus) || time.Since(at) < firmwareRetryAfter {
+		return fw
+	}
+	return d.queryFirmware()
+}
@@ -0,0 +1,58 @@
+package robotank_conductivity
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// countCmd returns how often cmd was sent.
+func countCmd(b *fakeBoard, cmd string) int {
+	n := 0
+	for _, c := range b.cmds {
+		if c == cmd {
+			n++
+		}
+	}
+	return n
+}
+
+func TestCachedFirmware(t *testing.T) {
+	bus := &fakeBoard{resp: map[string]string{"H": "RT-COND 1.2"}}
+	d := newTestDriver(t, bus, nil)
+	if got := d.cachedFirmware(); got != "RT-COND 1.2" {
+		t.Errorf("cachedFirmware = %q", got)
+	}
+	if n := countCmd(bus, "H"); n != 1 {
+		t.Errorf("H sent %d times, want once at construction", n)
+	}
+}
+
+func TestCachedFirmwareRetry(t *testing.T) {
+	// A failed query never fails construction.
+	bus := &fakeBoard{
+		resp:     map[string]string{"H": "RT-COND 1.2"},
+		writeErr: map[string]error{"H": errors.New("remote i/o error")},
+	}
+	d := newTestDriver(t, bus, nil)
+	if d.firmware != "" {
+		t.Fatalf("firmware %q after a failed query", d.firmware)
+	}
+
+	// Within firmwareRetryAfter the board is not asked again.
+	bus.writeErr = nil
+	if got := d.cachedFirmware(); got != "" || countCmd(bus, "H") != 0 {
+		t.Errorf("re-queried too soon: %q, %d H commands", got, countCmd(bus, "H"))
+	}
+
+	d.mu.Lock()
+	d.firmwareAt = time.Now().Add(-firmwareRetryAfter - time.Second)
+	d.mu.Unlock()
+	if got := d.cachedFirmware(); got != "RT-COND 1.2" || countCmd(bus, "H") != 1 {
+		t.Errorf("after firmwareRetryAfter: %q, %d H commands; want RT-COND 1.2, 1", got, countCmd(bus, "H"))
+	}
+	d.cachedFirmware()
+	if n := countCmd(bus, "H"); n != 1 {
+		t.Errorf("cached firmware re-queried: %d H commands", n)
+	}
+}
@@ -0,0 +1,78 @@
+// quality.go
+package robotank_conductivity
+
+import "fmt"
+
+const (
+	// Relative span (absFresh−absStd)/absFresh at or above which the
+	// calibration scores 100. RODI should read far above the standard.
+	calGoodRelSpan = 0.5
+
+	// Below this relative span the two anchors are too close to resolve
+	// conductivity well; usually a bad RODI point (probe not rinsed/dry).
+	calPoorRelSpan = 0.15
+)
+
+// Calibration quality grades.
+const (
+	calGradeGood     = "good"
+	calGradeFair     = "fair"
+	calGradePoor     = "poor"
+	calGradeInverted = "inverted"
+)
+
+// CalibrationQuality scores a two-point calibration 0..100 from how far apart
+// the anchors are relative to the RODI reading. absD falls as conductivity
+// rises, so absFresh must be larger than absStd; otherwise the grade is
+// "inverted" and the score 0.
+func CalibrationQuality(absFresh, absStd float64) (score float64, grade string) {
+	if absFresh <= 0 || absStd <= 0 || absFresh <= absStd {
+		return 0, calGradeInverted
+	}
+	rel := (absFresh - absStd) / absFresh
+	score = rel / calGoodRelSpan * 100
+	if score > 100 {
+		score = 100
+	}
+	switch {
+	case rel < calPoorRelSpan:
+		grade = calGradePoor
+	case rel < calGoodRelSpan:
+		grade = calGradeFair
+	default:
+		grade = calGradeGood
+	}
+	return score, grade
+}
+
+// calQualityMeta adds calibration quality meta and, for poor or inverted
+// calibrations, a note. Only meaningful once both anchors are set.
+func (d *RoboTankConductivity) calQualityMeta(meta map[string]any) (notes []string) {
+	d.mu.Lock()
+	absFresh, absStd, refUS := d.absDFresh, d.absDStd, d.refUS
+	d.mu.Unlock()
+
+	score, grade := CalibrationQuality(absFresh, absStd)
+	span := absFresh - absStd
+
+	meta["cal_quality"] = score
+	meta["cal_quality_grade"] = grade
+	meta["cal_span_mv"] = span
+	if span > 0 {
+		meta["cal_us_per_mv"] = refUS / span
+	} else {
+		meta["cal_us_per_mv"] = nil
+	}
+
+	switch grade {
+	case calGradeInverted:
+		notes = append(notes, fmt.Sprintf(
+			"Calibration looks inverted: AbsD_RODI=%.3f mV should be larger than AbsD_Std=%.3f mV. Redo both points.",
+			absFresh, absStd))
+	case calGradePoor:
+		notes = append(notes, fmt.Sprintf(
+			"Calibration span is small (AbsD_RODI=%.3f mV, AbsD_Std=%.3f mV, %.0f%% of RODI). Resolution is poor; recheck the RODI point with a rinsed probe.",
+			absFresh, absStd, span/absFresh*100))
+	}
+	return notes
+}
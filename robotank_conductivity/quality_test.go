@@ -0,0 +1,24 @@
+package robotank_conductivity
+
+import "testing"
+
+func TestCalibrationQuality(t *testing.T) {
+	cases := []struct {
+		fresh, std float64
+		score      float64
+		grade      string
+	}{
+		{100, 20, 100, calGradeGood},
+		{100, 70, 60, calGradeFair},
+		{100, 90, 20, calGradePoor},
+		{20, 100, 0, calGradeInverted},
+		{0, 20, 0, calGradeInverted},
+	}
+	for _, c := range cases {
+		score, grade := CalibrationQuality(c.fresh, c.std)
+		if grade != c.grade || score < c.score-1e-9 || score > c.score+1e-9 {
+			t.Errorf("CalibrationQuality(%v, %v) = %v %q, want %v %q",
+				c.fresh, c.std, score, grade, c.score, c.grade)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// salinity.go
+package robotank_conductivity
+
+// Units channel 1 can report (Ch1Unit). Channel 0 is always µS/cm.
+const (
+	ch1UnitPPT = "ppt"
+	ch1UnitPSU = "psu"
+	ch1UnitSG  = "sg"
+)
+
+// ch1UnitInfo describes how channel 1 is shown for one Ch1Unit.
+type ch1UnitInfo struct {
+	unit     string // snapshot unit
+	name     string // display name of the primary value
+	help     string
+	decimals int
+}
+
+var ch1Units = map[string]ch1UnitInfo{
+	ch1UnitPPT: {"ppt", "Salinity (ppt)", "Salinity derived from conductivity using 35 ppt @ 53,000 µS/cm.", 3},
+	ch1UnitPSU: {"PSU", "Salinity (PSU)", "Practical salinity (PSS-78) from conductivity @ 25°C, taking 53,000 µS/cm as 35 PSU.", 3},
+	ch1UnitSG:  {"SG", "Specific Gravity", "Specific gravity at 25°C from salinity (35 ppt = 1.0264).", 4},
+}
+
+// psuFromUS converts uS@refTempC to practical salinity (see PSUFromUS).
+func (d *RoboTankConductivity) psuFromUS(usRef float64) float64 {
+	return PSUFromUS(usRef, d.refUS)
+}
+
+// sgFromPPT converts ppt to specific gravity (see SGFromPPT).
+func (d *RoboTankConductivity) sgFromPPT(ppt float64) float64 {
+	return SGFromPPT(ppt)
+}
+
+// ch1Info returns the display settings for the configured Ch1Unit.
+func (d *RoboTankConductivity) ch1Info() ch1UnitInfo {
+	if info, ok := ch1Units[d.ch1Unit]; ok {
+		return info
+	}
+	return ch1Units[ch1UnitPPT]
+}
+
+// salinity converts uS@refTempC to the configured Ch1Unit.
+func (d *RoboTankConductivity) salinity(usRef float64) float64 {
+	switch d.ch1Unit {
+	case ch1UnitPSU:
+		return d.psuFromUS(usRef)
+	case ch1UnitSG:
+		return d.sgFromPPT(d.pptFromUS(usRef))
+	default:
+		return d.pptFromUS(usRef)
+	}
+}
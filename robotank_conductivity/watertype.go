@@ -0,0 +1,62 @@
+// watertype.go
+package robotank_conductivity
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// Water types accepted by the board's "W,<n>" command.
+const (
+	waterTypeUnset = -1
+	waterTypeFresh = 0
+	waterTypeSalt  = 1
+)
+
+func waterTypeName(wt int) string {
+	switch wt {
+	case waterTypeFresh:
+		return "fresh"
+	case waterTypeSalt:
+		return "salt"
+	default:
+		return "unknown"
+	}
+}
+
+// SetWaterTypeLive switches the board between fresh (0) and salt (1) water
+// mode without rebuilding the driver. The value is recorded only when the
+// board accepted the command, and shows up in Snapshot meta.
+func (d *RoboTankConductivity) SetWaterTypeLive(wt int) error {
+	if wt != waterTypeFresh && wt != waterTypeSalt {
+		return fmt.Errorf("%s: water type must be %d (fresh) or %d (salt), got %d",
+			driverName, waterTypeFresh, waterTypeSalt, wt)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.command(fmt.Sprintf("W,%d", wt)); err != nil {
+		return fmt.Errorf("%s: set water type %s: %w", driverName, waterTypeName(wt), err)
+	}
+	old := d.waterType
+	d.waterType = wt
+	d.waterTypeAt = time.Now()
+
+	log.Printf("robotank_cond addr=%d water type %s -> %s", d.addr, waterTypeName(old), waterTypeName(wt))
+	return nil
+}
+
+// waterTypeMeta adds the current water type to Snapshot meta.
+func (d *RoboTankConductivity) waterTypeMeta(meta map[string]any) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	meta["water_type"] = waterTypeName(d.waterType)
+	if d.waterTypeAt.IsZero() {
+		meta["water_type_set_at"] = nil
+		return
+	}
+	meta["water_type_set_at"] = d.waterTypeAt.Format(time.RFC3339)
+}
@@ -0,0 +1,42 @@
+package robotank_conductivity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetWaterTypeLive(t *testing.T) {
+	bus := &fakeBoard{resp: map[string]string{}}
+	d := newTestDriver(t, bus, nil)
+
+	for _, wt := range []int{waterTypeUnset, 2} {
+		n := len(bus.cmds)
+		if err := d.SetWaterTypeLive(wt); err == nil {
+			t.Errorf("SetWaterTypeLive(%d): expected an error", wt)
+		}
+		if len(bus.cmds) != n {
+			t.Errorf("SetWaterTypeLive(%d) sent %q", wt, bus.cmds[n:])
+		}
+	}
+
+	if err := d.SetWaterTypeLive(waterTypeSalt); err != nil {
+		t.Fatal(err)
+	}
+	if last := bus.cmds[len(bus.cmds)-1]; last != "W,1" {
+		t.Errorf("sent %q, want W,1", last)
+	}
+	meta := map[string]any{}
+	d.waterTypeMeta(meta)
+	if meta["water_type"] != "salt" || meta["water_type_set_at"] == nil {
+		t.Errorf("meta %v", meta)
+	}
+
+	// A write the board never got leaves the recorded type alone.
+	bus.writeErr = map[string]error{"W,0": errors.New("remote i/o error")}
+	if err := d.SetWaterTypeLive(waterTypeFresh); err == nil {
+		t.Fatal("expected the failed write to be returned")
+	}
+	if d.waterType != waterTypeSalt {
+		t.Errorf("waterType %d after failed write, want %d", d.waterType, waterTypeSalt)
+	}
+}
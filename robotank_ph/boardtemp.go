@@ -0,0 +1,42 @@
+// boardtemp.go
+package robotank_ph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// boardTempCommand asks Atlas-style firmware for the temperature it stores for
+// its own compensation. The answer is "?T,25.0" (some firmwares drop the prefix).
+const boardTempCommand = "T,?"
+
+// BoardTemperatureC returns the temperature (°C) the board reports compensating
+// at. It is informational only: reef-pi never sends it back or uses it, and
+// firmwares without the command answer with an error.
+func (d *Driver) BoardTemperatureC() (float64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.command(boardTempCommand); err != nil {
+		return 0, err
+	}
+	resp, err := d.readASCII()
+	if err != nil {
+		return 0, err
+	}
+	return parseBoardTemp(resp)
+}
+
+// parseBoardTemp parses a "?T,25.0" or "25.0" response.
+func parseBoardTemp(resp string) (float64, error) {
+	s := resp
+	if i := strings.IndexByte(s, ','); i >= 0 && strings.EqualFold(strings.TrimSpace(s[:i]), "?T") {
+		s = s[i+1:]
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: board temperature cmd=%q resp=%q: %w", ErrParse, boardTempCommand, resp, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,67 @@
+// clamp.go
+package robotank_ph
+
+import "fmt"
+
+// PhClampMin / PhClampMax bound the calibrated pH (default 0..14). The board
+// reading is pre-clamped to the same band widened by rawClampMargin on each
+// side before calibration. Both set to 0 turns clamping off, so bench tests
+// can see the unclamped calibration math.
+const (
+	defaultPhClampMin = 0.0
+	defaultPhClampMax = 14.0
+	rawClampMargin    = 1.0
+)
+
+// clampHit records which clamp cut a reading and the value before the cut.
+type clampHit struct {
+	raw       bool    // board pH pre-clamped before calibration
+	out       bool    // calibrated pH clamped
+	rawIn     float64 // board pH before the pre-clamp
+	unclamped float64 // calibrated pH before the clamp
+}
+
+func (d *Driver) clampOff() bool { return d.phClampMin == 0 && d.phClampMax == 0 }
+
+// preClamp is the safety clamp on the board reading (before any calibration).
+func (d *Driver) preClamp(raw float64) (float64, bool) {
+	if d.clampOff() {
+		return raw, false
+	}
+	return clampPH(raw, d.phClampMin-rawClampMargin, d.phClampMax+rawClampMargin)
+}
+
+// postClamp bounds the calibrated pH to PhClampMin..PhClampMax.
+func (d *Driver) postClamp(v float64) (float64, bool) {
+	if d.clampOff() {
+		return v, false
+	}
+	return clampPH(v, d.phClampMin, d.phClampMax)
+}
+
+// clampSuffix is the debug-log marker for a clamped result.
+func (d *Driver) clampSuffix() string {
+	return fmt.Sprintf(" (clamped %g..%g)", d.phClampMin, d.phClampMax)
+}
+
+// addClampMeta reports the clamp range and, when a clamp cut this reading,
+// a flag and a warning note.
+func (d *Driver) addClampMeta(meta map[string]interface{}, notes []string, hit clampHit) []string {
+	meta["ph_clamp_min"] = d.phClampMin
+	meta["ph_clamp_max"] = d.phClampMax
+	meta["ph_clamp_enabled"] = !d.clampOff()
+	meta["clamped"] = hit.out
+	meta["raw_clamped"] = hit.raw
+	if hit.raw {
+		notes = append(notes, fmt.Sprintf(
+			"WARNING: board pH %.3f was clamped to %g..%g before calibration. Widen PhClampMin/PhClampMax, or set both to 0 to disable clamping.",
+			hit.rawIn, d.phClampMin-rawClampMargin, d.phClampMax+rawClampMargin))
+	}
+	if hit.out {
+		meta["unclamped_value"] = hit.unclamped
+		notes = append(notes, fmt.Sprintf(
+			"WARNING: calibrated pH %.3f was clamped to %g..%g. Check Obs4/Obs7/Obs10, widen PhClampMin/PhClampMax, or set both to 0 to see the unclamped value.",
+			hit.unclamped, d.phClampMin, d.phClampMax))
+	}
+	return notes
+}
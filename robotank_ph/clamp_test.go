@@ -0,0 +1,124 @@
+package robotank_ph
+
+import (
+	"strings"
+	"testing"
+)
+
+func hasNote(notes []string, substr string) bool {
+	for _, n := range notes {
+		if strings.Contains(n, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestClampDefaults(t *testing.T) {
+	for _, tc := range []struct {
+		board      string
+		want       float64
+		clamped    bool
+		rawClamped bool
+		note       string
+	}{
+		{"7.00", 7, false, false, ""},
+		// Inside the pre-clamp band (-1..15): only the output clamp cuts it.
+		{"14.50", 14, true, false, "calibrated pH 14.500 was clamped to 0..14"},
+		// Beyond the band widened by rawClampMargin: both clamps cut it.
+		{"16.20", 14, true, true, "board pH 16.200 was clamped to -1..15"},
+		{"-3.00", 0, true, true, "board pH -3.000 was clamped to -1..15"},
+	} {
+		d := newTestDriver(t, &boardBus{status: 1, ph: tc.board}, nil)
+		if v, err := d.pin.Value(); err != nil || v != tc.want {
+			t.Errorf("board %s: Value = %v, %v; want %v", tc.board, v, err, tc.want)
+		}
+		s, err := d.pin.Snapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Meta["clamped"] != tc.clamped || s.Meta["raw_clamped"] != tc.rawClamped || s.Meta["ph_clamp_enabled"] != true {
+			t.Errorf("board %s: meta clamped=%v raw_clamped=%v enabled=%v", tc.board,
+				s.Meta["clamped"], s.Meta["raw_clamped"], s.Meta["ph_clamp_enabled"])
+		}
+		if tc.note != "" && !hasNote(s.Notes, tc.note) {
+			t.Errorf("board %s: missing note %q in %v", tc.board, tc.note, s.Notes)
+		}
+		if tc.note == "" && hasNote(s.Notes, "was clamped") {
+			t.Errorf("board %s: unexpected clamp note in %v", tc.board, s.Notes)
+		}
+	}
+}
+
+func TestClampUnclampedValueMeta(t *testing.T) {
+	d := newTestDriver(t, &boardBus{status: 1, ph: "14.50"}, nil)
+	s, err := d.pin.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Meta["unclamped_value"] != 14.5 {
+		t.Errorf("unclamped_value = %v, want 14.5", s.Meta["unclamped_value"])
+	}
+}
+
+func TestClampCustomRange(t *testing.T) {
+	params := func() map[string]interface{} {
+		return map[string]interface{}{phClampMinParam: 2.0, phClampMaxParam: 12.0}
+	}
+	// 12.8 is inside the 1..13 pre-clamp band, so only the output is cut.
+	d := newTestDriver(t, &boardBus{status: 1, ph: "12.80"}, params())
+	if v, _ := d.pin.Value(); v != 12 {
+		t.Errorf("Value = %v, want 12", v)
+	}
+	if _, hit := d.applyCalibrationClamp(12.8, false); hit.raw || !hit.out {
+		t.Errorf("12.8: raw=%v out=%v; want only the output clamp", hit.raw, hit.out)
+	}
+	if v, hit := d.applyCalibrationClamp(0.5, false); !hit.raw || !hit.out || hit.rawIn != 0.5 || v != 2 {
+		t.Errorf("0.5: v=%v hit=%+v; want pre-clamp to 1 and output 2", v, hit)
+	}
+}
+
+func TestClampOff(t *testing.T) {
+	d := newTestDriver(t, &boardBus{status: 1, ph: "16.20"}, map[string]interface{}{
+		phClampMinParam: 0.0, phClampMaxParam: 0.0,
+	})
+	if v, err := d.pin.Value(); err != nil || v != 16.2 {
+		t.Errorf("clamping off: Value = %v, %v; want 16.2", v, err)
+	}
+	s, err := d.pin.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Meta["ph_clamp_enabled"] != false || s.Meta["clamped"] != false || s.Meta["raw_clamped"] != false {
+		t.Errorf("clamping off: meta %v", s.Meta)
+	}
+	if hasNote(s.Notes, "was clamped") {
+		t.Errorf("clamping off: unexpected note in %v", s.Notes)
+	}
+}
+
+func TestClampValidation(t *testing.T) {
+	for _, tc := range []struct {
+		min, max float64
+		ok       bool
+	}{
+		{0, 14, true},
+		{2, 12, true},
+		{0, 0, true}, // off
+		{7, 7, false},
+		{8, 6, false},
+		{-1, 0, true},
+		{0, -1, false},
+	} {
+		ok, failures := Factory().ValidateParameters(map[string]interface{}{
+			addressParam: 0x62, obs7Param: 7.0,
+			phClampMinParam: tc.min, phClampMaxParam: tc.max,
+		})
+		if ok != tc.ok {
+			t.Errorf("PhClampMin=%v PhClampMax=%v: valid=%v, want %v (%v)", tc.min, tc.max, ok, tc.ok, failures)
+		}
+		if !tc.ok && len(failures[phClampMaxParam]) == 0 {
+			t.Errorf("PhClampMin=%v PhClampMax=%v: failure not reported on %s", tc.min, tc.max, phClampMaxParam)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// diagnose.go
+package robotank_ph
+
+import (
+	"errors"
+	"math"
+
+	"github.com/reef-pi/drivers/diag"
+)
+
+var _ diag.Diagnoser = (*Driver)(nil)
+
+// Diagnose runs one read transaction (the same one Value uses, without
+// samples or smoothing) and checks the calibration anchors. Read errors are
+// classified by cause (ErrBoardNotResponding, ErrBadPayload, ErrParse).
+func (d *Driver) Diagnose() diag.Diagnostics {
+	r := diag.New()
+
+	raw, err := d.readPH()
+	if r.Check("read pH", err) {
+		r.Infof("board pH=%.3f", raw)
+		if raw < 0 || raw > 14 {
+			r.Warnf("board pH %.3f is outside 0..14; check the probe connection", raw)
+		}
+	} else {
+		switch {
+		case errors.Is(err, ErrBoardNotResponding):
+			r.Infof("board does not answer: check power, wiring and Address 0x%02X", d.addr)
+		case errors.Is(err, ErrBadPayload):
+			r.Infof("board answers with bad payloads: check ReadLen / StatusByteMode for this firmware")
+		case errors.Is(err, ErrParse):
+			r.Infof("board reply is not a number: check ReadCommand and the framing parameters")
+		}
+	}
+
+	anchors := d.enabledAnchors()
+	if len(anchors) == 0 {
+		r.Warnf("no calibration anchors set (Obs4/Obs7/Obs10); reporting the board's own pH")
+	}
+	res := d.CalibrationResiduals()
+	for _, a := range anchors {
+		if math.Abs(res[a.truePH]) > maxResidualWarnPH {
+			r.Warnf("anchor pH %.2f misses its buffer by %.3f pH; recheck that anchor", a.truePH, res[a.truePH])
+		}
+	}
+	return r
+}
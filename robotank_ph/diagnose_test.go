@@ -0,0 +1,113 @@
+package robotank_ph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/reef-pi/drivers/diag"
+	"github.com/reef-pi/rpi/i2c"
+)
+
+// boardBus answers every read with the status byte and the ASCII reading ph,
+// zero padded to the requested length. A non-nil err fails every write.
+type boardBus struct {
+	status byte
+	ph     string
+	err    error
+}
+
+func (b *boardBus) SetAddress(byte) error { return nil }
+func (b *boardBus) ReadBytes(_ byte, n int) ([]byte, error) {
+	p := make([]byte, n)
+	p[0] = b.status
+	copy(p[1:], b.ph)
+	return p, nil
+}
+func (b *boardBus) WriteBytes(byte, []byte) error        { return b.err }
+func (b *boardBus) ReadFromReg(byte, byte, []byte) error { return nil }
+func (b *boardBus) WriteToReg(byte, byte, []byte) error  { return nil }
+func (b *boardBus) Close() error                         { return nil }
+
+func newTestDriver(t *testing.T, bus i2c.Bus, params map[string]interface{}) *Driver {
+	t.Helper()
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	if _, ok := params[addressParam]; !ok {
+		params[addressParam] = 0x62
+	}
+	if _, ok := params[obs7Param]; !ok {
+		params[obs7Param] = 7.0
+	}
+	h, err := Factory().NewDriver(params, bus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := h.(*Driver)
+	d.delay = 0
+	t.Cleanup(func() { d.Close() })
+	return d
+}
+
+func hasMessage(r diag.Diagnostics, prefix, substr string) bool {
+	for _, m := range r.Messages {
+		if strings.HasPrefix(m, prefix) && strings.Contains(m, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDiagnoseHealthy(t *testing.T) {
+	d := newTestDriver(t, &boardBus{status: 1, ph: "7.02"}, map[string]interface{}{
+		obs4Param: 4.05, obs7Param: 7.02,
+	})
+	r := d.Diagnose()
+	if !r.OK || r.LastError != "" {
+		t.Fatalf("healthy board: %+v", r)
+	}
+	if !hasMessage(r, "", "board pH=7.020") {
+		t.Errorf("missing reading: %v", r.Messages)
+	}
+	if hasMessage(r, diag.WarningPrefix, "") {
+		t.Errorf("calibrated, in-range board must not warn: %v", r.Messages)
+	}
+}
+
+func TestDiagnoseWarnings(t *testing.T) {
+	d := newTestDriver(t, &boardBus{status: 1, ph: "15.5"}, nil)
+	d.obs7 = -1 // no anchors enabled
+	r := d.Diagnose()
+	if !r.OK {
+		t.Fatalf("warnings must keep OK: %+v", r)
+	}
+	if !hasMessage(r, diag.WarningPrefix, "outside 0..14") {
+		t.Errorf("missing range warning: %v", r.Messages)
+	}
+	if !hasMessage(r, diag.WarningPrefix, "no calibration anchors") {
+		t.Errorf("missing anchor warning: %v", r.Messages)
+	}
+}
+
+func TestDiagnoseClassifiesErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		bus  *boardBus
+		hint string
+	}{
+		{"not responding", &boardBus{status: 1, ph: "7.0", err: errors.New("remote i/o error")}, "board does not answer"},
+		{"bad payload", &boardBus{status: 2, ph: "7.0"}, "bad payloads"},
+		{"parse", &boardBus{status: 1, ph: "abc"}, "not a number"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestDriver(t, tc.bus, nil).Diagnose()
+			if r.OK || r.LastError == "" {
+				t.Fatalf("failing read must fail: %+v", r)
+			}
+			if !hasMessage(r, diag.ErrorPrefix, "read pH") || !hasMessage(r, "", tc.hint) {
+				t.Errorf("want error and hint %q: %v", tc.hint, r.Messages)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@
 package robotank_ph
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -11,11 +12,16 @@ import (
 	"sync"
 	"time"
 
+	"github.com/reef-pi/drivers/filter"
+	"github.com/reef-pi/drivers/i2creg"
 	"github.com/reef-pi/hal"
 	"github.com/reef-pi/rpi/i2c"
 )
 
-const driverName = "Robo-Tank pH Circuit"
+const (
+	driverType = "robotank-ph" // stable identifier, see DriverType
+	driverName = "Robo-Tank pH Circuit"
+)
 
 // Robo-Tank firmware requires a short processing delay between
 // command write and response read.
@@ -24,6 +30,31 @@ const driverName = "Robo-Tank pH Circuit"
 // only increases misconfiguration risk.
 const fixedReadDelay = 300 * time.Millisecond
 
+// Default read transaction: a single "R" with no pre/post commands.
+const defaultReadCommand = "R"
+
+// Response framing. Robo-Tank answers 32 bytes with a status byte of 1 first;
+// cousin firmwares use other buffer sizes or no status byte (see readASCII).
+const (
+	defaultReadLen = 32
+	maxReadLen     = 64
+
+	statusRequired1 = "required-1" // payload[0] must be 1, text follows
+	statusNone      = "none"       // no status byte, the whole payload is text
+	statusASCII     = "ascii"      // a leading non-printable byte is skipped, never checked
+)
+
+// Read errors, so the host can tell a dead board (alarm) from a momentary
+// glitch (retry). Returned errors wrap one of these; test with errors.Is.
+var (
+	// ErrBoardNotResponding: the I2C transfer failed or the bus reads all 0xFF.
+	ErrBoardNotResponding = errors.New("board not responding")
+	// ErrBadPayload: the board answered, but with an empty payload or status != 1.
+	ErrBadPayload = errors.New("bad payload")
+	// ErrParse: the payload was valid but its text is not a number.
+	ErrParse = errors.New("parse failure")
+)
+
 // Known calibration buffer truths (do not change unless you really use other buffers)
 const (
 	truePH4  = 4.00
@@ -31,22 +62,62 @@ const (
 	truePH10 = 10.00
 )
 
+// Calibration residuals beyond this (pH) are flagged in Snapshot.
+const maxResidualWarnPH = 0.05
+
 // The PCB firmware is described as using ~59.16mV/pH with pH7 = 0mV (25C Nernst slope).
 // We cannot read raw mV from the board, but we can optionally print an *implied* mV for debugging.
 const phSlopeMvPerPH = 59.16
 
 // Driver exposes a single AnalogInput pin (0) for pH.
-// Protocol observed on 0x62:
-//   - Write ASCII command + "\x00"
+// Protocol observed on 0x62 (the defaults; ReadLen and StatusByteMode adapt it):
+//   - Write ASCII command + "\x00" (CommandTerminator)
 //   - Read 32 bytes
 //   - payload[0] == 1 => OK
-//   - payload[1:] ASCII float, padded with 0x00 and/or 0xFF
+//   - payload[1:] ASCII float, padded with 0x00 and/or 0xFF (ResponseTerminator, TrimTrailingFF)
 type Driver struct {
 	addr  byte
 	bus   i2c.Bus
 	delay time.Duration
 	debug bool
 
+	// claim is this instance's i2creg address claim (nil when config-only).
+	claim *i2creg.Handle
+
+	// Read transaction (adaptable for firmware variants):
+	//   [preReadCmd] -> readCmd -> response -> [postReadCmd]
+	// All steps run under mu. Empty pre/post commands are skipped.
+	readCmd     string
+	preReadCmd  string
+	postReadCmd string
+
+	// samplesPerRead readings are taken per Value/Snapshot call (see samples.go).
+	samplesPerRead int
+
+	// Response framing: bytes per read and status byte handling (status* consts).
+	readLen    int
+	statusMode string
+
+	// Command terminator and response framing by name (see framing.go).
+	cmdTerm  string
+	respTerm string
+	trimFF   bool
+
+	// readBoardTemp queries the board's stored temperature in Snapshot (see boardtemp.go).
+	readBoardTemp bool
+
+	// smooth filters the board's pH before calibration (SmoothMode, see
+	// smooth.go). Guarded by mu.
+	smoothMode    string
+	smoothMedianN int
+	smoothMeanN   int
+	smooth        filter.Filter
+
+	// Injected temperature (SetTemperatureC, see temperature.go). Diagnostic
+	// only; guarded by mu.
+	tempC         float64
+	tempUpdatedAt time.Time
+
 	// Serialize I2C "write cmd -> wait -> read payload" sequences.
 	// This prevents concurrent /read and /snapshot callers from interleaving and causing 0xFF payloads.
 	mu sync.Mutex
@@ -63,6 +134,10 @@ type Driver struct {
 	obs7  float64
 	obs10 float64
 
+	// Calibrated pH bounds (PhClampMin/PhClampMax, both 0 = off, see clamp.go).
+	phClampMin float64
+	phClampMax float64
+
 	meta hal.Metadata
 	pin  *phPin
 }
@@ -79,7 +154,8 @@ func (p *phPin) Metadata() hal.Metadata { return p.d.meta }
 func (p *phPin) Measure() (float64, error) { return p.Value() }
 
 func (p *phPin) Value() (float64, error) {
-	raw, err := p.d.readFloat("R")
+	s, err := p.d.readSamples()
+	raw := s.value
 	if err != nil {
 		if p.d.debug {
 			log.Printf("robotank_ph addr=0x%02X read error: %v", p.d.addr, err)
@@ -87,7 +163,7 @@ func (p *phPin) Value() (float64, error) {
 		return 0, err
 	}
 
-	cal := p.d.applyCalibration(raw)
+	cal := p.d.applyCalibration(p.d.smoothed(raw))
 
 	if p.d.debug {
 		mv := phToImpliedMv(raw)
@@ -106,7 +182,8 @@ func (p *phPin) Value() (float64, error) {
 func (p *phPin) Snapshot() (hal.Snapshot, error) {
 	// Read raw pH reported by the Robo-Tank board.
 	// This call is serialized internally (d.mu) to protect the I2C transaction.
-	raw, err := p.d.readFloat("R")
+	s, err := p.d.readSamples()
+	raw := s.value
 	if err != nil {
 		if p.d.debug {
 			log.Printf("robotank_ph addr=0x%02X snapshot read error: %v", p.d.addr, err)
@@ -114,9 +191,10 @@ func (p *phPin) Snapshot() (hal.Snapshot, error) {
 		return hal.Snapshot{}, err
 	}
 
-	// Apply software calibration anchors (Obs4 / Obs7 / Obs10).
+	// Smooth (SmoothMode), then apply software calibration anchors (Obs4 / Obs7 / Obs10).
 	// No temperature compensation is applied here (by design).
-	cal := p.d.applyCalibration(raw)
+	smoothed := p.d.smoothed(raw)
+	cal, hit := p.d.applyCalibrationClamp(smoothed, p.d.debug)
 
 	// ---------------------------------------------------------------------
 	// Signals
@@ -139,6 +217,12 @@ func (p *phPin) Snapshot() (hal.Snapshot, error) {
 			Now:  phToImpliedMv(raw),
 			Unit: "mV",
 		},
+
+		// Board pH after SmoothMode, before calibration (equals observed when off).
+		"smoothed": {
+			Now:  smoothed,
+			Unit: "pH",
+		},
 	}
 
 	// ---------------------------------------------------------------------
@@ -153,7 +237,7 @@ func (p *phPin) Snapshot() (hal.Snapshot, error) {
 		"raw_signal_key":     "observed",
 
 		// Derived signals shown collapsed by default
-		"secondary_signal_keys": []string{"implied_mv"},
+		"secondary_signal_keys": []string{"smoothed", "implied_mv", "implied_mv_at_temp", "board_temp_c"},
 
 		// Human-friendly labels
 		"display_roles": map[string]interface{}{
@@ -161,19 +245,28 @@ func (p *phPin) Snapshot() (hal.Snapshot, error) {
 			"observed": "Observed",
 		},
 		"display_names": map[string]interface{}{
-			"value":      "pH",
-			"observed":   "Observed (raw)",
-			"implied_mv": "Implied mV @25°C",
+			"value":              "pH",
+			"observed":           "Observed (raw)",
+			"smoothed":           "Observed (smoothed)",
+			"implied_mv":         "Implied mV @25°C",
+			"implied_mv_at_temp": "Implied mV @tank temp",
+			"board_temp_c":       "Board Temperature (°C)",
 		},
 		"display_help": map[string]interface{}{
-			"value":      "Calibrated pH after applying Obs4/Obs7/Obs10 anchors.",
-			"observed":   "Raw pH as reported by the Robo-Tank board before software calibration.",
-			"implied_mv": "Diagnostic only. Derived assuming 59.16 mV/pH at 25 °C. Not raw electrode mV.",
+			"value":              "Calibrated pH after applying Obs4/Obs7/Obs10 anchors.",
+			"observed":           "Raw pH as reported by the Robo-Tank board before software calibration.",
+			"smoothed":           "Board pH after SmoothMode filtering; calibration is applied to this value.",
+			"implied_mv":         "Diagnostic only. Derived assuming 59.16 mV/pH at 25 °C. Not raw electrode mV.",
+			"implied_mv_at_temp": "Diagnostic only. Same pH with the Nernst slope at the injected temperature; the gap to Implied mV @25°C is the board's fixed-slope error.",
+			"board_temp_c":       "Temperature the board reports compensating at (T,?). Informational; compare with the tank temperature.",
 		},
 		"signal_decimals": map[string]interface{}{
-			"value":      3,
-			"observed":   3,
-			"implied_mv": 1,
+			"value":              3,
+			"observed":           3,
+			"smoothed":           3,
+			"implied_mv":         1,
+			"implied_mv_at_temp": 1,
+			"board_temp_c":       1,
 		},
 
 		// -----------------------------------------------------------------
@@ -190,6 +283,26 @@ func (p *phPin) Snapshot() (hal.Snapshot, error) {
 		"obs7":    p.d.obs7,
 		"obs10":   p.d.obs10,
 		"address": fmt.Sprintf("0x%02X", p.d.addr),
+
+		"read_command":      p.d.readCmd,
+		"pre_read_command":  p.d.preReadCmd,
+		"post_read_command": p.d.postReadCmd,
+		"read_len":          p.d.readLen,
+		"status_byte_mode":  p.d.statusMode,
+
+		"command_terminator":  p.d.cmdTerm,
+		"response_terminator": p.d.respTerm,
+		"trim_trailing_ff":    p.d.trimFF,
+
+		// Multi-sample read: spread (max-min, pH) shows probe/board stability
+		"samples_per_read": p.d.samplesPerRead,
+		"samples_used":     s.used,
+		"sample_errors":    s.errors,
+		"sample_spread":    s.spread,
+
+		"smooth_mode":          p.d.smoothMode,
+		"smooth_median_window": p.d.smoothMedianN,
+		"smooth_mean_window":   p.d.smoothMeanN,
 	}
 
 	// Informational note only — never alters readings
@@ -197,6 +310,54 @@ func (p *phPin) Snapshot() (hal.Snapshot, error) {
 		"Temperature compensation disabled: board uses fixed 59.16 mV/pH (25 °C reference)",
 	}
 
+	// Implied mV with a temperature-correct slope, to show what the board's
+	// fixed 25 °C slope costs at the tank temperature. Never alters readings.
+	if tempC, at, ok := p.d.injectedTemp(); ok {
+		mv25, mvT := phToImpliedMv(raw), phToImpliedMvAt(raw, tempC)
+		signals["implied_mv_at_temp"] = hal.Signal{Now: mvT, Unit: "mV"}
+		meta["temp_c"] = tempC
+		meta["temp_age_sec"] = time.Since(at).Seconds()
+		meta["nernst_slope_mv_per_ph"] = nernstSlopeMvPerPH(tempC)
+		meta["implied_mv_slope_error"] = mvT - mv25
+	} else if !at.IsZero() {
+		meta["temp_c"] = tempC
+		notes = append(notes, fmt.Sprintf("Injected temperature is stale (>%v); implied_mv_at_temp omitted.", tempStaleAfter))
+	}
+
+	// Board's own compensation temperature, for comparison with the tank's.
+	// Informational only; a failed query never fails the snapshot.
+	if p.d.readBoardTemp {
+		if t, err := p.d.BoardTemperatureC(); err != nil {
+			meta["board_temp_error"] = err.Error()
+			notes = append(notes, fmt.Sprintf("Board temperature query (%s) failed: %v. Firmware may not support it.", boardTempCommand, err))
+		} else {
+			meta["board_temp_c"] = t
+			signals["board_temp_c"] = hal.Signal{Now: t, Unit: "C"}
+		}
+	}
+
+	// PhClampMin/PhClampMax and whether they cut this reading
+	notes = p.d.addClampMeta(meta, notes, hit)
+
+	// Calibration fit quality (JSON needs string keys)
+	if res := p.d.CalibrationResiduals(); len(res) > 0 {
+		byPH := map[string]float64{}
+		maxAbs, maxPH := 0.0, 0.0
+		for ph, r := range res {
+			byPH[fmt.Sprintf("%.2f", ph)] = r
+			if math.Abs(r) > maxAbs {
+				maxAbs, maxPH = math.Abs(r), ph
+			}
+		}
+		meta["cal_residuals"] = byPH
+		meta["cal_max_residual"] = maxAbs
+		if maxAbs > maxResidualWarnPH {
+			notes = append(notes, fmt.Sprintf(
+				"WARNING: calibration anchor pH %.2f maps back with residual %.3f pH (>%.2f). Check Obs4/Obs7/Obs10 for a bad buffer or swapped value.",
+				maxPH, res[maxPH], maxResidualWarnPH))
+		}
+	}
+
 	return hal.Snapshot{
 		Value:   cal, // calibrated pH
 		Unit:    "pH",
@@ -209,19 +370,27 @@ func (p *phPin) Snapshot() (hal.Snapshot, error) {
 
 // Optional: reef-pi generic calibration workflow hook.
 // NOTE: We can't persist changes back into the driver config DB from here reliably,
-// so this just acts as a no-op (returns nil).
+// so this does not change the calibration. It takes a fresh reading per
+// measurement (see fresh.go) and logs the anchor value to enter.
 // You should set Obs4/Obs7/Obs10 in the driver configuration UI.
 func (p *phPin) Calibrate(ms []hal.Measurement) error {
-	// Intentionally a no-op. Calibration anchors are config-driven.
-	return nil
+	// Calibration anchors are config-driven; only log live readings.
+	return p.logAnchorsFresh(ms)
 }
 
 // ---- hal.Driver ----
 
 func (d *Driver) Name() string           { return driverName }
-func (d *Driver) Close() error           { return nil }
 func (d *Driver) Metadata() hal.Metadata { return d.meta }
 
+func (d *Driver) Close() error {
+	d.claim.Release()
+	return nil
+}
+
+// DriverType returns the stable driver kind for host-side routing.
+func (d *Driver) DriverType() string { return driverType }
+
 func (d *Driver) AnalogInputPin(n int) (hal.AnalogInputPin, error) {
 	if n != 0 {
 		return nil, fmt.Errorf("%s supports only channel 0", driverName)
@@ -304,27 +473,34 @@ func linearMapDbg(x, x1, x2, y1, y2 float64) mapDebug {
 // - 3 points: piecewise linear (4–7, 7–10)
 // If no anchors are set, returns raw unchanged.
 func (d *Driver) applyCalibration(raw float64) float64 {
+	return d.applyCalibrationDbg(raw, d.debug)
+}
+
+// applyCalibrationDbg is applyCalibration with explicit control over debug logging.
+func (d *Driver) applyCalibrationDbg(raw float64, debug bool) float64 {
+	out, _ := d.applyCalibrationClamp(raw, debug)
+	return out
+}
+
+// applyCalibrationClamp is applyCalibrationDbg that also reports which
+// clamp (PhClampMin/PhClampMax, see clamp.go) cut the reading.
+func (d *Driver) applyCalibrationClamp(raw float64, debug bool) (float64, clampHit) {
 	// Safety clamp on RAW (this is before any calibration)
-	rawIn := raw
-	if raw < -1 {
-		raw = -1
-	}
-	if raw > 15 {
-		raw = 15
-	}
-	if d.debug && raw != rawIn {
-		log.Printf("robotank_ph cal: raw clamp %.6f -> %.6f (pre-cal safety clamp)", rawIn, raw)
+	hit := clampHit{rawIn: raw}
+	raw, hit.raw = d.preClamp(raw)
+	if debug && hit.raw {
+		log.Printf("robotank_ph cal: raw clamp %.6f -> %.6f (pre-cal safety clamp)", hit.rawIn, raw)
 	}
 
 	as := d.enabledAnchors()
 	if len(as) == 0 {
-		if d.debug {
+		if debug {
 			log.Printf("robotank_ph cal: no anchors enabled -> cal=raw (%.6f)", raw)
 		}
-		return raw
+		return raw, hit
 	}
 
-	if d.debug {
+	if debug {
 		parts := make([]string, 0, len(as))
 		for _, a := range as {
 			parts = append(parts,
@@ -342,24 +518,26 @@ func (d *Driver) applyCalibration(raw float64) float64 {
 	if len(as) == 1 {
 		off := as[0].truePH - as[0].obsPH
 		outPre := raw + off
-		out, clamped := clampPH(outPre, 0, 14)
+		out, clamped := d.postClamp(outPre)
+		hit.out, hit.unclamped = clamped, outPre
 
-		if d.debug {
+		if debug {
 			log.Printf(
 				"robotank_ph cal: MODE=1pt offset=true-obs => off=%.6f (true=%.2f obs=%.6f) raw=%.6f => raw+off=%.6f%s",
-				off, as[0].truePH, as[0].obsPH, raw, outPre, boolSuffix(clamped, " (clamped 0..14)"),
+				off, as[0].truePH, as[0].obsPH, raw, outPre, boolSuffix(clamped, d.clampSuffix()),
 			)
 			log.Printf("robotank_ph cal: RESULT cal=%.6f", out)
 		}
-		return out
+		return out, hit
 	}
 
 	// 2-point: scale + offset (linear map)
 	if len(as) == 2 {
 		dbg := linearMapDbg(raw, as[0].obsPH, as[1].obsPH, as[0].truePH, as[1].truePH)
-		out, clamped := clampPH(dbg.y, 0, 14)
+		out, clamped := d.postClamp(dbg.y)
+		hit.out, hit.unclamped = clamped, dbg.y
 
-		if d.debug {
+		if debug {
 			scale := 0.0
 			if math.Abs(dbg.den) >= 1e-9 {
 				scale = (as[1].truePH - as[0].truePH) / dbg.den
@@ -371,11 +549,11 @@ func (d *Driver) applyCalibration(raw float64) float64 {
 			log.Printf("robotank_ph cal:   x1=obs@true%.2f=%.6f  x2=obs@true%.2f=%.6f  den(x2-x1)=%.9f",
 				as[0].truePH, as[0].obsPH, as[1].truePH, as[1].obsPH, dbg.den)
 			log.Printf("robotank_ph cal:   y1=true=%.2f y2=true=%.2f  t=(x-x1)/den=%.9f", as[0].truePH, as[1].truePH, dbg.t)
-			log.Printf("robotank_ph cal:   y= y1 + t*(y2-y1) => %.6f%s", dbg.y, boolSuffix(clamped, " (clamped 0..14)"))
+			log.Printf("robotank_ph cal:   y= y1 + t*(y2-y1) => %.6f%s", dbg.y, boolSuffix(clamped, d.clampSuffix()))
 			log.Printf("robotank_ph cal:   line form y=scale*x+offset => scale=%.9f offset=%.9f", scale, offset)
 			log.Printf("robotank_ph cal: RESULT cal=%.6f", out)
 		}
-		return out
+		return out, hit
 	}
 
 	// 3-point: piecewise around the middle anchor (truePH7)
@@ -399,9 +577,10 @@ func (d *Driver) applyCalibration(raw float64) float64 {
 		dbg = linearMapDbg(raw, x1, x2, y1, y2)
 	}
 
-	out, clamped := clampPH(dbg.y, 0, 14)
+	out, clamped := d.postClamp(dbg.y)
+	hit.out, hit.unclamped = clamped, dbg.y
 
-	if d.debug {
+	if debug {
 		log.Printf("robotank_ph cal: MODE=3pt piecewise (segment=%s chosen by raw<=obs@7? raw=%.6f obs7=%.6f => %v)",
 			seg, raw, a1.obsPH, left)
 
@@ -414,7 +593,7 @@ func (d *Driver) applyCalibration(raw float64) float64 {
 		}
 
 		log.Printf("robotank_ph cal:   den=%.9f t=%.9f y=%.6f%s",
-			dbg.den, dbg.t, dbg.y, boolSuffix(clamped, " (clamped 0..14)"))
+			dbg.den, dbg.t, dbg.y, boolSuffix(clamped, d.clampSuffix()))
 
 		den := (x2 - x1)
 		scale := 0.0
@@ -427,7 +606,22 @@ func (d *Driver) applyCalibration(raw float64) float64 {
 		log.Printf("robotank_ph cal: RESULT cal=%.6f", out)
 	}
 
-	return out
+	return out, hit
+}
+
+// CalibrationResiduals feeds each enabled anchor's observed reading back through
+// applyCalibration and returns true pH -> (calibrated - true pH).
+//
+// The 1/2/3-point maps pass through their anchors, so residuals are ~0 for a sane
+// calibration. A large residual means an anchor is out of order (e.g. Obs4 above
+// Obs7 from a contaminated buffer puts it in the wrong 3-point segment), two
+// anchors read the same, or the pre/post clamp cut a reading.
+func (d *Driver) CalibrationResiduals() map[float64]float64 {
+	res := map[float64]float64{}
+	for _, a := range d.enabledAnchors() {
+		res[a.truePH] = d.applyCalibrationDbg(a.obsPH, false) - a.truePH
+	}
+	return res
 }
 
 // Debug helper only: implied mV under the designer's convention.
@@ -454,17 +648,17 @@ func (d *Driver) command(cmd string) error {
 	if d.debug {
 		log.Printf("robotank_ph addr=0x%02X write cmd=%q", d.addr, cmd)
 	}
-	if err := d.bus.WriteBytes(d.addr, []byte(cmd+"\x00")); err != nil {
-		return err
+	if err := d.bus.WriteBytes(d.addr, d.frameCommand(cmd)); err != nil {
+		return fmt.Errorf("%w: write cmd=%q: %w", ErrBoardNotResponding, cmd, err)
 	}
 	time.Sleep(d.delay)
 	return nil
 }
 
 func (d *Driver) readASCII() (string, error) {
-	payload, err := d.bus.ReadBytes(d.addr, 32)
+	payload, err := d.bus.ReadBytes(d.addr, d.readLen)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("%w: read: %w", ErrBoardNotResponding, err)
 	}
 
 	if d.debug {
@@ -472,44 +666,33 @@ func (d *Driver) readASCII() (string, error) {
 	}
 
 	if len(payload) == 0 {
-		return "", fmt.Errorf("empty payload")
+		return "", fmt.Errorf("%w: empty payload", ErrBadPayload)
 	}
 
 	// Some devices/bus errors manifest as all 0xFF. Retry once.
 	if payload[0] == 0xFF && allFF(payload) {
 		time.Sleep(50 * time.Millisecond)
-		payload, err = d.bus.ReadBytes(d.addr, 32)
+		payload, err = d.bus.ReadBytes(d.addr, d.readLen)
 		if err != nil {
-			return "", err
+			return "", fmt.Errorf("%w: read (after 0xFF retry): %w", ErrBoardNotResponding, err)
 		}
 		if d.debug {
 			log.Printf("robotank_ph addr=0x%02X read retry payload=% X", d.addr, payload)
 		}
 		if len(payload) == 0 {
-			return "", fmt.Errorf("empty payload (after retry)")
+			return "", fmt.Errorf("%w: empty payload (after retry)", ErrBadPayload)
 		}
-	}
-
-	if payload[0] != 1 {
-		return "", fmt.Errorf("status=%d payload=%v", payload[0], payload)
-	}
-
-	b := payload[1:]
-
-	// cut at first NUL
-	for i, v := range b {
-		if v == 0x00 {
-			b = b[:i]
-			break
+		if allFF(payload) {
+			return "", fmt.Errorf("%w: payload all 0xFF (after retry)", ErrBoardNotResponding)
 		}
 	}
 
-	// trim trailing 0xFF
-	for len(b) > 0 && b[len(b)-1] == 0xFF {
-		b = b[:len(b)-1]
+	b, err := d.stripStatus(payload)
+	if err != nil {
+		return "", err
 	}
 
-	s := strings.TrimSpace(string(b))
+	s := strings.TrimSpace(string(d.frameResponse(b)))
 	if d.debug {
 		log.Printf("robotank_ph addr=0x%02X read ascii=%q", d.addr, s)
 	}
@@ -517,11 +700,60 @@ func (d *Driver) readASCII() (string, error) {
 	return s, nil
 }
 
+// stripStatus applies StatusByteMode and returns the text part of payload.
+func (d *Driver) stripStatus(payload []byte) ([]byte, error) {
+	switch d.statusMode {
+	case statusNone:
+		return payload, nil
+	case statusASCII:
+		if c := payload[0]; c < 0x20 || c > 0x7E {
+			return payload[1:], nil
+		}
+		return payload, nil
+	default:
+		if payload[0] != 1 {
+			return nil, fmt.Errorf("%w: status=%d payload=%v", ErrBadPayload, payload[0], payload)
+		}
+		return payload[1:], nil
+	}
+}
+
 func (d *Driver) readFloat(cmd string) (float64, error) {
 	// Critical: serialize the *whole* "write -> wait -> read" transaction
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
+	return d.readFloatLocked(cmd)
+}
+
+// readPH runs the configured read transaction: optional PreReadCommand (e.g. wake),
+// ReadCommand + response, optional PostReadCommand (e.g. sleep). A failing
+// post command is logged but does not discard a good reading.
+func (d *Driver) readPH() (float64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.preReadCmd != "" {
+		if err := d.command(d.preReadCmd); err != nil {
+			return 0, fmt.Errorf("pre-read cmd=%q: %w", d.preReadCmd, err)
+		}
+	}
+
+	v, err := d.readFloatLocked(d.readCmd)
+	if err != nil {
+		return 0, err
+	}
+
+	if d.postReadCmd != "" {
+		if err := d.command(d.postReadCmd); err != nil {
+			log.Printf("robotank_ph addr=0x%02X post-read cmd=%q error: %v", d.addr, d.postReadCmd, err)
+		}
+	}
+	return v, nil
+}
+
+// readFloatLocked sends cmd and parses the response. Caller holds d.mu.
+func (d *Driver) readFloatLocked(cmd string) (float64, error) {
 	if err := d.command(cmd); err != nil {
 		return 0, err
 	}
@@ -531,7 +763,7 @@ func (d *Driver) readFloat(cmd string) (float64, error) {
 	}
 	v, err := strconv.ParseFloat(resp, 64)
 	if err != nil {
-		return 0, fmt.Errorf("parse float cmd=%q resp=%q: %w", cmd, resp, err)
+		return 0, fmt.Errorf("%w: float cmd=%q resp=%q: %w", ErrParse, cmd, resp, err)
 	}
 	return v, nil
 }
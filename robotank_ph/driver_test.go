@@ -0,0 +1,159 @@
+package robotank_ph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// reply is one scripted answer of scriptBus: a payload or a read error.
+type reply struct {
+	payload []byte
+	err     error
+}
+
+// okReply is a Robo-Tank answer: status 1 and text, NUL padded to defaultReadLen.
+func okReply(text string) reply {
+	p := make([]byte, defaultReadLen)
+	p[0] = 1
+	copy(p[1:], text)
+	return reply{payload: p}
+}
+
+// scriptBus answers reads from replies in order, repeating the last one, and
+// records every write with its terminator. writeErr fails writes of the given
+// command (terminator included).
+type scriptBus struct {
+	replies  []reply
+	reads    int
+	writes   []string
+	writeErr map[string]error
+}
+
+func (b *scriptBus) SetAddress(byte) error { return nil }
+func (b *scriptBus) ReadBytes(byte, int) ([]byte, error) {
+	r := b.replies[len(b.replies)-1]
+	if b.reads < len(b.replies) {
+		r = b.replies[b.reads]
+	}
+	b.reads++
+	return append([]byte(nil), r.payload...), r.err
+}
+func (b *scriptBus) WriteBytes(_ byte, p []byte) error {
+	b.writes = append(b.writes, string(p))
+	return b.writeErr[string(p)]
+}
+func (b *scriptBus) ReadFromReg(byte, byte, []byte) error { return nil }
+func (b *scriptBus) WriteToReg(byte, byte, []byte) error  { return nil }
+func (b *scriptBus) Close() error                         { return nil }
+
+// commands returns the written commands with NUL terminators removed.
+func (b *scriptBus) commands() []string {
+	out := make([]string, len(b.writes))
+	for i, w := range b.writes {
+		out[i] = strings.TrimRight(w, "\x00")
+	}
+	return out
+}
+
+func TestStatusByteMode(t *testing.T) {
+	pad := func(b ...byte) []byte { return append(b, make([]byte, 8)...) }
+	cases := []struct {
+		mode    string
+		payload []byte
+		want    float64
+		wantErr error
+	}{
+		{"required-1", pad(1, '7', '.', '0', '1'), 7.01, nil},
+		{"Required-1", pad(2, '7', '.', '0', '1'), 0, ErrBadPayload},
+		{"none", pad('7', '.', '0', '5'), 7.05, nil},
+		{"NONE", pad(1, '7', '.', '0', '5'), 0, ErrParse}, // the status byte is text now
+		{"ascii", pad(1, '7', '.', '1'), 7.1, nil},        // non-printable first byte skipped
+		{" Ascii ", pad('7', '.', '2', '\r', '\n'), 7.2, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.mode, func(t *testing.T) {
+			bus := &scriptBus{replies: []reply{{payload: c.payload}}}
+			d := newTestDriver(t, bus, map[string]interface{}{statusByteModeParam: c.mode, responseTerminatorParam: "nul"})
+			v, err := d.readFloat("R")
+			if c.wantErr != nil {
+				if !errors.Is(err, c.wantErr) {
+					t.Fatalf("want %v, got v=%v err=%v", c.wantErr, v, err)
+				}
+				return
+			}
+			if err != nil || v != c.want {
+				t.Fatalf("got %v, %v; want %v", v, err, c.want)
+			}
+		})
+	}
+
+	if ok, failures := Factory().ValidateParameters(map[string]interface{}{
+		addressParam: 0x62, statusByteModeParam: "status",
+	}); ok || len(failures[statusByteModeParam]) == 0 {
+		t.Errorf("unknown StatusByteMode must fail validation: %v", failures)
+	}
+}
+
+func TestReadErrorsWrapSentinels(t *testing.T) {
+	ffReply := reply{payload: []byte{0xFF, 0xFF, 0xFF, 0xFF}}
+	ioErr := errors.New("remote i/o error")
+	cases := []struct {
+		name string
+		bus  *scriptBus
+		want error
+	}{
+		{"write fails", &scriptBus{replies: []reply{okReply("7.0")}, writeErr: map[string]error{"R\x00": ioErr}}, ErrBoardNotResponding},
+		{"read fails", &scriptBus{replies: []reply{{err: ioErr}}}, ErrBoardNotResponding},
+		{"all 0xFF after retry", &scriptBus{replies: []reply{ffReply, ffReply}}, ErrBoardNotResponding},
+		{"read fails after 0xFF", &scriptBus{replies: []reply{ffReply, {err: ioErr}}}, ErrBoardNotResponding},
+		{"status 2", &scriptBus{replies: []reply{{payload: []byte{2, '7', 0}}}}, ErrBadPayload},
+		{"empty payload", &scriptBus{replies: []reply{{payload: []byte{}}}}, ErrBadPayload},
+		{"not a number", &scriptBus{replies: []reply{okReply("abc")}}, ErrParse},
+	}
+	sentinels := []error{ErrBoardNotResponding, ErrBadPayload, ErrParse}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := newTestDriver(t, c.bus, nil).readPH()
+			for _, s := range sentinels {
+				if errors.Is(err, s) != (s == c.want) {
+					t.Errorf("errors.Is(%v, %v) = %v", err, s, !(s == c.want))
+				}
+			}
+		})
+	}
+
+	// One all-0xFF read followed by a good one recovers.
+	bus := &scriptBus{replies: []reply{ffReply, okReply("7.0")}}
+	if v, err := newTestDriver(t, bus, nil).readPH(); err != nil || v != 7.0 || bus.reads != 2 {
+		t.Errorf("0xFF retry: got %v, %v after %d reads; want 7, nil, 2", v, err, bus.reads)
+	}
+}
+
+func TestReadPHCommandOrder(t *testing.T) {
+	bus := &scriptBus{replies: []reply{okReply("7.25")}}
+	d := newTestDriver(t, bus, map[string]interface{}{
+		preReadCommandParam:  "Wake",
+		postReadCommandParam: "Sleep",
+	})
+	if v, err := d.readPH(); err != nil || v != 7.25 {
+		t.Fatalf("got %v, %v; want 7.25", v, err)
+	}
+	if got := strings.Join(bus.commands(), ","); got != "Wake,R,Sleep" {
+		t.Errorf("commands %s, want Wake,R,Sleep", got)
+	}
+
+	// A failing post command is logged; the reading stands.
+	bus = &scriptBus{replies: []reply{okReply("7.25")}, writeErr: map[string]error{"Sleep\x00": errors.New("remote i/o error")}}
+	d = newTestDriver(t, bus, map[string]interface{}{postReadCommandParam: "Sleep"})
+	if v, err := d.readPH(); err != nil || v != 7.25 {
+		t.Errorf("failing post command: got %v, %v; want 7.25, nil", v, err)
+	}
+
+	// A failing pre command aborts before the read.
+	bus = &scriptBus{replies: []reply{okReply("7.25")}, writeErr: map[string]error{"Wake\x00": errors.New("remote i/o error")}}
+	d = newTestDriver(t, bus, map[string]interface{}{preReadCommandParam: "Wake"})
+	if _, err := d.readPH(); !errors.Is(err, ErrBoardNotResponding) || bus.reads != 0 {
+		t.Errorf("failing pre command: err=%v reads=%d; want ErrBoardNotResponding, 0", err, bus.reads)
+	}
+}
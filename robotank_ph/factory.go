@@ -25,8 +25,9 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/reef-pi/drivers/i2creg"
+	"github.com/reef-pi/drivers/nobus"
 	"github.com/reef-pi/hal"
-	"github.com/reef-pi/rpi/i2c"
 )
 
 // factory implements hal.DriverFactory.
@@ -56,6 +57,35 @@ const (
 	obs4Param  = "Obs4"
 	obs7Param  = "Obs7"
 	obs10Param = "Obs10"
+
+	// Read transaction for firmware variants: [PreReadCommand] -> ReadCommand -> [PostReadCommand].
+	readCommandParam     = "ReadCommand"
+	preReadCommandParam  = "PreReadCommand"
+	postReadCommandParam = "PostReadCommand"
+
+	// SamplesPerRead readings per Value/Snapshot, extremes dropped, rest averaged.
+	samplesPerReadParam = "SamplesPerRead"
+
+	// Response framing for firmware variants: bytes per read and status byte handling.
+	readLenParam        = "ReadLen"
+	statusByteModeParam = "StatusByteMode"
+
+	// ReadBoardTemp queries the board's stored compensation temperature (T,?) in Snapshot.
+	readBoardTempParam = "ReadBoardTemp"
+
+	// Command terminator and response framing for firmware variants (see framing.go).
+	commandTerminatorParam  = "CommandTerminator"
+	responseTerminatorParam = "ResponseTerminator"
+	trimTrailingFFParam     = "TrimTrailingFF"
+
+	// SmoothMode and window sizes for the pH before calibration (see smooth.go).
+	smoothModeParam         = "SmoothMode"
+	smoothMedianWindowParam = "SmoothMedianWindow"
+	smoothMeanWindowParam   = "SmoothMeanWindow"
+
+	// PhClampMin/PhClampMax bound the calibrated pH; both 0 disables clamping (see clamp.go).
+	phClampMinParam = "PhClampMin"
+	phClampMaxParam = "PhClampMax"
 )
 
 // Singleton factory instance (driver factories are typically singletons).
@@ -106,6 +136,112 @@ func Factory() hal.DriverFactory {
 					Default:     -1.0,
 					Description: "Observed electrode mV when probe is placed in pH 10.00 calibration solution. Set to -1 to disable this calibration point.",
 				},
+				// Read transaction
+				{
+					Name:        readCommandParam,
+					Type:        hal.String,
+					Order:       5,
+					Default:     defaultReadCommand,
+					Description: "ASCII command that returns one pH reading (default R).",
+				},
+				{
+					Name:        preReadCommandParam,
+					Type:        hal.String,
+					Order:       6,
+					Default:     "",
+					Description: "Optional command sent before each read (e.g. a wake command). Leave empty to skip.",
+				},
+				{
+					Name:        postReadCommandParam,
+					Type:        hal.String,
+					Order:       7,
+					Default:     "",
+					Description: "Optional command sent after each read (e.g. Sleep). Leave empty to skip.",
+				},
+				{
+					Name:        samplesPerReadParam,
+					Type:        hal.Integer,
+					Order:       8,
+					Default:     defaultSamplesPerRead,
+					Description: "Readings taken per update (1..5). With 3 or more the highest and lowest are dropped and the rest averaged. Each reading adds ~300ms.",
+				},
+				{
+					Name:        readLenParam,
+					Type:        hal.Integer,
+					Order:       9,
+					Default:     defaultReadLen,
+					Description: "Bytes read per response (2..64). Robo-Tank boards answer 32.",
+				},
+				{
+					Name:        statusByteModeParam,
+					Type:        hal.String,
+					Order:       10,
+					Default:     statusRequired1,
+					Description: "Response status byte: required-1 (Robo-Tank, first byte must be 1), none (no status byte), or ascii (a leading non-printable byte is skipped).",
+				},
+				{
+					Name:        readBoardTempParam,
+					Type:        hal.Boolean,
+					Order:       11,
+					Default:     false,
+					Description: "Show the temperature the board stores for its own compensation (T,?) in the snapshot. Informational only; adds ~300ms per snapshot.",
+				},
+				{
+					Name:        commandTerminatorParam,
+					Type:        hal.String,
+					Order:       12,
+					Default:     defaultCommandTerminator,
+					Description: "Bytes appended to every command: nul (Robo-Tank), cr, lf, crlf or none.",
+				},
+				{
+					Name:        responseTerminatorParam,
+					Type:        hal.String,
+					Order:       13,
+					Default:     defaultResponseTerminator,
+					Description: "The response text ends at the first nul (Robo-Tank), cr, lf or crlf. none keeps the whole payload.",
+				},
+				{
+					Name:        trimTrailingFFParam,
+					Type:        hal.Boolean,
+					Order:       14,
+					Default:     true,
+					Description: "Drop 0xFF padding at the end of the response.",
+				},
+				{
+					Name:        smoothModeParam,
+					Type:        hal.String,
+					Order:       15,
+					Default:     smoothNone,
+					Description: "Smoothing of the board's pH: none, median (drops spikes), mean, or median+mean (median first, then mean over its output).",
+				},
+				{
+					Name:        smoothMedianWindowParam,
+					Type:        hal.Integer,
+					Order:       16,
+					Default:     defaultSmoothWindow,
+					Description: "Readings in the median window (1..50). Used by median and median+mean.",
+				},
+				{
+					Name:        smoothMeanWindowParam,
+					Type:        hal.Integer,
+					Order:       17,
+					Default:     defaultSmoothWindow,
+					Description: "Readings in the mean window (1..50). Used by mean and median+mean.",
+				},
+				{
+					Name:        phClampMinParam,
+					Type:        hal.Decimal,
+					Order:       18,
+					Default:     defaultPhClampMin,
+					Description: "Lowest calibrated pH reported. The board reading is pre-clamped 1 pH below this. Set PhClampMin and PhClampMax both to 0 to disable clamping.",
+				},
+				{
+					Name:        phClampMaxParam,
+					Type:        hal.Decimal,
+					Order:       19,
+					Default:     defaultPhClampMax,
+					Description: "Highest calibrated pH reported. The board reading is pre-clamped 1 pH above this.",
+				},
 				// Debug
 				{
 					Name:        debugParam,
@@ -129,9 +265,10 @@ func (f *factory) GetParameters() []hal.ConfigParameter { return f.parameters }
 // ValidateParameters checks that the user configuration is safe and meaningful.
 //
 // Rules enforced:
-//   - Address is required and must be 0..127 (7-bit I2C)
+//   - Address is required and must be a 7-bit I2C address (0x62, 98 or 0b1100010)
 //   - At least one anchor is enabled (Obs4/Obs7/Obs10 != -1)
 //   - Enabled anchors must be in the plausible pH range 0..14
+//   - PhClampMin < PhClampMax, unless both are 0 (clamping off)
 func (f *factory) ValidateParameters(parameters map[string]interface{}) (bool, map[string][]string) {
 	failures := map[string][]string{}
 
@@ -141,9 +278,8 @@ func (f *factory) ValidateParameters(parameters map[string]interface{}) (bool, m
 		failures[addressParam] = []string{"Address is required"}
 		return false, failures
 	}
-	addr, ok := toInt(addrV)
-	if !ok || addr < 0 || addr > 127 {
-		failures[addressParam] = []string{"Address must be an integer 0..127"}
+	if _, err := i2creg.ParseI2CAddress(addrV); err != nil {
+		failures[addressParam] = []string{err.Error()}
 	}
 
 	// --- Anchor validation ---
@@ -164,6 +300,62 @@ func (f *factory) ValidateParameters(parameters map[string]interface{}) (bool, m
 		}
 	}
 
+	// --- Read transaction validation ---
+	for _, k := range []string{readCommandParam, preReadCommandParam, postReadCommandParam} {
+		if s := getString(parameters, k, ""); len(s) > 31 {
+			failures[k] = append(failures[k], k+" must be at most 31 characters")
+		}
+	}
+	if v, ok := parameters[readCommandParam]; ok {
+		if s, _ := v.(string); strings.TrimSpace(s) == "" {
+			failures[readCommandParam] = append(failures[readCommandParam], "ReadCommand must not be empty")
+		}
+	}
+
+	if v, ok := parameters[samplesPerReadParam]; ok {
+		if n, ok := toInt(v); !ok || n < 1 || n > maxSamplesPerRead {
+			failures[samplesPerReadParam] = append(failures[samplesPerReadParam],
+				"SamplesPerRead must be an integer 1.."+strconv.Itoa(maxSamplesPerRead))
+		}
+	}
+
+	if v, ok := parameters[readLenParam]; ok {
+		if n, ok := toInt(v); !ok || n < 2 || n > maxReadLen {
+			failures[readLenParam] = append(failures[readLenParam],
+				"ReadLen must be an integer 2.."+strconv.Itoa(maxReadLen))
+		}
+	}
+	switch strings.ToLower(getString(parameters, statusByteModeParam, statusRequired1)) {
+	case statusRequired1, statusNone, statusASCII:
+	default:
+		failures[statusByteModeParam] = append(failures[statusByteModeParam],
+			"StatusByteMode must be required-1, none or ascii")
+	}
+	for _, k := range []string{commandTerminatorParam, responseTerminatorParam} {
+		if !validTerminator(getString(parameters, k, termNUL)) {
+			failures[k] = append(failures[k], k+" must be one of "+terminatorNames())
+		}
+	}
+
+	if !validSmoothMode(getString(parameters, smoothModeParam, smoothNone)) {
+		failures[smoothModeParam] = append(failures[smoothModeParam],
+			"SmoothMode must be none, median, mean or median+mean")
+	}
+	for _, k := range []string{smoothMedianWindowParam, smoothMeanWindowParam} {
+		if v, ok := parameters[k]; ok {
+			if n, ok := toInt(v); !ok || n < 1 || n > maxSmoothWindow {
+				failures[k] = append(failures[k], k+" must be an integer 1.."+strconv.Itoa(maxSmoothWindow))
+			}
+		}
+	}
+
+	clampMin := getFloat(parameters, phClampMinParam, defaultPhClampMin)
+	clampMax := getFloat(parameters, phClampMaxParam, defaultPhClampMax)
+	if !(clampMin < clampMax) && (clampMin != 0 || clampMax != 0) {
+		failures[phClampMaxParam] = append(failures[phClampMaxParam],
+			"PhClampMax must be greater than PhClampMin (set both to 0 to disable clamping)")
+	}
+
 	// Without at least one anchor, calibration is effectively undefined for this driver.
 	if enabled == 0 {
 		failures["Obs"] = append(
@@ -193,38 +385,89 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
 	}
 
 	// Parse parameters
-	addr, _ := toInt(parameters[addressParam])
+	addr, _ := i2creg.ParseI2CAddress(parameters[addressParam])
 	debug := getBool(parameters, debugParam, false)
 
 	obs4 := getFloat(parameters, obs4Param, -1)
 	obs7 := getFloat(parameters, obs7Param, -1)
 	obs10 := getFloat(parameters, obs10Param, -1)
 
+	readCmd := getString(parameters, readCommandParam, defaultReadCommand)
+	preReadCmd := getString(parameters, preReadCommandParam, "")
+	postReadCmd := getString(parameters, postReadCommandParam, "")
+
+	smoothMode := strings.ToLower(getString(parameters, smoothModeParam, smoothNone))
+	smoothMedianN := getInt(parameters, smoothMedianWindowParam, defaultSmoothWindow)
+	smoothMeanN := getInt(parameters, smoothMeanWindowParam, defaultSmoothWindow)
+	smooth, err := newSmoother(smoothMode, smoothMedianN, smoothMeanN)
+	if err != nil {
+		return nil, err
+	}
+
+	// A nil hardware resource gives a config-only driver (see nobus).
+	bus, err := nobus.I2C("robotank_ph", hardwareResources)
+	if err != nil {
+		return nil, err
+	}
+	configOnly := nobus.Is(bus)
+
 	// Instantiate driver
 	d := &Driver{
-		addr:  byte(addr),
-		bus:   hardwareResources.(i2c.Bus),
+		addr:  addr,
+		bus:   bus,
 		debug: debug,
 
 		// Fixed, known-safe delay for Robo-Tank firmware. See driver.go.
 		delay: fixedReadDelay,
 
+		readCmd:     readCmd,
+		preReadCmd:  preReadCmd,
+		postReadCmd: postReadCmd,
+
+		samplesPerRead: getInt(parameters, samplesPerReadParam, defaultSamplesPerRead),
+
+		readLen:    getInt(parameters, readLenParam, defaultReadLen),
+		statusMode: strings.ToLower(getString(parameters, statusByteModeParam, statusRequired1)),
+
+		cmdTerm:  strings.ToLower(getString(parameters, commandTerminatorParam, defaultCommandTerminator)),
+		respTerm: strings.ToLower(getString(parameters, responseTerminatorParam, defaultResponseTerminator)),
+		trimFF:   getBool(parameters, trimTrailingFFParam, true),
+
+		readBoardTemp: getBool(parameters, readBoardTempParam, false),
+
+		smoothMode:    smoothMode,
+		smoothMedianN: smoothMedianN,
+		smoothMeanN:   smoothMeanN,
+		smooth:        smooth,
+
 		// Software calibration anchors (observed readings)
 		obs4:  obs4,
 		obs7:  obs7,
 		obs10: obs10,
 
+		phClampMin: getFloat(parameters, phClampMinParam, defaultPhClampMin),
+		phClampMax: getFloat(parameters, phClampMaxParam, defaultPhClampMax),
+
 		meta: f.meta,
 	}
 	d.pin = &phPin{d: d}
 
+	if !configOnly {
+		claim, err := i2creg.Claim(d.bus, d.addr, driverType)
+		if err != nil {
+			return nil, err
+		}
+		d.claim = claim
+	}
+
 	log.Printf(
-		"robotank_ph init addr=0x%02X delay=%v debug=%v obs(4=%.4f 7=%.4f 10=%.4f)",
-		d.addr, d.delay, d.debug, d.obs4, d.obs7, d.obs10,
+		"robotank_ph init addr=0x%02X delay=%v debug=%v obs(4=%.4f 7=%.4f 10=%.4f) read(pre=%q cmd=%q post=%q len=%d status=%s term=%s/%s trimFF=%v) samples=%d smooth=%s(median=%d mean=%d)",
+		d.addr, d.delay, d.debug, d.obs4, d.obs7, d.obs10, d.preReadCmd, d.readCmd, d.postReadCmd, d.readLen, d.statusMode,
+		d.cmdTerm, d.respTerm, d.trimFF, d.samplesPerRead, d.smoothMode, d.smoothMedianN, d.smoothMeanN,
 	)
 
 	// Optional: query firmware/ident string (only in debug mode)
-	if d.debug {
+	if d.debug && !configOnly {
 		if fw, err := d.Firmware(); err == nil {
 			log.Printf("robotank_ph addr=0x%02X H=%q", d.addr, fw)
 		} else {
@@ -263,6 +506,20 @@ func getBool(m map[string]interface{}, key string, def bool) bool {
 	return def
 }
 
+// getString reads a string parameter from the config map (trimmed).
+// Non-string values fall back to def.
+func getString(m map[string]interface{}, key string, def string) string {
+	v, ok := m[key]
+	if !ok {
+		return def
+	}
+	s, ok := v.(string)
+	if !ok {
+		return def
+	}
+	return strings.TrimSpace(s)
+}
+
 // getFloat reads a float parameter from the config map.
 // reef-pi may provide values as float64, int, or string.
 func getFloat(m map[string]interface{}, key string, def float64) float64 {
@@ -0,0 +1,67 @@
+// framing.go
+package robotank_ph
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+)
+
+// Command terminators and response framing by name. Robo-Tank firmware takes
+// "cmd\x00" and pads its answer with NUL and 0xFF; cousin ASCII boards expect
+// "\r" and end the text with CR or LF.
+const (
+	termNUL  = "nul"
+	termCR   = "cr"
+	termLF   = "lf"
+	termCRLF = "crlf"
+	termNone = "none"
+
+	defaultCommandTerminator  = termNUL
+	defaultResponseTerminator = termNUL
+)
+
+var terminators = map[string]string{
+	termNUL:  "\x00",
+	termCR:   "\r",
+	termLF:   "\n",
+	termCRLF: "\r\n",
+	termNone: "",
+}
+
+// terminatorNames lists the accepted terminator names for error messages.
+func terminatorNames() string {
+	names := make([]string, 0, len(terminators))
+	for k := range terminators {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// validTerminator reports whether name is a known terminator (case-insensitive).
+func validTerminator(name string) bool {
+	_, ok := terminators[strings.ToLower(name)]
+	return ok
+}
+
+// frameCommand appends the configured CommandTerminator to cmd.
+func (d *Driver) frameCommand(cmd string) []byte {
+	return []byte(cmd + terminators[d.cmdTerm])
+}
+
+// frameResponse cuts b at the first ResponseTerminator and, with TrimTrailingFF,
+// drops the 0xFF padding left after it.
+func (d *Driver) frameResponse(b []byte) []byte {
+	if t := terminators[d.respTerm]; t != "" {
+		if i := bytes.Index(b, []byte(t)); i >= 0 {
+			b = b[:i]
+		}
+	}
+	if d.trimFF {
+		for len(b) > 0 && b[len(b)-1] == 0xFF {
+			b = b[:len(b)-1]
+		}
+	}
+	return b
+}
@@ -0,0 +1,60 @@
+package robotank_ph
+
+import (
+	"testing"
+)
+
+func TestFrameCommand(t *testing.T) {
+	for _, c := range []struct {
+		term, want string
+	}{
+		{termNUL, "R\x00"},
+		{termCR, "R\r"},
+		{termLF, "R\n"},
+		{termCRLF, "R\r\n"},
+		{termNone, "R"},
+	} {
+		d := &Driver{cmdTerm: c.term}
+		if got := string(d.frameCommand("R")); got != c.want {
+			t.Errorf("%s: frameCommand = %q, want %q", c.term, got, c.want)
+		}
+	}
+}
+
+func TestFrameResponse(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		term   string
+		trimFF bool
+		in     string
+		want   string
+	}{
+		{"nul cuts padding", termNUL, true, "7.01\x00\xFF\xFF", "7.01"},
+		{"nul, text only", termNUL, true, "7.01", "7.01"},
+		{"cr", termCR, true, "7.01\r\x00\x00", "7.01"},
+		{"lf", termLF, true, "7.01\n\xFF", "7.01"},
+		{"crlf", termCRLF, true, "7.01\r\n\x00", "7.01"},
+		{"crlf ignores lone cr", termCRLF, false, "7.01\r", "7.01\r"},
+		{"none keeps everything", termNone, false, "7.01\x00\xFF", "7.01\x00\xFF"},
+		{"none trims ff", termNone, true, "7.01\xFF\xFF", "7.01"},
+		{"trim keeps inner ff", termNone, true, "7.\xFF01\xFF", "7.\xFF01"},
+		{"no trim", termNone, false, "7.01\xFF\xFF", "7.01\xFF\xFF"},
+		{"all ff", termNUL, true, "\xFF\xFF\xFF", ""},
+	} {
+		d := &Driver{respTerm: c.term, trimFF: c.trimFF}
+		if got := string(d.frameResponse([]byte(c.in))); got != c.want {
+			t.Errorf("%s: frameResponse(%q) = %q, want %q", c.name, c.in, got, c.want)
+		}
+	}
+}
+
+func TestValidTerminator(t *testing.T) {
+	for _, name := range []string{"nul", "CR", "Lf", "crlf", "none"} {
+		if !validTerminator(name) {
+			t.Errorf("validTerminator(%q) = false", name)
+		}
+	}
+	if validTerminator("tab") {
+		t.Error("validTerminator(tab) = true")
+	}
+}
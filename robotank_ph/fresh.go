@@ -0,0 +1,45 @@
+// fresh.go
+package robotank_ph
+
+import (
+	"log"
+	"math"
+
+	"github.com/reef-pi/hal"
+)
+
+// ValueFresh returns the calibrated pH of one real board transaction (under
+// d.mu), bypassing SamplesPerRead and the SmoothMode history. Calibration and
+// troubleshooting use it so an old reading in the smoother cannot leak into
+// the value you are looking at.
+func (p *phPin) ValueFresh() (float64, error) {
+	raw, err := p.d.readPH()
+	if err != nil {
+		return 0, err
+	}
+	return p.d.applyCalibration(raw), nil
+}
+
+// logAnchorsFresh takes one fresh reading per measurement and logs it as the
+// anchor (Obs4 / Obs7 / Obs10) for the nearest buffer. Anchors stay
+// config-driven; this only shows the live value to enter.
+func (p *phPin) logAnchorsFresh(ms []hal.Measurement) error {
+	for _, m := range ms {
+		raw, err := p.d.readPH()
+		if err != nil {
+			return err
+		}
+		param, truePH := obs7Param, truePH7
+		for _, a := range []struct {
+			param  string
+			truePH float64
+		}{{obs4Param, truePH4}, {obs10Param, truePH10}} {
+			if math.Abs(m.Expected-a.truePH) < math.Abs(m.Expected-truePH) {
+				param, truePH = a.param, a.truePH
+			}
+		}
+		log.Printf("robotank_ph addr=0x%02X calibration: expected=%.2f fresh observed=%.4f; set %s=%.4f (pH %.2f buffer)",
+			p.d.addr, m.Expected, raw, param, raw, truePH)
+	}
+	return nil
+}
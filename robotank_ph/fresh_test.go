@@ -0,0 +1,52 @@
+package robotank_ph
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/reef-pi/hal"
+)
+
+func TestValueFreshBypassesSamplesAndSmoother(t *testing.T) {
+	bus := &scriptBus{replies: []reply{okReply("7.0"), okReply("7.0"), okReply("7.0"), okReply("9.0")}}
+	d := newTestDriver(t, bus, map[string]interface{}{
+		samplesPerReadParam: 3,
+		smoothModeParam:     "mean",
+	})
+	if _, err := d.pin.Value(); err != nil {
+		t.Fatal(err)
+	}
+	v, err := d.pin.ValueFresh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One transaction, and the 7.0 history in the mean does not leak in.
+	if v != 9.0 || bus.reads != 4 {
+		t.Errorf("ValueFresh = %v after %d reads; want 9, 4", v, bus.reads)
+	}
+}
+
+func TestCalibrateLogsNearestAnchor(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	bus := &scriptBus{replies: []reply{okReply("4.12"), okReply("7.03"), okReply("9.91")}}
+	d := newTestDriver(t, bus, nil)
+	obs4, obs7, obs10 := d.obs4, d.obs7, d.obs10
+	err := d.pin.Calibrate([]hal.Measurement{{Expected: 4.01}, {Expected: 6.86}, {Expected: 10.5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"set Obs4=4.1200", "set Obs7=7.0300", "set Obs10=9.9100"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("log missing %q:\n%s", want, buf.String())
+		}
+	}
+	if d.obs4 != obs4 || d.obs7 != obs7 || d.obs10 != obs10 {
+		t.Errorf("Calibrate changed the anchors to %v/%v/%v", d.obs4, d.obs7, d.obs10)
+	}
+}
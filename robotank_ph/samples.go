@@ -0,0 +1,76 @@
+// samples.go
+package robotank_ph
+
+import (
+	"fmt"
+	"log"
+	"sort"
+)
+
+// Each sample costs one full read transaction (~300ms), so N stays small.
+const (
+	defaultSamplesPerRead = 1
+	maxSamplesPerRead     = 5
+)
+
+// sampleSet is the result of one multi-sample read.
+type sampleSet struct {
+	value  float64 // trimmed mean of the samples
+	spread float64 // max - min over all good samples (pH)
+	used   int     // samples averaged after dropping extremes
+	good   int     // samples read successfully
+	errors int     // samples that failed
+}
+
+// readSamples takes samplesPerRead readings, drops the lowest and highest when
+// at least 3 succeeded, and averages the rest. Failed samples are skipped; the
+// call fails only when no sample succeeds.
+func (d *Driver) readSamples() (sampleSet, error) {
+	n := d.samplesPerRead
+	if n < 1 {
+		n = 1
+	}
+
+	var s sampleSet
+	vals := make([]float64, 0, n)
+	var lastErr error
+	for i := 0; i < n; i++ {
+		v, err := d.readPH()
+		if err != nil {
+			s.errors++
+			lastErr = err
+			if d.debug {
+				log.Printf("robotank_ph addr=0x%02X sample %d/%d error: %v", d.addr, i+1, n, err)
+			}
+			continue
+		}
+		vals = append(vals, v)
+	}
+	if len(vals) == 0 {
+		if n == 1 {
+			return s, lastErr
+		}
+		return s, fmt.Errorf("all %d samples failed: %w", n, lastErr)
+	}
+
+	sort.Float64s(vals)
+	s.good = len(vals)
+	s.spread = vals[len(vals)-1] - vals[0]
+
+	kept := vals
+	if len(kept) >= 3 {
+		kept = kept[1 : len(kept)-1]
+	}
+	sum := 0.0
+	for _, v := range kept {
+		sum += v
+	}
+	s.used = len(kept)
+	s.value = sum / float64(len(kept))
+
+	if d.debug && n > 1 {
+		log.Printf("robotank_ph addr=0x%02X samples=%v kept=%d mean=%.4f spread=%.4f errors=%d",
+			d.addr, vals, s.used, s.value, s.spread, s.errors)
+	}
+	return s, nil
+}
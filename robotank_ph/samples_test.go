@@ -0,0 +1,55 @@
+package robotank_ph
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestReadSamples(t *testing.T) {
+	ioErr := reply{err: errors.New("remote i/o error")}
+	cases := []struct {
+		name      string
+		n         int
+		replies   []reply
+		want      float64
+		spread    float64
+		used      int
+		good      int
+		errs      int
+		errSubstr string
+	}{
+		{"single", 1, []reply{okReply("7.10")}, 7.10, 0, 1, 1, 0, ""},
+		{"two, no trim", 2, []reply{okReply("7.0"), okReply("7.4")}, 7.2, 0.4, 2, 2, 0, ""},
+		{"three, trimmed", 3, []reply{okReply("7.0"), okReply("7.6"), okReply("7.2")}, 7.2, 0.6, 1, 3, 0, ""},
+		{"five, spike dropped", 5, []reply{okReply("7.1"), okReply("9.0"), okReply("7.2"), okReply("7.0"), okReply("7.3")}, 7.2, 2.0, 3, 5, 0, ""},
+		{"partial failure", 3, []reply{okReply("7.0"), ioErr, okReply("7.4")}, 7.2, 0.4, 2, 2, 1, ""},
+		{"all failed", 3, []reply{ioErr}, 0, 0, 0, 0, 3, "all 3 samples failed"},
+		{"single failed", 1, []reply{ioErr}, 0, 0, 0, 0, 1, "remote i/o error"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := newTestDriver(t, &scriptBus{replies: c.replies}, nil)
+			d.samplesPerRead = c.n
+			s, err := d.readSamples()
+			if c.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), c.errSubstr) || !errors.Is(err, ErrBoardNotResponding) {
+					t.Fatalf("want error %q wrapping ErrBoardNotResponding, got %v", c.errSubstr, err)
+				}
+				if s.errors != c.errs {
+					t.Errorf("errors=%d, want %d", s.errors, c.errs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(s.value-c.want) > 1e-9 || math.Abs(s.spread-c.spread) > 1e-9 ||
+				s.used != c.used || s.good != c.good || s.errors != c.errs {
+				t.Errorf("got %+v; want value=%v spread=%v used=%d good=%d errors=%d",
+					s, c.want, c.spread, c.used, c.good, c.errs)
+			}
+		})
+	}
+}
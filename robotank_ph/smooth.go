@@ -0,0 +1,58 @@
+// smooth.go
+package robotank_ph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/reef-pi/drivers/filter"
+)
+
+// SmoothMode picks the smoothing applied to the board's pH before calibration.
+// "median+mean" runs a median (drops single-read spikes) and then a mean over
+// its output, the usual combination for cheap, noisy probes.
+//
+// The driver has no read cache: Value and Snapshot each read the board, and
+// each feeds its (multi-sample) result to the smoother once. Window sizes
+// therefore count calls, and a host that calls both per poll fills the windows
+// twice as fast. ValueFresh bypasses the smoother.
+const (
+	smoothNone       = "none"
+	smoothMedian     = "median"
+	smoothMean       = "mean"
+	smoothMedianMean = "median+mean"
+
+	defaultSmoothWindow = 5
+	maxSmoothWindow     = 50
+)
+
+func validSmoothMode(mode string) bool {
+	switch strings.ToLower(mode) {
+	case smoothNone, smoothMedian, smoothMean, smoothMedianMean:
+		return true
+	}
+	return false
+}
+
+// newSmoother builds the filter for mode with the given window sizes.
+func newSmoother(mode string, medianN, meanN int) (filter.Filter, error) {
+	switch strings.ToLower(mode) {
+	case smoothNone:
+		return filter.None(), nil
+	case smoothMedian:
+		return filter.Median(medianN), nil
+	case smoothMean:
+		return filter.Mean(meanN), nil
+	case smoothMedianMean:
+		return filter.Chain(filter.Median(medianN), filter.Mean(meanN)), nil
+	}
+	return nil, fmt.Errorf("unknown SmoothMode %q", mode)
+}
+
+// smoothed feeds raw into the smoother and returns the filtered pH. Call it
+// once per board read.
+func (d *Driver) smoothed(raw float64) float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.smooth.Add(raw)
+}
@@ -0,0 +1,60 @@
+package robotank_ph
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSmoothModeWiring(t *testing.T) {
+	// A spike at the third read: median drops it, mean spreads it.
+	reads := []reply{okReply("7.0"), okReply("7.0"), okReply("9.0")}
+	cases := []struct {
+		mode string
+		want float64
+	}{
+		{"none", 9.0},
+		{"Median", 7.0},
+		{"mean", 23.0 / 3},
+		{"median+mean", 7.0},
+	}
+	for _, c := range cases {
+		t.Run(c.mode, func(t *testing.T) {
+			d := newTestDriver(t, &scriptBus{replies: reads}, map[string]interface{}{
+				smoothModeParam:         c.mode,
+				smoothMedianWindowParam: 3,
+				smoothMeanWindowParam:   3,
+			})
+			var v float64
+			for range reads {
+				var err error
+				if v, err = d.pin.Value(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if math.Abs(v-c.want) > 1e-9 {
+				t.Errorf("Value after spike = %v, want %v", v, c.want)
+			}
+		})
+	}
+
+	// Snapshot reads the board too and feeds the same smoother.
+	d := newTestDriver(t, &scriptBus{replies: reads}, map[string]interface{}{
+		smoothModeParam: "mean", smoothMeanWindowParam: 3,
+	})
+	d.pin.Value()
+	d.pin.Value()
+	s, err := d.pin.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Signals["observed"].Now != 9.0 || math.Abs(s.Signals["smoothed"].Now-23.0/3) > 1e-9 {
+		t.Errorf("snapshot observed=%v smoothed=%v; want 9, %v",
+			s.Signals["observed"].Now, s.Signals["smoothed"].Now, 23.0/3)
+	}
+}
+
+func TestNewSmootherRejectsUnknownMode(t *testing.T) {
+	if _, err := newSmoother("ewma", 3, 3); err == nil {
+		t.Error("newSmoother(ewma) must fail")
+	}
+}
@@ -0,0 +1,40 @@
+// temperature.go
+package robotank_ph
+
+import (
+	"time"
+)
+
+// Injected temperatures older than this are not used for implied_mv_at_temp.
+const tempStaleAfter = 5 * time.Minute
+
+// SetTemperatureC stores an injected temperature (°C). The board compensates
+// internally at its own fixed slope, so readings are never changed; the value
+// only feeds the implied_mv_at_temp diagnostic.
+func (p *phPin) SetTemperatureC(tempC float64) {
+	p.d.mu.Lock()
+	p.d.tempC = tempC
+	p.d.tempUpdatedAt = time.Now()
+	p.d.mu.Unlock()
+}
+
+// injectedTemp returns the last injected temperature, ok=false when none was
+// injected or it is stale.
+func (d *Driver) injectedTemp() (tempC float64, at time.Time, ok bool) {
+	d.mu.Lock()
+	tempC, at = d.tempC, d.tempUpdatedAt
+	d.mu.Unlock()
+	return tempC, at, !at.IsZero() && time.Since(at) <= tempStaleAfter
+}
+
+// nernstSlopeMvPerPH is the electrode slope (mV/pH) at tempC: 59.16 at 25 °C,
+// scaling with absolute temperature.
+func nernstSlopeMvPerPH(tempC float64) float64 {
+	return phSlopeMvPerPH * (tempC + 273.15) / 298.15
+}
+
+// phToImpliedMvAt is phToImpliedMv with the slope of a temperature-correct
+// electrode at tempC instead of the board's fixed 25 °C slope.
+func phToImpliedMvAt(ph, tempC float64) float64 {
+	return (7.0 - ph) * nernstSlopeMvPerPH(tempC)
+}
@@ -0,0 +1,57 @@
+package robotank_ph
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNernstSlope(t *testing.T) {
+	if s := nernstSlopeMvPerPH(25); math.Abs(s-phSlopeMvPerPH) > 1e-9 {
+		t.Errorf("slope at 25°C = %v, want %v", s, phSlopeMvPerPH)
+	}
+	// 59.16 * 283.15 / 298.15
+	if s := nernstSlopeMvPerPH(10); math.Abs(s-56.1837) > 1e-3 {
+		t.Errorf("slope at 10°C = %v, want ~56.184", s)
+	}
+	if mv := phToImpliedMvAt(8, 25); math.Abs(mv-phToImpliedMv(8)) > 1e-9 {
+		t.Errorf("implied mV at 25°C = %v, want %v", mv, phToImpliedMv(8))
+	}
+	if mv := phToImpliedMvAt(6, 10); math.Abs(mv-56.1837) > 1e-3 {
+		t.Errorf("implied mV of pH 6 at 10°C = %v, want ~56.184", mv)
+	}
+	if mv := phToImpliedMvAt(7, 10); mv != 0 {
+		t.Errorf("pH 7 must imply 0 mV at any temperature, got %v", mv)
+	}
+}
+
+func TestSnapshotTemperature(t *testing.T) {
+	d := newTestDriver(t, &scriptBus{replies: []reply{okReply("8.0")}}, nil)
+	d.pin.SetTemperatureC(10)
+	s, err := d.pin.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Signals["implied_mv_at_temp"].Now; math.Abs(got+56.1837) > 1e-3 {
+		t.Errorf("implied_mv_at_temp = %v, want ~-56.184", got)
+	}
+
+	// A stale temperature drops the signal and says why.
+	d.mu.Lock()
+	d.tempUpdatedAt = time.Now().Add(-tempStaleAfter - time.Second)
+	d.mu.Unlock()
+	if s, err = d.pin.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Signals["implied_mv_at_temp"]; ok {
+		t.Error("stale temperature must not produce implied_mv_at_temp")
+	}
+	stale := false
+	for _, n := range s.Notes {
+		stale = stale || strings.Contains(n, "stale")
+	}
+	if !stale {
+		t.Errorf("missing stale note: %q", s.Notes)
+	}
+}
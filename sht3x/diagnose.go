@@ -0,0 +1,55 @@
+// diagnose.go
+//
+// Health report (see package diag).
+//
+// The liveness check reads the status register (CRC checked), which changes
+// nothing on the sensor and needs no measurement.
+//
+package sht3x
+
+import (
+	"github.com/reef-pi/drivers/diag"
+)
+
+// Status register bits.
+const (
+	statusHeaterOn    = 1 << 13
+	statusResetSeen   = 1 << 4
+	statusCmdFailed   = 1 << 1
+	statusWriteCRCBad = 1 << 0
+)
+
+var _ diag.Diagnoser = (*Driver)(nil)
+
+// ReadStatus returns the sensor status register.
+func (d *SHT31D) ReadStatus() (uint16, error) {
+	if err := d.bus.WriteBytes(d.addr, CMD_READ_STATUS); err != nil {
+		return 0, err
+	}
+	data, err := d.read(1)
+	if err != nil {
+		return 0, err
+	}
+	return data[0], nil
+}
+
+// Diagnose reads the status register.
+func (d *Driver) Diagnose() diag.Diagnostics {
+	r := diag.New()
+
+	status, err := d.sensor.ReadStatus()
+	if !r.Check("read status", err) {
+		return r
+	}
+	r.Infof("status=0x%04X", status)
+	if status&statusHeaterOn != 0 {
+		r.Warnf("heater is on; temperature reads high and humidity low")
+	}
+	if status&statusResetSeen != 0 {
+		r.Infof("reset detected since the status was last cleared (normal after power-up)")
+	}
+	if status&(statusCmdFailed|statusWriteCRCBad) != 0 {
+		r.Warnf("last command was not processed (status 0x%04X)", status)
+	}
+	return r
+}
@@ -9,6 +9,7 @@ import (
 type Driver struct {
 	meta     hal.Metadata
 	channels []hal.AnalogInputPin
+	sensor   *SHT31D
 }
 
 func NewDriver(addr byte, bus i2c.Bus, meta hal.Metadata) (*Driver, error) {
@@ -27,6 +28,7 @@ func NewDriver(addr byte, bus i2c.Bus, meta hal.Metadata) (*Driver, error) {
 	return &Driver{
 		meta:     meta,
 		channels: []hal.AnalogInputPin{ch1, ch2},
+		sensor:   s,
 	}, nil
 }
 
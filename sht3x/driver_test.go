@@ -1,8 +1,10 @@
 package sht3x
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/reef-pi/drivers/diag"
 	"github.com/reef-pi/hal"
 	"github.com/reef-pi/rpi/i2c"
 )
@@ -65,3 +67,24 @@ func TestDriver(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestDiagnose(t *testing.T) {
+	bus := i2c.MockBus()
+	driver, err := Factory().NewDriver(params, bus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := driver.(*Driver)
+
+	status := []byte{0x20, 0x10} // heater on, reset seen
+	bus.Bytes = append(status, crc(0xFF, status))
+	r := d.Diagnose()
+	if !r.OK || len(r.Messages) != 4 || r.Messages[1] != "status=0x2010" || !strings.HasPrefix(r.Messages[2], diag.WarningPrefix) {
+		t.Errorf("heater on: %+v", r)
+	}
+
+	bus.Bytes = []byte{0x00, 0x00, 0x00}
+	if r := d.Diagnose(); r.OK || r.LastError == "" {
+		t.Errorf("CRC mismatch should not be OK: %+v", r)
+	}
+}
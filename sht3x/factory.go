@@ -5,8 +5,9 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/reef-pi/drivers/i2creg"
+	"github.com/reef-pi/drivers/nobus"
 	"github.com/reef-pi/hal"
-	"github.com/reef-pi/rpi/i2c"
 )
 
 const addressParam = "Address"
@@ -52,14 +53,8 @@ func (f *factory) GetParameters() []hal.ConfigParameter {
 func (f *factory) ValidateParameters(parameters map[string]interface{}) (bool, map[string][]string) {
 	var failures = make(map[string][]string)
 	if v, ok := parameters[addressParam]; ok {
-		val, ok := hal.ConvertToInt(v)
-		if !ok {
-			failure := fmt.Sprint(addressParam, " is not a number. ", v, " was received.")
-			failures[addressParam] = append(failures[addressParam], failure)
-		}
-		if val <= 0 || val >= 256 {
-			failure := fmt.Sprint(addressParam, " is out of range (1 - 255). ", v, " was received.")
-			failures[addressParam] = append(failures[addressParam], failure)
+		if _, err := i2creg.ParseI2CAddress(v); err != nil {
+			failures[addressParam] = append(failures[addressParam], err.Error())
 		}
 	} else {
 		failure := fmt.Sprint(addressParam, " is a required parameter, but was not received.")
@@ -73,8 +68,10 @@ func (f *factory) NewDriver(parameters map[string]interface{}, hardwareResources
 	if valid, failures := f.ValidateParameters(parameters); !valid {
 		return nil, errors.New(hal.ToErrorString(failures))
 	}
-	intAddress, _ := hal.ConvertToInt(parameters[addressParam])
-	address := byte(intAddress)
-	bus := hardwareResources.(i2c.Bus)
+	address, _ := i2creg.ParseI2CAddress(parameters[addressParam])
+	bus, err := nobus.I2C("sht3x", hardwareResources)
+	if err != nil {
+		return nil, err
+	}
 	return NewDriver(address, bus, f.meta)
 }
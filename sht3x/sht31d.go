@@ -17,6 +17,7 @@ var (
 	CMD_ART                 = []byte{0x2B, 0x32} // Activate "accelerated response time"
 	CMD_BREAK               = []byte{0x30, 0x93} // Interrupt "periodic acqusition mode" and return to "single shot mode"
 	CMD_RESET               = []byte{0x30, 0xA2} // Soft reset command
+	CMD_READ_STATUS         = []byte{0xF3, 0x2D} // Read the status register
 
 )
 